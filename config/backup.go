@@ -0,0 +1,51 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"gopkg.in/yaml.v3"
+)
+
+// ExportYAML 导出配置文件, redact 为 true 时隐藏密钥
+func (conf Config) ExportYAML(redact bool) ([]byte, error) {
+	if redact {
+		conf = conf.Redacted()
+	}
+	return yaml.Marshal(&conf)
+}
+
+// ImportYAML 解析并校验导入的配置文件, 校验通过后需调用 SaveConfig 保存
+func ImportYAML(byt []byte) (conf Config, err error) {
+	if isEncrypted(byt) {
+		return conf, errors.New(util.LogStr("不支持导入已加密的配置文件"))
+	}
+	// 隐藏了密钥的配置无法恢复
+	if bytes.Contains(byt, []byte(redactedMask)) {
+		return conf, errors.New(util.LogStr("配置中包含已隐藏的密钥, 请导出包含密钥的配置后再导入"))
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(byt))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&conf); err != nil {
+		return conf, errors.New(util.LogStr("配置文件格式不正确: %s", err))
+	}
+	return conf, conf.validateImport()
+}
+
+// validateImport 校验导入的配置, 与页面保存时的校验相同
+func (conf *Config) validateImport() (err error) {
+	// 密码可为明文
+	for _, pwd := range []*string{&conf.Password, &conf.ReadOnlyPassword, &conf.BasicAuthPassword} {
+		if *pwd != "" && !util.IsHashedPassword(*pwd) {
+			if *pwd, err = conf.CheckPassword(*pwd); err != nil {
+				return err
+			}
+		}
+	}
+	if errs := conf.Validate(); len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestExportImportYAML 测试导出后再导入, 隐藏了密钥的配置不能导入
+func TestExportImportYAML(t *testing.T) {
+	conf := Config{DnsConf: []DnsConfig{{Name: "home", DNS: DNS{Name: "cloudflare", Secret: "token-value"}}}}
+	conf.Username = "admin"
+	conf.Password, _ = util.HashPassword("Xk9#mQ2$vL7pR4")
+
+	byt, err := conf.ExportYAML(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported, err := ImportYAML(byt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported.DnsConf[0].DNS.Secret != "token-value" || imported.Password != conf.Password {
+		t.Errorf("导入后的配置不正确: %+v", imported)
+	}
+
+	redacted, _ := conf.ExportYAML(true)
+	if strings.Contains(string(redacted), "token-value") {
+		t.Error("隐藏密钥时不应包含密钥")
+	}
+	if _, err := ImportYAML(redacted); err == nil {
+		t.Error("隐藏了密钥的配置期待返回异常")
+	}
+}
+
+// TestImportYAMLValidate 测试导入时校验配置
+func TestImportYAMLValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"正确", "dnsconf:\n  - dns:\n      name: alidns\n      id: key\n      secret: secret\n", false},
+		{"未填写密钥", "dnsconf:\n  - dns:\n      name: alidns\n", true},
+		{"未知配置项", "dnsconf: []\nunknown: 1\n", true},
+		{"未选择服务商", "dnsconf:\n  - name: home\n", true},
+		{"维护时段不正确", "maintenancewindow: \"02:00\"\n", true},
+		{"密码不安全", "user:\n  username: admin\n  password: \"123\"\n", true},
+		{"格式不正确", "dnsconf: [", true},
+	}
+	for _, tt := range tests {
+		if _, err := ImportYAML([]byte(tt.content)); (err != nil) != tt.wantErr {
+			t.Errorf("%s: 期待异常 %v, 得到 %v", tt.name, tt.wantErr, err)
+		}
+	}
+
+	// 明文密码导入时加密
+	conf, err := ImportYAML([]byte("user:\n  username: admin\n  password: \"Xk9#mQ2$vL7pR4\"\n"))
+	if err != nil || !util.IsHashedPassword(conf.Password) {
+		t.Errorf("期待密码已加密, 得到 %s %v", conf.Password, err)
+	}
+}
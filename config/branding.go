@@ -0,0 +1,42 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// defaultTitle 默认的页面标题
+const defaultTitle = "DDNS-GO"
+
+// faviconExts 支持的图标格式
+var faviconExts = map[string]bool{".ico": true, ".png": true, ".svg": true, ".gif": true, ".jpg": true, ".jpeg": true}
+
+// GetTitle 获得页面标题, 为空时使用默认值
+func (conf *Config) GetTitle() string {
+	if title := strings.TrimSpace(conf.Title); title != "" {
+		return title
+	}
+	return defaultTitle
+}
+
+// CheckFavicon 校验自定义图标的路径, 需为存在的图片文件
+func CheckFavicon(path string) error {
+	if path == "" {
+		return nil
+	}
+	if !faviconExts[strings.ToLower(filepath.Ext(path))] {
+		return errors.New(util.LogStr("不支持的图标格式: %s", path))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return errors.New(util.LogStr("图标文件不存在: %s", path))
+	}
+	if info.IsDir() {
+		return errors.New(util.LogStr("图标路径不是文件: %s", path))
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGetTitle 测试页面标题为空时使用默认值
+func TestGetTitle(t *testing.T) {
+	if title := (&Config{}).GetTitle(); title != "DDNS-GO" {
+		t.Errorf("期待 DDNS-GO, 得到 %s", title)
+	}
+	if title := (&Config{Title: " My DDNS "}).GetTitle(); title != "My DDNS" {
+		t.Errorf("期待 My DDNS, 得到 %s", title)
+	}
+}
+
+// TestCheckFavicon 测试校验自定义图标的路径
+func TestCheckFavicon(t *testing.T) {
+	dir := t.TempDir()
+	png := filepath.Join(dir, "logo.PNG")
+	txt := filepath.Join(dir, "logo.txt")
+	os.WriteFile(png, []byte("png"), 0600)
+	os.WriteFile(txt, []byte("txt"), 0600)
+	os.Mkdir(filepath.Join(dir, "dir.ico"), 0700)
+
+	tests := []struct {
+		path    string
+		wantErr bool
+	}{
+		{"", false},
+		{png, false},
+		{txt, true},
+		{filepath.Join(dir, "missing.ico"), true},
+		{filepath.Join(dir, "dir.ico"), true},
+	}
+	for _, tt := range tests {
+		if err := CheckFavicon(tt.path); (err != nil) != tt.wantErr {
+			t.Errorf("%s 期待异常 %v, 得到 %v", tt.path, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"context"
+	"net/netip"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// compareTimeout 与服务商比对前查询DNS服务器的超时时间
+var compareTimeout = 5 * time.Second
+
+// compareResolved 与服务商比对前查询配置的DNS服务器, 只返回未解析到当前IP的域名
+// 查询失败时仍与服务商比对, 全部已是当前IP时返回的IP为空, 不调用服务商接口
+// forced 为 true 时为达到缓存次数或保存配置后的强制比对, 不查询, 以便应用TTL、备注等修改
+func (domains *Domains) compareResolved(network string, ipAddr string, forced bool, domainArr []*Domain) (string, []*Domain) {
+	addr, err := netip.ParseAddr(ipAddr)
+	if domains.CompareDNS == "" || forced || err != nil {
+		return ipAddr, domainArr
+	}
+
+	resolver := util.NewResolver(domains.CompareDNS)
+	var pending []*Domain
+	for _, domain := range domainArr {
+		ctx, cancel := context.WithTimeout(context.Background(), compareTimeout)
+		addrs, err := resolver.LookupNetIP(ctx, network, domain.ToASCII())
+		cancel()
+		if err != nil {
+			util.Log("查询域名 %s 失败, 将与DNS服务商比对. 异常信息: %s", domain, err)
+			pending = append(pending, domain)
+			continue
+		}
+		// 只有一条记录且为当前IP时跳过, 多条记录时仍交由服务商处理
+		if len(addrs) == 1 && addrs[0].Unmap() == addr.Unmap() {
+			util.Log("DNS服务器 %s 返回域名 %s 已解析到 %s, 跳过更新", domains.CompareDNS, domain, ipAddr)
+			domain.UpdateStatus = UpdatedNothing
+			continue
+		}
+		pending = append(pending, domain)
+	}
+
+	if len(pending) == 0 {
+		return "", domainArr
+	}
+	return ipAddr, pending
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"net"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startCompareDNSServer 启动 UDP DNS 服务器, 按域名返回 A 记录, 未配置的域名返回 NXDOMAIN
+func startCompareDNSServer(t *testing.T, records map[string][][4]byte) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if query.Unpack(buf[:n]) != nil || len(query.Questions) != 1 {
+				continue
+			}
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, RCode: dnsmessage.RCodeSuccess},
+				Questions: query.Questions,
+			}
+			q := query.Questions[0]
+			ips, ok := records[q.Name.String()]
+			if !ok {
+				resp.RCode = dnsmessage.RCodeNameError
+			}
+			if q.Type == dnsmessage.TypeA {
+				for _, ip := range ips {
+					resp.Answers = append(resp.Answers, dnsmessage.Resource{
+						Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: q.Class, TTL: 60},
+						Body:   &dnsmessage.AResource{A: ip},
+					})
+				}
+			}
+			packed, _ := resp.Pack()
+			conn.WriteTo(packed, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+// TestCompareResolved 测试已解析到当前IP的域名跳过更新, 查询失败时仍与服务商比对
+func TestCompareResolved(t *testing.T) {
+	addr := startCompareDNSServer(t, map[string][][4]byte{
+		"same.example.com.":  {{192, 0, 2, 1}},
+		"other.example.com.": {{192, 0, 2, 2}},
+		"multi.example.com.": {{192, 0, 2, 1}, {192, 0, 2, 3}},
+	})
+
+	defer func(force bool) { util.ForceCompareGlobal = force }(util.ForceCompareGlobal)
+	util.ForceCompareGlobal = false
+
+	same := &Domain{DomainName: "example.com", SubDomain: "same"}
+	other := &Domain{DomainName: "example.com", SubDomain: "other"}
+	multi := &Domain{DomainName: "example.com", SubDomain: "multi"}
+	missing := &Domain{DomainName: "example.com", SubDomain: "missing"}
+	domains := &Domains{
+		Ipv4Addr:    "192.0.2.1",
+		Ipv4Cache:   &util.IpCache{},
+		Ipv4Domains: []*Domain{same, other, multi, missing},
+		CompareDNS:  addr,
+	}
+
+	ipAddr, pending := domains.GetNewIpResult("A")
+	if ipAddr != "192.0.2.1" {
+		t.Errorf("期待 192.0.2.1, 得到 %s", ipAddr)
+	}
+	if len(pending) != 3 || pending[0] != other || pending[1] != multi || pending[2] != missing {
+		t.Errorf("期待与服务商比对 other multi missing, 得到 %v", pending)
+	}
+	if same.UpdateStatus != UpdatedNothing {
+		t.Errorf("期待 %s 未改变, 得到 %s", same, same.UpdateStatus)
+	}
+
+	// 全部已解析到当前IP时不调用服务商接口
+	domains = &Domains{
+		Ipv4Addr:    "192.0.2.1",
+		Ipv4Cache:   &util.IpCache{},
+		Ipv4Domains: []*Domain{{DomainName: "example.com", SubDomain: "same"}},
+		CompareDNS:  addr,
+	}
+	if ipAddr, _ := domains.GetNewIpResult("A"); ipAddr != "" {
+		t.Errorf("期待IP为空, 得到 %s", ipAddr)
+	}
+}
+
+// TestCompareResolvedForced 测试达到缓存次数或保存配置后的强制比对不查询DNS服务器, 以便应用TTL等修改
+func TestCompareResolvedForced(t *testing.T) {
+	addr := startCompareDNSServer(t, map[string][][4]byte{
+		"same.example.com.": {{192, 0, 2, 1}},
+	})
+	defer func(force bool) { util.ForceCompareGlobal = force }(util.ForceCompareGlobal)
+	util.ForceCompareGlobal = false
+
+	// IP未改变, 达到缓存次数
+	domains := &Domains{
+		Ipv4Addr:    "192.0.2.1",
+		Ipv4Cache:   &util.IpCache{Addr: "192.0.2.1", Times: 1},
+		Ipv4Domains: []*Domain{{DomainName: "example.com", SubDomain: "same"}},
+		CompareDNS:  addr,
+	}
+	if ipAddr, pending := domains.GetNewIpResult("A"); ipAddr != "192.0.2.1" || len(pending) != 1 {
+		t.Errorf("达到缓存次数时期待与服务商比对, 得到 %q %v", ipAddr, pending)
+	}
+
+	// 保存配置后重置缓存
+	util.ForceCompareGlobal = true
+	domains.Ipv4Cache = &util.IpCache{}
+	if ipAddr, pending := domains.GetNewIpResult("A"); ipAddr != "192.0.2.1" || len(pending) != 1 {
+		t.Errorf("保存配置后期待与服务商比对, 得到 %q %v", ipAddr, pending)
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestRedacted 测试隐藏密钥且不修改原配置
+func TestRedacted(t *testing.T) {
+	conf := Config{DnsConf: []DnsConfig{{DNS: DNS{Name: "cloudflare", Secret: "token-value"}}}}
+	conf.Password = "hashed"
+
+	redacted := conf.Redacted()
+	if redacted.DnsConf[0].DNS.Secret != redactedMask || redacted.Password != redactedMask {
+		t.Errorf("期待已隐藏密钥, 得到 %s %s", redacted.DnsConf[0].DNS.Secret, redacted.Password)
+	}
+	if conf.DnsConf[0].DNS.Secret != "token-value" || conf.Password != "hashed" {
+		t.Errorf("原配置不应被修改")
+	}
+}
+
+// TestCheckPushedAddr 测试校验推送的IP
+func TestCheckPushedAddr(t *testing.T) {
+	data := []struct {
+		addr     string
+		addrType string
+		ok       bool
+	}{
+		{"1.2.3.4", "IPv4", true},
+		{"2001:db8::1", "IPv6", true},
+		{"2001:db8::1", "IPv4", false},
+		{"1.2.3.4", "IPv6", false},
+		{"127.0.0.1", "IPv4", false},
+		{"example.com", "IPv4", false},
+	}
+
+	for _, d := range data {
+		if err := CheckPushedAddr(d.addr, d.addrType); (err == nil) != d.ok {
+			t.Errorf("%s %s 期待 %v, 得到 %v", d.addrType, d.addr, d.ok, err)
+		}
+	}
+}
+
+// TestGetIPv6Zone 测试命令输出中保留地址后的区域ID
+func TestGetIPv6Zone(t *testing.T) {
+	data := map[string]string{
+		"%eth0\n":      "%eth0",
+		"%wlan0.1 dev": "%wlan0.1",
+		"%12":          "%12",
+		"/64 scope":    "",
+		" %eth0":       "",
+		"":             "",
+	}
+	for rest, expected := range data {
+		if got := getIPv6Zone(rest); got != expected {
+			t.Errorf("%q 期待 %q, 得到 %q", rest, expected, got)
+		}
+	}
+}
+
+// TestCompatibleDnsConf 测试兼容之前的配置文件时不保存, 密码未加密时 CompatibleConfig 才加密并保存
+func TestCompatibleDnsConf(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ddns_go_config.yaml")
+	content := "password: plain\ndns:\n    name: cloudflare\n"
+	os.WriteFile(path, []byte(content), 0600)
+	t.Setenv(util.ConfigFilePathENV, path)
+	defer func() { cache.ConfigSingle = nil }()
+
+	conf := &Config{}
+	conf.Password = "plain"
+	conf.CompatibleDnsConf()
+	if len(conf.DnsConf) != 1 || conf.DnsConf[0].DNS.Name != "cloudflare" {
+		t.Fatalf("期待兼容之前的DNS配置, 得到 %+v", conf.DnsConf)
+	}
+	if byt, _ := os.ReadFile(path); string(byt) != content || conf.Password != "plain" {
+		t.Errorf("期待不修改配置文件, 得到\n%s", byt)
+	}
+
+	conf.CompatibleConfig()
+	if byt, _ := os.ReadFile(path); string(byt) == content || !util.IsHashedPassword(conf.Password) {
+		t.Errorf("期待加密密码并保存, 得到\n%s", byt)
+	}
+}
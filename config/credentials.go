@@ -0,0 +1,60 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"gopkg.in/yaml.v3"
+)
+
+// CredentialsOnlyChanged 是否只修改了DNS服务商的凭证(ID/Secret)
+// 服务商实例每次更新时重新创建, 只修改凭证时无需重置IP缓存等运行状态, 下次更新时使用新凭证
+func CredentialsOnlyChanged(old *Config, new *Config) bool {
+	if len(old.DnsConf) != len(new.DnsConf) {
+		return false
+	}
+	changed := false
+	for i := range old.DnsConf {
+		if old.DnsConf[i].DNS.ID != new.DnsConf[i].DNS.ID || old.DnsConf[i].DNS.Secret != new.DnsConf[i].DNS.Secret {
+			changed = true
+		}
+	}
+	if !changed {
+		return false
+	}
+
+	// 按保存的内容比较, 忽略空数组与 nil 的区别
+	oldByt, err1 := yaml.Marshal(withoutCredentials(old))
+	newByt, err2 := yaml.Marshal(withoutCredentials(new))
+	return err1 == nil && err2 == nil && bytes.Equal(oldByt, newByt)
+}
+
+// withoutCredentials 清空凭证后的配置, 不修改原配置
+func withoutCredentials(conf *Config) Config {
+	c := *conf
+	c.DnsConf = slices.Clone(conf.DnsConf)
+	for i := range c.DnsConf {
+		c.DnsConf[i].DNS.ID = ""
+		c.DnsConf[i].DNS.Secret = ""
+	}
+	return c
+}
+
+// SetCredentials 修改第 index 个配置(从0开始)的凭证, 为空时不修改
+func (conf *Config) SetCredentials(index int, id string, secret string) error {
+	if index < 0 || index >= len(conf.DnsConf) {
+		return errors.New(util.LogStr("第 %s 个配置不存在", util.Ordinal(index+1, conf.Lang)))
+	}
+	if id == "" && secret == "" {
+		return errors.New(util.LogStr("未填写凭证"))
+	}
+	if id != "" {
+		conf.DnsConf[index].DNS.ID = id
+	}
+	if secret != "" {
+		conf.DnsConf[index].DNS.Secret = secret
+	}
+	return nil
+}
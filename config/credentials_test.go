@@ -0,0 +1,43 @@
+package config
+
+import "testing"
+
+// TestCredentialsOnlyChanged 测试只修改凭证时保留运行状态
+func TestCredentialsOnlyChanged(t *testing.T) {
+	newConf := func(id string, secret string, domains []string) *Config {
+		conf := &Config{DnsConf: []DnsConfig{{DNS: DNS{Name: "cloudflare", ID: id, Secret: secret}}}}
+		conf.DnsConf[0].Ipv4.Domains = domains
+		return conf
+	}
+	old := newConf("", "old-token", nil)
+
+	if !CredentialsOnlyChanged(old, newConf("", "new-token", []string{})) {
+		t.Error("只修改了凭证, 期待为 true")
+	}
+	if CredentialsOnlyChanged(old, newConf("", "old-token", nil)) {
+		t.Error("未修改凭证, 期待为 false")
+	}
+	if CredentialsOnlyChanged(old, newConf("", "new-token", []string{"example.com"})) {
+		t.Error("同时修改了域名, 期待为 false")
+	}
+	if old.DnsConf[0].DNS.Secret != "old-token" {
+		t.Error("原配置不应被修改")
+	}
+}
+
+// TestSetCredentials 测试修改凭证, 为空时不修改
+func TestSetCredentials(t *testing.T) {
+	conf := &Config{DnsConf: []DnsConfig{{DNS: DNS{Name: "alidns", ID: "id", Secret: "secret"}}}}
+	if err := conf.SetCredentials(0, "", "new-secret"); err != nil {
+		t.Fatal(err)
+	}
+	if conf.DnsConf[0].DNS.ID != "id" || conf.DnsConf[0].DNS.Secret != "new-secret" {
+		t.Errorf("期待 id new-secret, 得到 %s %s", conf.DnsConf[0].DNS.ID, conf.DnsConf[0].DNS.Secret)
+	}
+	if err := conf.SetCredentials(1, "id", "secret"); err == nil {
+		t.Error("配置不存在时期待返回异常")
+	}
+	if err := conf.SetCredentials(0, "", ""); err == nil {
+		t.Error("未填写凭证时期待返回异常")
+	}
+}
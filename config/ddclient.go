@@ -0,0 +1,317 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// 导入 ddclient 配置时默认的获取IP接口
+const (
+	ddclientIpv4URL = "https://api.ipify.org, https://ddns.oray.com/checkip, https://ip.3322.net, https://4.ipw.cn"
+	ddclientIpv6URL = "https://api64.ipify.org, https://speed.neu6.edu.cn/getIP.php, https://v6.ident.me, https://6.ipw.cn"
+)
+
+// ddclientKeys 支持的 ddclient 配置项
+var ddclientKeys = map[string]bool{
+	"protocol": true, "server": true, "script": true, "ssl": true,
+	"login": true, "password": true, "apikey": true, "secretapikey": true,
+	"zone": true, "ttl": true,
+	"use": true, "usev4": true, "usev6": true,
+	"web": true, "webv4": true, "webv6": true,
+	"if": true, "ifv4": true, "ifv6": true,
+	"cmd": true, "cmdv4": true, "cmdv6": true,
+}
+
+// ImportDdclient 解析 ddclient.conf, 将支持的协议转换为 ddns-go 的配置
+// 凭证及获取IP方式相同的域名合并为一个配置, 不支持的协议及配置项输出日志后忽略
+// https://ddclient.net/usage.html
+func ImportDdclient(content string) (dnsConfs []DnsConfig) {
+	globals := map[string]string{}
+	index := map[string]int{}
+	logged := map[string]bool{}
+
+	for _, line := range ddclientLines(content) {
+		settings := map[string]string{}
+		var hosts []string
+		for _, token := range ddclientTokens(line) {
+			key, value, ok := strings.Cut(token, "=")
+			if !ok {
+				hosts = append(hosts, token)
+				continue
+			}
+			key = strings.ToLower(key)
+			if !ddclientKeys[key] {
+				if !logged[key] {
+					logged[key] = true
+					util.Log("不支持 ddclient 的配置项 %s, 已忽略", key)
+				}
+				continue
+			}
+			settings[key] = ddclientUnquote(value)
+		}
+
+		// 没有主机名的行为之后的默认值
+		if len(hosts) == 0 {
+			for k, v := range settings {
+				globals[k] = v
+			}
+			continue
+		}
+		for k, v := range globals {
+			if _, ok := settings[k]; !ok {
+				settings[k] = v
+			}
+		}
+
+		for _, host := range hosts {
+			dc, domain, ok := ddclientToDnsConfig(settings, host)
+			if !ok {
+				continue
+			}
+			// 凭证及获取IP方式相同时合并
+			key, _ := json.Marshal(dc)
+			i, found := index[string(key)]
+			if !found {
+				i = len(dnsConfs)
+				index[string(key)] = i
+				dnsConfs = append(dnsConfs, dc)
+			}
+			if dc.Ipv4.Enable {
+				dnsConfs[i].Ipv4.Domains = append(dnsConfs[i].Ipv4.Domains, domain)
+			}
+			if dc.Ipv6.Enable {
+				dnsConfs[i].Ipv6.Domains = append(dnsConfs[i].Ipv6.Domains, domain)
+			}
+		}
+	}
+	return
+}
+
+// ddclientLines 去除注释并合并以 \ 结尾的行
+func ddclientLines(content string) (lines []string) {
+	current := ""
+	for _, line := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n") {
+		if i := ddclientCommentIndex(line); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if strings.HasSuffix(line, "\\") {
+			current += strings.TrimSuffix(line, "\\") + " "
+			continue
+		}
+		current += line
+		if strings.TrimSpace(current) != "" {
+			lines = append(lines, current)
+		}
+		current = ""
+	}
+	if strings.TrimSpace(current) != "" {
+		lines = append(lines, current)
+	}
+	return
+}
+
+// ddclientCommentIndex 引号外的 # 的位置, 没有时为 -1
+func ddclientCommentIndex(line string) int {
+	var quote rune
+	for i, c := range line {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '#':
+			return i
+		}
+	}
+	return -1
+}
+
+// ddclientTokens 按空白或逗号分割, 引号内不分割, 如 login=user, password='a b' example.com
+func ddclientTokens(line string) (tokens []string) {
+	var sb strings.Builder
+	var quote rune
+	flush := func() {
+		if sb.Len() > 0 {
+			tokens = append(tokens, sb.String())
+			sb.Reset()
+		}
+	}
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			sb.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote = c
+			sb.WriteRune(c)
+		case c == ',' || c == ' ' || c == '\t':
+			// 兼容等号两侧有空格, 如 login = user
+			j := i
+			for j < len(runes) && (runes[j] == ' ' || runes[j] == '\t') {
+				j++
+			}
+			if c != ',' && j < len(runes) && runes[j] == '=' {
+				i = j - 1
+				continue
+			}
+			if sb.Len() > 0 && strings.HasSuffix(sb.String(), "=") {
+				i = j - 1
+				continue
+			}
+			flush()
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	flush()
+	return
+}
+
+// ddclientUnquote 去除值两侧的引号
+func ddclientUnquote(value string) string {
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// ddclientToDnsConfig 将单个主机转换为 ddns-go 的配置及域名, 不支持的协议返回 false
+func ddclientToDnsConfig(settings map[string]string, host string) (dc DnsConfig, domain string, ok bool) {
+	protocol := strings.ToLower(settings["protocol"])
+	if protocol == "" {
+		protocol = "dyndns2"
+	}
+	login, password := settings["login"], settings["password"]
+	zone := settings["zone"]
+	domain = ddclientDomain(host, zone)
+
+	switch protocol {
+	case "dyndns2", "noip", "freedns":
+		defaultServer := map[string]string{
+			"dyndns2": "members.dyndns.org",
+			"noip":    "dynupdate.no-ip.com",
+			"freedns": "freedns.afraid.org",
+		}[protocol]
+		server := settings["server"]
+		if server == "" {
+			server = defaultServer
+		}
+		script := settings["script"]
+		if script == "" {
+			script = "/nic/update"
+		}
+		scheme := "https://"
+		if strings.EqualFold(settings["ssl"], "no") {
+			scheme = "http://"
+		}
+		if strings.Contains(server, "://") {
+			scheme = ""
+		}
+		dc.DNS = DNS{Name: "dyndns2", ID: login, Secret: password, Endpoint: scheme + server + script}
+		// 主机名可能不是根域名下的子域名, 如 No-IP 的 example.ddns.net
+		domain = host
+	case "cloudflare":
+		// 只支持API令牌, ddclient 中 login 为 token
+		if login != "" && login != "token" {
+			util.Log("ddclient 主机 %s 使用了 Cloudflare 的邮箱及 Global API Key, 请改为API令牌", host)
+		}
+		dc.DNS = DNS{Name: "cloudflare", Secret: password}
+	case "godaddy":
+		dc.DNS = DNS{Name: "godaddy", ID: login, Secret: password}
+	case "namecheap":
+		// login 为根域名, 主机为子域名, 如 @、www
+		if host == "@" || host == login {
+			domain = login
+		} else if !strings.HasSuffix(host, "."+login) {
+			domain = host + ":" + login
+		}
+		dc.DNS = DNS{Name: "namecheap", Secret: password}
+	case "porkbun":
+		dc.DNS = DNS{Name: "porkbun", ID: settings["apikey"], Secret: settings["secretapikey"]}
+	case "zoneedit1":
+		dc.DNS = DNS{Name: "zoneedit", ID: login, Secret: password}
+	case "dnsexit2":
+		dc.DNS = DNS{Name: "dnsexit", Secret: password}
+	default:
+		util.Log("不支持 ddclient 的协议 %s, 已忽略主机 %s", protocol, host)
+		return dc, "", false
+	}
+	dc.TTL = settings["ttl"]
+	ddclientIPSettings(&dc, settings)
+	return dc, domain, true
+}
+
+// ddclientDomain 指定了区域时转换为 子域名:根域名 格式, 如 www.example.com 及 example.com => www:example.com
+func ddclientDomain(host string, zone string) string {
+	zone = strings.TrimSuffix(zone, ".")
+	if zone == "" || host == zone || !strings.HasSuffix(host, "."+zone) {
+		return host
+	}
+	return strings.TrimSuffix(host, "."+zone) + ":" + zone
+}
+
+// ddclientIPSettings 转换获取IP的方式, 默认只更新IPv4, 设置了 usev6 时同时更新IPv6
+func ddclientIPSettings(dc *DnsConfig, settings map[string]string) {
+	use, usev6 := strings.ToLower(settings["usev4"]), strings.ToLower(settings["usev6"])
+	if use == "" {
+		use = strings.ToLower(settings["use"])
+	}
+	value := func(keys ...string) string {
+		for _, k := range keys {
+			if v := settings[k]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	dc.Ipv4.Enable = use != "disabled" && (use != "" || usev6 == "")
+	if dc.Ipv4.Enable {
+		dc.Ipv4.GetType = "url"
+		dc.Ipv4.URL = ddclientIpv4URL
+		switch use {
+		case "web", "webv4":
+			if web := value("webv4", "web"); strings.Contains(web, "://") {
+				dc.Ipv4.URL = web
+			}
+		case "if", "ifv4":
+			dc.Ipv4.GetType = "netInterface"
+			dc.Ipv4.NetInterface = value("ifv4", "if")
+		case "cmd", "cmdv4":
+			dc.Ipv4.GetType = "cmd"
+			dc.Ipv4.Cmd = value("cmdv4", "cmd")
+		case "", "ip", "ipv4":
+		default:
+			util.Log("不支持 ddclient 的获取IP方式 %s, 将通过接口获取", use)
+		}
+	}
+
+	dc.Ipv6.Enable = usev6 != "" && usev6 != "disabled"
+	if dc.Ipv6.Enable {
+		dc.Ipv6.GetType = "url"
+		dc.Ipv6.URL = ddclientIpv6URL
+		switch usev6 {
+		case "webv6", "web":
+			if web := value("webv6"); strings.Contains(web, "://") {
+				dc.Ipv6.URL = web
+			}
+		case "ifv6", "if":
+			dc.Ipv6.GetType = "netInterface"
+			dc.Ipv6.NetInterface = value("ifv6", "if")
+		case "cmdv6", "cmd":
+			dc.Ipv6.GetType = "cmd"
+			dc.Ipv6.Cmd = value("cmdv6", "cmd")
+		default:
+			util.Log("不支持 ddclient 的获取IP方式 %s, 将通过接口获取", usev6)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestImportDdclient 测试导入 ddclient 配置, 相同凭证的主机合并为一个配置
+func TestImportDdclient(t *testing.T) {
+	content := `# ddclient.conf
+daemon=300
+syslog=yes
+use=web, web=https://ipv4.example.net/ip
+ssl=yes
+
+protocol=dyndns2
+server=dynupdate.no-ip.com
+login=user
+password='p#ss word'   # 注释
+home.ddns.net, office.ddns.net
+
+protocol=cloudflare, \
+zone=example.com, \
+login=token, \
+password=cf-token
+usev6=ifv6, ifv6=eth0
+www.example.com
+example.com
+
+protocol=namecheap, login=example.org, password=nc-pass @, www
+protocol=unknown host.example.com
+`
+	confs := ImportDdclient(content)
+	if len(confs) != 3 {
+		t.Fatalf("期待 3 个配置, 得到 %d: %+v", len(confs), confs)
+	}
+
+	dyn := confs[0]
+	if dyn.DNS != (DNS{Name: "dyndns2", ID: "user", Secret: "p#ss word", Endpoint: "https://dynupdate.no-ip.com/nic/update"}) {
+		t.Errorf("DynDNS2 配置不正确: %+v", dyn.DNS)
+	}
+	if !reflect.DeepEqual(dyn.Ipv4.Domains, []string{"home.ddns.net", "office.ddns.net"}) || dyn.Ipv6.Enable {
+		t.Errorf("DynDNS2 域名不正确: %v %v", dyn.Ipv4.Domains, dyn.Ipv6.Enable)
+	}
+	if dyn.Ipv4.GetType != "url" || dyn.Ipv4.URL != "https://ipv4.example.net/ip" {
+		t.Errorf("获取IP方式不正确: %s %s", dyn.Ipv4.GetType, dyn.Ipv4.URL)
+	}
+
+	cf := confs[1]
+	if cf.DNS.Name != "cloudflare" || cf.DNS.Secret != "cf-token" {
+		t.Errorf("Cloudflare 配置不正确: %+v", cf.DNS)
+	}
+	expected := []string{"www:example.com", "example.com"}
+	if !reflect.DeepEqual(cf.Ipv4.Domains, expected) || !reflect.DeepEqual(cf.Ipv6.Domains, expected) {
+		t.Errorf("Cloudflare 域名期待 %v, 得到 %v %v", expected, cf.Ipv4.Domains, cf.Ipv6.Domains)
+	}
+	if cf.Ipv6.GetType != "netInterface" || cf.Ipv6.NetInterface != "eth0" {
+		t.Errorf("IPv6 获取方式不正确: %s %s", cf.Ipv6.GetType, cf.Ipv6.NetInterface)
+	}
+
+	nc := confs[2]
+	if nc.DNS.Name != "namecheap" || !reflect.DeepEqual(nc.Ipv4.Domains, []string{"example.org", "www:example.org"}) {
+		t.Errorf("Namecheap 配置不正确: %+v %v", nc.DNS, nc.Ipv4.Domains)
+	}
+}
+
+// TestDdclientTokens 测试分割 ddclient 配置行
+func TestDdclientTokens(t *testing.T) {
+	tokens := ddclientTokens(`login = user, password="a, b" host1,host2`)
+	expected := []string{"login=user", `password="a, b"`, "host1", "host2"}
+	if !reflect.DeepEqual(tokens, expected) {
+		t.Errorf("期待 %q, 得到 %q", expected, tokens)
+	}
+}
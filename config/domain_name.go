@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// maxDomainLength 域名的最大长度, 不含结尾的点
+const maxDomainLength = 253
+
+// maxLabelLength 每级标签的最大长度
+const maxLabelLength = 63
+
+// nameRules 域名的规范化及校验规则, 不同服务商的要求不同, 由DNS配置设置
+type nameRules struct {
+	// keepCase 保留大小写, 默认转为小写
+	keepCase bool
+	// noUnderscore 不允许下划线
+	noUnderscore bool
+}
+
+// nameRules 获得DNS配置的域名规则
+func (dnsConf *DnsConfig) nameRules() nameRules {
+	return nameRules{keepCase: dnsConf.KeepCase, noUnderscore: dnsConf.NoUnderscore}
+}
+
+// normalize 规范化用户输入的域名, 去除结尾的点, 未保留大小写时转为小写, 如 WWW.Example.com. => www.example.com
+func (rules nameRules) normalize(domainStr string) string {
+	domainStr = strings.TrimRight(strings.TrimSpace(domainStr), ".")
+	if rules.keepCase {
+		return domainStr
+	}
+	return strings.ToLower(domainStr)
+}
+
+// toASCII 国际化域名转换为 punycode, 保留大小写时不转换只含 ASCII 字符的名称, 以免被转为小写
+func (rules nameRules) toASCII(name string) string {
+	if rules.keepCase && isASCII(name) {
+		return name
+	}
+	name, _ = nontransitionalLookup.ToASCII(name)
+	return name
+}
+
+// check 按DNS标签规则校验 ASCII 形式的域名: 总长度不超过253, 每级标签1到63个字符,
+// 只含字母、数字、连字符及下划线, 不以连字符开头或结尾, 第一级可为通配符 *
+func (rules nameRules) check(name string) error {
+	if len(name) > maxDomainLength {
+		return errors.New(util.LogStr("域名超过 %d 个字符", maxDomainLength))
+	}
+	for i, label := range strings.Split(name, ".") {
+		switch {
+		case label == "":
+			return errors.New(util.LogStr("包含空的标签"))
+		case label == "*" && i == 0:
+			continue
+		case len(label) > maxLabelLength:
+			return errors.New(util.LogStr("标签 %s 超过 %d 个字符", label, maxLabelLength))
+		case strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-"):
+			return errors.New(util.LogStr("标签 %s 不能以连字符开头或结尾", label))
+		}
+		for _, c := range label {
+			switch {
+			case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-':
+			case c == '_':
+				if rules.noUnderscore {
+					return errors.New(util.LogStr("标签 %s 包含下划线, 该配置不允许下划线", label))
+				}
+			default:
+				return errors.New(util.LogStr("标签 %s 包含不允许的字符 %q", label, c))
+			}
+		}
+	}
+	return nil
+}
+
+// isASCII 是否只含 ASCII 字符
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"errors"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/jeessy2/ddns-go/v6/util"
@@ -17,6 +19,12 @@ type Domains struct {
 	Ipv6Addr    string
 	Ipv6Cache   *util.IpCache
 	Ipv6Domains []*Domain
+	// CompareDNS 与服务商比对前查询的DNS服务器, 为空不查询
+	CompareDNS string
+	// OnlyChanged 只更新IP变化的域名
+	OnlyChanged bool
+	// Ipv4NoIP/Ipv6NoIP 启用并填写了域名但未能获取到IP
+	Ipv4NoIP, Ipv6NoIP bool
 }
 
 // Domain 域名实体
@@ -26,6 +34,15 @@ type Domain struct {
 	// SubDomain 子域名
 	SubDomain    string
 	CustomParams string
+	UpdatePTR    bool             // 同时更新PTR记录, 由参数 ptr=true 开启
+	Comment      string           // 记录的备注, 由参数 comment 设置
+	RecordType   string           // 记录类型 A/AAAA/both, 由参数 record 设置, 为空时取决于所在的IPv4/IPv6列表
+	Webhook      string           // 该域名更新成功后调用的URL, 由参数 webhook 设置
+	Weight       string           // 记录的权重(0-100), 由参数 weight 设置, 为空时不设置
+	Tags         []string         // 域名的标签, 由参数 tag 设置, 用于只更新部分域名
+	CacheTimes   int              // 该域名间隔N次与服务商比对, 由参数 cache 设置, 为0时使用DNS配置的缓存次数
+	UpdateOnly   bool             // 只更新已有的记录, 记录不存在时不新增, 由参数 create=false 或DNS配置的 UpdateOnly 开启
+	create       string           // 参数 create 的值, 为空时取决于DNS配置
 	UpdateStatus updateStatusType // 更新状态
 }
 
@@ -46,6 +63,12 @@ func (d Domain) String() string {
 	return d.DomainName
 }
 
+// GetWeight 获得记录的权重, 未设置时返回 false
+func (d Domain) GetWeight() (int, bool) {
+	weight, err := strconv.Atoi(d.Weight)
+	return weight, err == nil
+}
+
 // GetFullDomain 获得全部的，子域名
 func (d Domain) GetFullDomain() string {
 	if d.SubDomain != "" {
@@ -54,6 +77,12 @@ func (d Domain) GetFullDomain() string {
 	return "@" + "." + d.DomainName
 }
 
+// GetFQDN 获得以点结尾的完整域名, 如 www.example.com.
+// 华为云等需要
+func (d Domain) GetFQDN() string {
+	return d.String() + "."
+}
+
 // GetSubDomain 获得子域名，为空返回@
 // 阿里云/腾讯云/dnspod/GoDaddy/namecheap 需要
 func (d Domain) GetSubDomain() string {
@@ -84,19 +113,30 @@ func (d Domain) ToASCII() string {
 	return name
 }
 
+// ToUnicode converts [Domain] to its Unicode form for display.
+//
+// Note: conversion errors are silently discarded and partial conversion
+// results are used.
+func (d Domain) ToUnicode() string {
+	name, _ := nontransitionalLookup.ToUnicode(d.String())
+	return name
+}
+
 // GetNewIp 接口/网卡/命令获得 ip 并校验用户输入的域名
 func (domains *Domains) GetNewIp(dnsConf *DnsConfig) {
-	domains.Ipv4Domains = checkParseDomains(dnsConf.Ipv4.Domains)
-	domains.Ipv6Domains = checkParseDomains(dnsConf.Ipv6.Domains)
+	domains.Ipv4Domains, domains.Ipv6Domains = dnsConf.ParseDomains()
+	domains.CompareDNS = dnsConf.CompareDNS
+	domains.OnlyChanged = dnsConf.OnlyChanged
 
 	// IPv4
 	if dnsConf.Ipv4.Enable && len(domains.Ipv4Domains) > 0 {
-		ipv4Addr := dnsConf.GetIpv4Addr()
+		ipv4Addr := dnsConf.getIpv4AddrShared()
 		if ipv4Addr != "" {
 			domains.Ipv4Addr = ipv4Addr
 			domains.Ipv4Cache.TimesFailedIP = 0
 		} else {
 			// 启用IPv4 & 未获取到IP & 填写了域名 & 失败刚好3次，防止偶尔的网络连接失败，并且只发一次
+			domains.Ipv4NoIP = true
 			domains.Ipv4Cache.TimesFailedIP++
 			if domains.Ipv4Cache.TimesFailedIP == 3 {
 				domains.Ipv4Domains[0].UpdateStatus = UpdatedFailed
@@ -107,12 +147,18 @@ func (domains *Domains) GetNewIp(dnsConf *DnsConfig) {
 
 	// IPv6
 	if dnsConf.Ipv6.Enable && len(domains.Ipv6Domains) > 0 {
-		ipv6Addr := dnsConf.GetIpv6Addr()
+		ipv6Addr := dnsConf.getIpv6AddrShared()
+		// 区域ID只在本机有效, 更新记录时去除
+		if ip, zone := util.SplitIPv6Zone(ipv6Addr); zone != "" {
+			util.Log("IPv6地址 %s 带有区域ID, 更新记录时将使用 %s", ipv6Addr, ip)
+			ipv6Addr = ip
+		}
 		if ipv6Addr != "" {
 			domains.Ipv6Addr = ipv6Addr
 			domains.Ipv6Cache.TimesFailedIP = 0
 		} else {
 			// 启用IPv6 & 未获取到IP & 填写了域名 & 失败刚好3次，防止偶尔的网络连接失败，并且只发一次
+			domains.Ipv6NoIP = true
 			domains.Ipv6Cache.TimesFailedIP++
 			if domains.Ipv6Cache.TimesFailedIP == 3 {
 				domains.Ipv6Domains[0].UpdateStatus = UpdatedFailed
@@ -124,78 +170,300 @@ func (domains *Domains) GetNewIp(dnsConf *DnsConfig) {
 }
 
 // checkParseDomains 校验并解析用户输入的域名
+// 一行可填写多个以逗号分隔的域名, 共用相同的参数, 如 example.com,www.example.com?comment=web
+// 不含点的名称为第一个域名的根域名下的子域名, @ 为根域名, 如 example.com,www,blog
 func checkParseDomains(domainArr []string) (domains []*Domain) {
+	return nameRules{}.parseDomains(domainArr)
+}
+
+// parseDomains 按规则校验并解析用户输入的域名, 不符合DNS规则的域名输出日志后忽略
+func (rules nameRules) parseDomains(domainArr []string) (domains []*Domain) {
 	for _, domainStr := range domainArr {
 		domainStr = strings.TrimSpace(domainStr)
 		if domainStr == "" {
 			continue
 		}
 
-		domain := &Domain{}
-
 		// qp(queryParts) 从域名中提取自定义参数，如 baidu.com?q=1 => [baidu.com, q=1]
 		qp := strings.Split(domainStr, "?")
-		domainStr = qp[0]
 
-		// dp(domainParts) 将域名（qp[0]）分割为子域名与根域名，如 www:example.cn.eu.org => [www, example.cn.eu.org]
-		dp := strings.Split(domainStr, ":")
-
-		switch len(dp) {
-		case 1: // 不使用冒号分割，自动识别域名
-			domainName, err := publicsuffix.EffectiveTLDPlusOne(domainStr)
-			if err != nil {
-				util.Log("域名: %s 不正确", domainStr)
-				util.Log("异常信息: %s", err)
+		var first *Domain
+		for _, name := range strings.Split(qp[0], ",") {
+			name = rules.normalize(name)
+			if name == "" {
 				continue
 			}
-			domain.DomainName = domainName
 
-			domainLen := len(domainStr) - len(domainName) - 1
-			if domainLen > 0 {
-				domain.SubDomain = domainStr[:domainLen]
+			var domain *Domain
+			if first != nil && isRelativeDomain(name) {
+				domain = &Domain{DomainName: first.DomainName}
+				if name != "@" {
+					domain.SubDomain = rules.toASCII(name)
+				}
+			} else {
+				domain = rules.parseDomain(name)
+				if domain == nil {
+					continue
+				}
 			}
-		case 2: // 使用冒号分隔，为 子域名:根域名 格式
-			sp := strings.Split(dp[1], ".")
-			if len(sp) <= 1 {
-				util.Log("域名: %s 不正确", domainStr)
+			if err := rules.check(domain.String()); err != nil {
+				util.Log("域名 %s 不符合DNS规则, 已忽略: %s", domain, err)
 				continue
 			}
-			domain.DomainName = dp[1]
-			domain.SubDomain = dp[0]
-		default:
-			util.Log("域名: %s 不正确", domainStr)
-			continue
-		}
 
-		// 参数条件
-		if len(qp) == 2 {
-			u, err := url.Parse("https://baidu.com?" + qp[1])
-			if err != nil {
+			// 参数条件
+			if len(qp) == 2 && !parseDomainParams(domain, qp[1]) {
 				util.Log("域名: %s 解析失败", domainStr)
-				continue
+				break
+			}
+			if first == nil {
+				first = domain
 			}
-			domain.CustomParams = u.Query().Encode()
+			domains = append(domains, domain)
 		}
-		domains = append(domains, domain)
 	}
 	return
 }
 
+// isRelativeDomain 是否为相对于根域名的名称, 如 www、@
+func isRelativeDomain(name string) bool {
+	return !strings.ContainsAny(name, ".:")
+}
+
+// errDomainFormat 域名格式不正确
+var errDomainFormat = errors.New("invalid domain format")
+
+// parseDomain 将域名分割为子域名与根域名, 不正确时返回 nil
+func (rules nameRules) parseDomain(domainStr string) *Domain {
+	domain, err := rules.splitDomain(domainStr)
+	if err != nil {
+		util.Log("域名: %s 不正确", domainStr)
+		if err != errDomainFormat {
+			util.Log("异常信息: %s", err)
+		}
+		return nil
+	}
+	return domain
+}
+
+// splitDomain 将域名分割为子域名与根域名
+func (rules nameRules) splitDomain(domainStr string) (*Domain, error) {
+	domain := &Domain{}
+
+	// dp(domainParts) 将域名分割为子域名与根域名，如 www:example.cn.eu.org => [www, example.cn.eu.org]
+	dp := strings.Split(domainStr, ":")
+
+	switch len(dp) {
+	case 1: // 不使用冒号分割，自动识别域名
+		// 公共后缀列表为小写, 保留大小写时按小写识别后截取原始的根域名
+		lower := strings.ToLower(domainStr)
+		domainName, err := publicsuffix.EffectiveTLDPlusOne(lower)
+		if err != nil {
+			return nil, err
+		}
+		if len(lower) == len(domainStr) {
+			domainName = domainStr[len(domainStr)-len(domainName):]
+		}
+		domain.DomainName = domainName
+
+		domainLen := len(domainStr) - len(domainName) - 1
+		if domainLen > 0 {
+			domain.SubDomain = domainStr[:domainLen]
+		}
+	case 2: // 使用冒号分隔，为 子域名:根域名 格式
+		sp := strings.Split(dp[1], ".")
+		if len(sp) <= 1 {
+			return nil, errDomainFormat
+		}
+		domain.DomainName = dp[1]
+		domain.SubDomain = dp[0]
+	default:
+		return nil, errDomainFormat
+	}
+
+	// 国际化域名转换为 punycode, 如 例え.jp => xn--r8jz45g.jp
+	domain.DomainName = rules.toASCII(domain.DomainName)
+	domain.SubDomain = rules.toASCII(domain.SubDomain)
+	return domain, nil
+}
+
+// parseDomainParams 解析域名后的参数, 失败返回 false
+func parseDomainParams(domain *Domain, rawQuery string) bool {
+	u, err := url.Parse("https://baidu.com?" + rawQuery)
+	if err != nil {
+		return false
+	}
+	query := u.Query()
+	// ptr、comment、record、webhook、weight、tag、cache、create、source 不直接传递给DNS服务商
+	if query.Has("record") {
+		domain.RecordType = parseRecordType(query.Get("record"))
+		query.Del("record")
+	}
+	if query.Has("ptr") {
+		domain.UpdatePTR = query.Get("ptr") == "true"
+		query.Del("ptr")
+	}
+	if query.Has("comment") {
+		domain.Comment = query.Get("comment")
+		query.Del("comment")
+	}
+	if query.Has("webhook") {
+		domain.Webhook = query.Get("webhook")
+		query.Del("webhook")
+	}
+	if query.Has("weight") {
+		if weight, err := strconv.Atoi(query.Get("weight")); err == nil && weight >= 0 && weight <= 100 {
+			domain.Weight = strconv.Itoa(weight)
+		} else {
+			util.Log("域名 %s 的权重 %s 不正确, 需为0到100的整数, 已忽略", domain, query.Get("weight"))
+		}
+		query.Del("weight")
+	}
+	if query.Has("tag") {
+		domain.Tags = parseTags(query["tag"])
+		query.Del("tag")
+	}
+	if query.Has("cache") {
+		if times, err := strconv.Atoi(query.Get("cache")); err == nil && times > 0 {
+			domain.CacheTimes = times
+		} else {
+			util.Log("域名 %s 的缓存次数 %s 不正确, 需为大于0的整数, 已忽略", domain, query.Get("cache"))
+		}
+		query.Del("cache")
+	}
+	if query.Has("create") {
+		domain.create = strings.ToLower(query.Get("create"))
+		domain.UpdateOnly = domain.create == "false"
+		query.Del("create")
+	}
+	query.Del("source")
+	domain.CustomParams = query.Encode()
+	return true
+}
+
+// 参数 record 指定的记录类型
+const (
+	recordTypeA    = "A"
+	recordTypeAAAA = "AAAA"
+	recordTypeBoth = "both"
+)
+
+// ParseDomains 解析IPv4/IPv6的域名, 按参数 record 指定的记录类型放入对应的列表
+// 如 IPv4 中的 example.com?record=both 同时更新 A 和 AAAA 记录
+func (dnsConf *DnsConfig) ParseDomains() (ipv4Domains []*Domain, ipv6Domains []*Domain) {
+	// 同一列表中的相同域名只保留第一个
+	add := func(list []*Domain, domain *Domain) []*Domain {
+		for _, d := range list {
+			if d.GetFullDomain() == domain.GetFullDomain() {
+				return list
+			}
+		}
+		return append(list, domain)
+	}
+	place := func(domain *Domain, defaultType string) {
+		// 未指定参数 create 时使用DNS配置的只更新已有记录
+		if domain.create == "" {
+			domain.UpdateOnly = dnsConf.UpdateOnly
+		}
+		recordType := domain.RecordType
+		if recordType == "" {
+			recordType = defaultType
+		}
+		switch recordType {
+		case recordTypeA:
+			ipv4Domains = add(ipv4Domains, domain)
+		case recordTypeAAAA:
+			ipv6Domains = add(ipv6Domains, domain)
+		case recordTypeBoth:
+			ipv6Copy := *domain
+			ipv4Domains = add(ipv4Domains, domain)
+			ipv6Domains = add(ipv6Domains, &ipv6Copy)
+		}
+	}
+	rules := dnsConf.nameRules()
+	for _, domain := range rules.parseDomains(dnsConf.Ipv4.Domains) {
+		place(domain, recordTypeA)
+	}
+	for _, domain := range rules.parseDomains(dnsConf.Ipv6.Domains) {
+		place(domain, recordTypeAAAA)
+	}
+	return
+}
+
+// parseRecordType 解析参数 record, 不正确时返回空
+func parseRecordType(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "a":
+		return recordTypeA
+	case "aaaa":
+		return recordTypeAAAA
+	case "both":
+		return recordTypeBoth
+	}
+	util.Log("记录类型 %s 不正确, 只支持 A、AAAA、both", value)
+	return ""
+}
+
 // GetNewIpResult 获得GetNewIp结果
 func (domains *Domains) GetNewIpResult(recordType string) (ipAddr string, retDomains []*Domain) {
 	if recordType == "AAAA" {
-		if domains.Ipv6Cache.Check(domains.Ipv6Addr) {
-			return domains.Ipv6Addr, domains.Ipv6Domains
+		if !domains.Ipv6Cache.Stable(domains.Ipv6Addr) {
+			util.Log("IPv6变化为 %s, 连续 %d/%d 次相同后更新", domains.Ipv6Addr, domains.Ipv6Cache.PendingTimes, domains.Ipv6Cache.StableTimes)
+			return "", domains.Ipv6Domains
+		}
+		forced := domains.Ipv6Cache.Addr == domains.Ipv6Addr
+		due := domains.Ipv6Cache.Check(domains.Ipv6Addr)
+		if retDomains = dueDomains(domains.Ipv6Cache, domains.Ipv6Addr, !forced, due, domains.Ipv6Domains); len(retDomains) > 0 {
+			ipAddr, retDomains = domains.skipWritten(domains.Ipv6Cache, domains.Ipv6Addr, forced, retDomains)
+			return domains.compareResolved("ip6", ipAddr, forced || util.ForceCompareGlobal, retDomains)
 		} else {
 			util.Log("IPv6未改变, 将等待 %d 次后与DNS服务商进行比对", domains.Ipv6Cache.Times)
 			return "", domains.Ipv6Domains
 		}
 	}
 	// IPv4
-	if domains.Ipv4Cache.Check(domains.Ipv4Addr) {
-		return domains.Ipv4Addr, domains.Ipv4Domains
+	if !domains.Ipv4Cache.Stable(domains.Ipv4Addr) {
+		util.Log("IPv4变化为 %s, 连续 %d/%d 次相同后更新", domains.Ipv4Addr, domains.Ipv4Cache.PendingTimes, domains.Ipv4Cache.StableTimes)
+		return "", domains.Ipv4Domains
+	}
+	forced := domains.Ipv4Cache.Addr == domains.Ipv4Addr
+	due := domains.Ipv4Cache.Check(domains.Ipv4Addr)
+	if retDomains = dueDomains(domains.Ipv4Cache, domains.Ipv4Addr, !forced, due, domains.Ipv4Domains); len(retDomains) > 0 {
+		ipAddr, retDomains = domains.skipWritten(domains.Ipv4Cache, domains.Ipv4Addr, forced, retDomains)
+		return domains.compareResolved("ip4", ipAddr, forced || util.ForceCompareGlobal, retDomains)
 	} else {
 		util.Log("IPv4未改变, 将等待 %d 次后与DNS服务商进行比对", domains.Ipv4Cache.Times)
 		return "", domains.Ipv4Domains
 	}
 }
+
+// dueDomains 本次需与DNS服务商比对的域名, 单独设置了缓存次数的域名按该次数比对, 其它域名在配置达到缓存次数(due)时比对
+// changed 为 true 时IP已改变, 全部比对
+func dueDomains(cache *util.IpCache, ipAddr string, changed bool, due bool, domainArr []*Domain) (result []*Domain) {
+	if ipAddr == "" {
+		if due {
+			return domainArr
+		}
+		return nil
+	}
+	for _, domain := range domainArr {
+		if domain.CacheTimes == 0 {
+			if due {
+				result = append(result, domain)
+			}
+			continue
+		}
+		if cache.CheckDomain(domain.String(), domain.CacheTimes, changed) {
+			if !due {
+				util.Log("域名 %s 达到缓存次数, 与DNS服务商进行比对", domain)
+			}
+			result = append(result, domain)
+		} else if due {
+			util.Log("域名 %s 未改变, 将等待 %d 次后与DNS服务商进行比对", domain, cache.DomainTimes[domain.String()])
+		}
+	}
+	if !due && len(result) > 0 {
+		cache.Provenance = util.IPFresh
+	}
+	return
+}
@@ -1,6 +1,12 @@
 package config
 
-import "testing"
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
 
 // TestToASCII test converts the name of [Domain] to its ASCII form.
 //
@@ -83,3 +89,281 @@ func TestParseDomainArr(t *testing.T) {
 	}
 
 }
+
+// TestParseIDNDomains 测试国际化域名转换为 punycode
+func TestParseIDNDomains(t *testing.T) {
+	tests := map[string]struct {
+		domain     string
+		domainName string
+		subDomain  string
+		unicode    string
+	}{
+		"root domain": {
+			"例え.jp", "xn--r8jz45g.jp", "", "例え.jp",
+		},
+		"sub domain": {
+			"www.例え.jp", "xn--r8jz45g.jp", "www", "www.例え.jp",
+		},
+		"unicode sub domain": {
+			"中文.例え.jp", "xn--r8jz45g.jp", "xn--fiq228c", "中文.例え.jp",
+		},
+		"colon separated": {
+			"sub:münchen.de", "xn--mnchen-3ya.de", "sub", "sub.münchen.de",
+		},
+		"unicode tld": {
+			"中文.中国", "xn--fiq228c.xn--fiqs8s", "", "中文.中国",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			parsed := checkParseDomains([]string{tt.domain})
+			if len(parsed) != 1 {
+				t.Fatalf("解析 %s 失败", tt.domain)
+			}
+			d := parsed[0]
+			if d.DomainName != tt.domainName || d.SubDomain != tt.subDomain {
+				t.Errorf("解析 %s 失败: 期待 %s:%s, 得到 %s:%s", tt.domain, tt.subDomain, tt.domainName, d.SubDomain, d.DomainName)
+			}
+			if d.ToUnicode() != tt.unicode {
+				t.Errorf("ToUnicode() = %v, want %v", d.ToUnicode(), tt.unicode)
+			}
+		})
+	}
+}
+
+// TestNormalizeDomains 测试结尾的点及大小写
+func TestNormalizeDomains(t *testing.T) {
+	tests := map[string]struct {
+		domain     string
+		domainName string
+		subDomain  string
+		params     string
+	}{
+		"trailing dot": {
+			"host.example.com.", "example.com", "host", "",
+		},
+		"multiple trailing dots": {
+			"example.com..", "example.com", "", "",
+		},
+		"uppercase": {
+			"WWW.EXAMPLE.CO.UK", "example.co.uk", "www", "",
+		},
+		"mixed case with trailing dot": {
+			"Test.MyDomain.Com.", "mydomain.com", "test", "",
+		},
+		"colon separated with trailing dot": {
+			"WWW:Example.cn.eu.org.", "example.cn.eu.org", "www", "",
+		},
+		"custom params keep case": {
+			"Host.Example.com.?RecordId=AbC", "example.com", "host", "RecordId=AbC",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			parsed := checkParseDomains([]string{tt.domain})
+			if len(parsed) != 1 {
+				t.Fatalf("解析 %s 失败", tt.domain)
+			}
+			d := parsed[0]
+			if d.DomainName != tt.domainName || d.SubDomain != tt.subDomain || d.CustomParams != tt.params {
+				t.Errorf("解析 %s 失败: 期待 %s:%s?%s, 得到 %s:%s?%s", tt.domain,
+					tt.subDomain, tt.domainName, tt.params,
+					d.SubDomain, d.DomainName, d.CustomParams)
+			}
+		})
+	}
+
+	d := &Domain{DomainName: "example.com", SubDomain: "www"}
+	if d.GetFQDN() != "www.example.com." {
+		t.Errorf("GetFQDN() = %v, want %v", d.GetFQDN(), "www.example.com.")
+	}
+}
+
+// TestParsePTRDomains 测试 ptr 参数
+func TestParsePTRDomains(t *testing.T) {
+	parsed := checkParseDomains([]string{"mail.example.com?ptr=true&line=mx", "www.example.com"})
+	if len(parsed) != 2 {
+		t.Fatalf("解析失败")
+	}
+	if !parsed[0].UpdatePTR || parsed[0].CustomParams != "line=mx" {
+		t.Errorf("期待开启PTR且参数为 line=mx, 得到 %v %s", parsed[0].UpdatePTR, parsed[0].CustomParams)
+	}
+	if parsed[1].UpdatePTR {
+		t.Errorf("期待未开启PTR")
+	}
+}
+
+// TestParseCommentDomains 测试 comment 参数
+func TestParseCommentDomains(t *testing.T) {
+	parsed := checkParseDomains([]string{"www.example.com?comment=managed by ddns-go&TTL=600", "example.com"})
+	if len(parsed) != 2 {
+		t.Fatalf("解析失败")
+	}
+	if parsed[0].Comment != "managed by ddns-go" || parsed[0].CustomParams != "TTL=600" {
+		t.Errorf("期待备注为 managed by ddns-go 且参数为 TTL=600, 得到 %s %s", parsed[0].Comment, parsed[0].CustomParams)
+	}
+	if parsed[1].Comment != "" {
+		t.Errorf("期待备注为空, 得到 %s", parsed[1].Comment)
+	}
+}
+
+// TestParseWeightDomains 测试 weight 参数, 不正确时忽略
+func TestParseWeightDomains(t *testing.T) {
+	parsed := checkParseDomains([]string{"www.example.com?weight=20&line=cn", "a.example.com?weight=101", "b.example.com?weight=0"})
+	if len(parsed) != 3 {
+		t.Fatalf("解析失败")
+	}
+	if weight, ok := parsed[0].GetWeight(); !ok || weight != 20 || parsed[0].CustomParams != "line=cn" {
+		t.Errorf("期待权重为 20 且参数为 line=cn, 得到 %s %s", parsed[0].Weight, parsed[0].CustomParams)
+	}
+	if _, ok := parsed[1].GetWeight(); ok || parsed[1].CustomParams != "" {
+		t.Errorf("期待忽略不正确的权重, 得到 %s %s", parsed[1].Weight, parsed[1].CustomParams)
+	}
+	if weight, ok := parsed[2].GetWeight(); !ok || weight != 0 {
+		t.Errorf("期待权重为 0, 得到 %s", parsed[2].Weight)
+	}
+}
+
+// TestParseMultipleNames 测试一行多个域名
+func TestParseMultipleNames(t *testing.T) {
+	parsed := checkParseDomains([]string{"example.com, www.example.com,blog,@?comment=web&line=cn", "a:test.example.com,b"})
+	want := []string{"@.example.com", "www.example.com", "blog.example.com", "@.example.com", "a.test.example.com", "b.test.example.com"}
+	if len(parsed) != len(want) {
+		t.Fatalf("期待 %d 个域名, 得到 %d 个", len(want), len(parsed))
+	}
+	for i, d := range parsed {
+		if d.GetFullDomain() != want[i] {
+			t.Errorf("期待 %s, 得到 %s", want[i], d.GetFullDomain())
+		}
+		if i < 4 && (d.Comment != "web" || d.CustomParams != "line=cn") {
+			t.Errorf("期待 %s 使用相同的参数, 得到 %s %s", d, d.Comment, d.CustomParams)
+		}
+	}
+
+	// 第一个域名不正确时, 不含点的名称也不正确
+	if parsed := checkParseDomains([]string{"www,example.com"}); len(parsed) != 1 || parsed[0].String() != "example.com" {
+		t.Errorf("期待只解析 example.com, 得到 %v", parsed)
+	}
+}
+
+// TestParseDomainsRecordType 测试 record 参数指定记录类型
+func TestParseDomainsRecordType(t *testing.T) {
+	dc := &DnsConfig{}
+	dc.Ipv4.Domains = []string{"a.example.com", "dual.example.com?record=both&line=cn", "v6.example.com?record=AAAA", "bad.example.com?record=MX"}
+	dc.Ipv6.Domains = []string{"v4.example.com?record=a", "dual.example.com", "b.example.com"}
+
+	ipv4, ipv6 := dc.ParseDomains()
+	names := func(domains []*Domain) (result []string) {
+		for _, d := range domains {
+			result = append(result, d.String())
+		}
+		return
+	}
+	if got, want := names(ipv4), []string{"a.example.com", "dual.example.com", "bad.example.com", "v4.example.com"}; !slices.Equal(got, want) {
+		t.Errorf("IPv4 期待 %v, 得到 %v", want, got)
+	}
+	if got, want := names(ipv6), []string{"dual.example.com", "v6.example.com", "b.example.com"}; !slices.Equal(got, want) {
+		t.Errorf("IPv6 期待 %v, 得到 %v", want, got)
+	}
+	// 同时更新时两个列表中的状态互不影响, 且参数相同
+	if ipv4[1] == ipv6[0] || ipv6[0].CustomParams != "line=cn" {
+		t.Errorf("期待 IPv6 中为相同参数的副本, 得到 %+v", ipv6[0])
+	}
+}
+
+// TestDomainCacheTimes 测试参数 cache 单独设置域名的缓存次数, 其它域名使用配置的缓存次数
+func TestDomainCacheTimes(t *testing.T) {
+	parsed := checkParseDomains([]string{"a.example.com", "b.example.com?cache=2&line=cn", "c.example.com?cache=0"})
+	if parsed[1].CacheTimes != 2 || parsed[1].CustomParams != "line=cn" || parsed[2].CacheTimes != 0 {
+		t.Fatalf("期待 b 的缓存次数为 2 且忽略不正确的值, 得到 %+v", parsed)
+	}
+
+	domains := &Domains{Ipv4Cache: &util.IpCache{CustomTimes: 5}, Ipv4Domains: parsed[:2]}
+	run := func(ipAddr string) (names []string) {
+		domains.Ipv4Addr = ipAddr
+		_, retDomains := domains.GetNewIpResult("A")
+		if domains.Ipv4Cache.Provenance == util.IPCached {
+			return nil
+		}
+		for _, d := range retDomains {
+			names = append(names, d.SubDomain)
+		}
+		return
+	}
+	// 第1次及IP改变时全部比对, a 间隔5次, b 间隔2次
+	expected := [][]string{{"a", "b"}, nil, nil, {"b"}, nil, nil, {"a", "b"}, nil}
+	for i, want := range expected {
+		if got := run("192.0.2.1"); !slices.Equal(got, want) {
+			t.Errorf("第 %d 次期待比对 %v, 得到 %v", i+1, want, got)
+		}
+	}
+	if got := run("192.0.2.2"); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("IP改变时期待全部比对, 得到 %v", got)
+	}
+}
+
+// TestParseUpdateOnlyDomains 测试 create 参数及DNS配置的只更新已有记录, 参数优先
+func TestParseUpdateOnlyDomains(t *testing.T) {
+	dc := &DnsConfig{}
+	dc.Ipv4.Domains = []string{"a.example.com?create=false&line=cn", "b.example.com?create=true", "c.example.com"}
+
+	updateOnly := func() (result []bool) {
+		ipv4, _ := dc.ParseDomains()
+		for _, d := range ipv4 {
+			result = append(result, d.UpdateOnly)
+		}
+		return
+	}
+	if got, want := updateOnly(), []bool{true, false, false}; !slices.Equal(got, want) {
+		t.Errorf("期待 %v, 得到 %v", want, got)
+	}
+	dc.UpdateOnly = true
+	if got, want := updateOnly(), []bool{true, false, true}; !slices.Equal(got, want) {
+		t.Errorf("开启只更新已有记录时期待 %v, 得到 %v", want, got)
+	}
+
+	if parsed := checkParseDomains(dc.Ipv4.Domains); parsed[0].CustomParams != "line=cn" {
+		t.Errorf("期待参数为 line=cn, 得到 %s", parsed[0].CustomParams)
+	}
+}
+
+// TestNameRules 测试保留大小写、下划线及DNS标签规则, 不符合规则的域名被忽略
+func TestNameRules(t *testing.T) {
+	tests := map[string]struct {
+		rules   nameRules
+		domain  string
+		want    []string
+		invalid bool
+	}{
+		"underscore":             {nameRules{}, "_dmarc.example.com", []string{"_dmarc.example.com"}, false},
+		"underscore not allowed": {nameRules{noUnderscore: true}, "_dmarc.example.com", nil, true},
+		"relative underscore":    {nameRules{noUnderscore: true}, "example.com,_dmarc", []string{"example.com"}, true},
+		"lowercase":              {nameRules{}, "WWW.Example.CO.UK", []string{"www.example.co.uk"}, false},
+		"keep case":              {nameRules{keepCase: true}, "WWW.Example.CO.UK", []string{"WWW.Example.CO.UK"}, false},
+		"keep case unicode":      {nameRules{keepCase: true}, "Www.例え.jp", []string{"Www.xn--r8jz45g.jp"}, false},
+		"wildcard":               {nameRules{}, "*.example.com", []string{"*.example.com"}, false},
+		"max length label":       {nameRules{}, strings.Repeat("a", 63) + ".example.com", []string{strings.Repeat("a", 63) + ".example.com"}, false},
+		"overlength label":       {nameRules{}, strings.Repeat("a", 64) + ".example.com", nil, true},
+		"overlength domain":      {nameRules{}, strings.Repeat("a.", 125) + "example.com", nil, true},
+		"hyphen":                 {nameRules{}, "-www.example.com", nil, true},
+		"empty label":            {nameRules{}, "a..example.com", nil, true},
+		"invalid character":      {nameRules{}, "w w.example.com", nil, true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []string
+			for _, d := range tt.rules.parseDomains([]string{tt.domain}) {
+				got = append(got, d.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("期待 %v, 得到 %v", tt.want, got)
+			}
+			if invalid := tt.rules.invalidDomains([]string{tt.domain}); (len(invalid) > 0) != tt.invalid {
+				t.Errorf("校验 %s 期待不正确 %v, 得到 %v", tt.domain, tt.invalid, invalid)
+			}
+		})
+	}
+}
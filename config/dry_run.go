@@ -0,0 +1,55 @@
+package config
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// LogDryRun 只获取IP并输出将要进行的更新, 不调用DNS服务商接口
+// 当前的解析通过 CompareDNS 或系统的DNS查询, 仅供参考, 实际以服务商的记录为准
+func (dnsConf *DnsConfig) LogDryRun() {
+	ipv4Domains, ipv6Domains := dnsConf.ParseDomains()
+	resolver := net.DefaultResolver
+	if dnsConf.CompareDNS != "" {
+		resolver = util.NewResolver(dnsConf.CompareDNS)
+	}
+	if dnsConf.Ipv4.Enable && len(ipv4Domains) > 0 {
+		dryRunDomains(resolver, "ip4", dnsConf.GetIpv4Addr(), ipv4Domains)
+	}
+	if dnsConf.Ipv6.Enable && len(ipv6Domains) > 0 {
+		dryRunDomains(resolver, "ip6", dnsConf.GetIpv6Addr(), ipv6Domains)
+	}
+}
+
+// dryRunDomains 输出域名将要进行的更新, 返回未解析到当前IP的域名, 未获取到IP时不输出
+func dryRunDomains(resolver *net.Resolver, network string, ipAddr string, domainArr []*Domain) (pending []*Domain) {
+	addr, err := netip.ParseAddr(ipAddr)
+	if err != nil {
+		return
+	}
+	for _, domain := range domainArr {
+		ctx, cancel := context.WithTimeout(context.Background(), compareTimeout)
+		addrs, err := resolver.LookupNetIP(ctx, network, domain.ToASCII())
+		cancel()
+		if err != nil {
+			util.Log("试运行: 域名 %s 将更新为 %s, 查询当前解析失败: %s", domain, ipAddr, err)
+			pending = append(pending, domain)
+			continue
+		}
+		if len(addrs) == 1 && addrs[0].Unmap() == addr.Unmap() {
+			util.Log("试运行: 域名 %s 已解析到 %s, 预计无需更新", domain, ipAddr)
+			continue
+		}
+		current := make([]string, 0, len(addrs))
+		for _, a := range addrs {
+			current = append(current, a.Unmap().String())
+		}
+		util.Log("试运行: 域名 %s 当前解析为 %s, 将更新为 %s", domain, strings.Join(current, ", "), ipAddr)
+		pending = append(pending, domain)
+	}
+	return
+}
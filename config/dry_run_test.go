@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestDryRunDomains 测试试运行只返回未解析到当前IP的域名
+func TestDryRunDomains(t *testing.T) {
+	addr := startCompareDNSServer(t, map[string][][4]byte{
+		"same.example.com.":  {{192, 0, 2, 1}},
+		"other.example.com.": {{192, 0, 2, 2}},
+	})
+	same := &Domain{DomainName: "example.com", SubDomain: "same"}
+	other := &Domain{DomainName: "example.com", SubDomain: "other"}
+	missing := &Domain{DomainName: "example.com", SubDomain: "missing"}
+
+	pending := dryRunDomains(util.NewResolver(addr), "ip4", "192.0.2.1", []*Domain{same, other, missing})
+	if len(pending) != 2 || pending[0] != other || pending[1] != missing {
+		t.Errorf("期待将更新 other missing, 得到 %v", pending)
+	}
+	for _, d := range []*Domain{same, other, missing} {
+		if d.UpdateStatus != "" {
+			t.Errorf("期待试运行不修改 %s 的状态, 得到 %s", d, d.UpdateStatus)
+		}
+	}
+
+	// 未获取到IP时不输出
+	if pending := dryRunDomains(util.NewResolver(addr), "ip4", "", []*Domain{other}); len(pending) != 0 {
+		t.Errorf("期待未获取到IP时为空, 得到 %v", pending)
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// 重复域名的处理方式
+const (
+	// DuplicateDomainsWarn 输出日志后仍然更新, 默认
+	DuplicateDomainsWarn = "warn"
+	// DuplicateDomainsRefuse 校验失败, 不能保存
+	DuplicateDomainsRefuse = "refuse"
+)
+
+// domainTarget 更新的目标, 域名及记录类型相同时多个条目会相互覆盖
+type domainTarget struct {
+	name       string
+	recordType string
+}
+
+// duplicateDomain 重复的域名, index/field 为重复的条目, first 为第一次出现的配置
+type duplicateDomain struct {
+	index      int
+	field      string
+	first      int
+	domain     string
+	recordType string
+}
+
+// duplicateDomains 返回已启用的IPv4/IPv6中域名及记录类型重复的条目, 包括同一配置及不同配置
+func (conf *Config) duplicateDomains() (dups []duplicateDomain) {
+	seen := map[domainTarget]int{}
+	for i, dc := range conf.DnsConf {
+		rules := dc.nameRules()
+		for _, list := range []struct {
+			field       string
+			domains     []string
+			defaultType string
+		}{
+			{"Ipv4Domains", dc.Ipv4.Domains, recordTypeA},
+			{"Ipv6Domains", dc.Ipv6.Domains, recordTypeAAAA},
+		} {
+			for _, domain := range rules.parseDomains(list.domains) {
+				recordTypes := []string{domain.RecordType}
+				switch domain.RecordType {
+				case "":
+					recordTypes = []string{list.defaultType}
+				case recordTypeBoth:
+					recordTypes = []string{recordTypeA, recordTypeAAAA}
+				}
+				for _, recordType := range recordTypes {
+					if (recordType == recordTypeA && !dc.Ipv4.Enable) || (recordType == recordTypeAAAA && !dc.Ipv6.Enable) {
+						continue
+					}
+					key := domainTarget{strings.ToLower(domain.ToASCII()), recordType}
+					if first, ok := seen[key]; ok {
+						dups = append(dups, duplicateDomain{i, list.field, first, domain.String(), recordType})
+						continue
+					}
+					seen[key] = i
+				}
+			}
+		}
+	}
+	return
+}
+
+// message 重复域名的提示
+func (d duplicateDomain) message(lang string) string {
+	if d.first == d.index {
+		return util.LogStr("第 %s 个配置的域名 %s (%s) 重复", util.Ordinal(d.index+1, lang), d.domain, d.recordType)
+	}
+	return util.LogStr("第 %s 个配置的域名 %s (%s) 与第 %s 个配置重复", util.Ordinal(d.index+1, lang), d.domain, d.recordType, util.Ordinal(d.first+1, lang))
+}
+
+// DuplicateDomainWarnings 重复域名的处理方式不为 refuse 时返回重复域名的提示, 用于输出日志
+func (conf *Config) DuplicateDomainWarnings() (warnings []string) {
+	if conf.DuplicateDomains == DuplicateDomainsRefuse {
+		return nil
+	}
+	for _, d := range conf.duplicateDomains() {
+		warnings = append(warnings, d.message(conf.Lang))
+	}
+	return
+}
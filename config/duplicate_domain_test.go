@@ -0,0 +1,55 @@
+package config
+
+import "testing"
+
+// TestDuplicateDomains 测试按域名及记录类型检测重复, 按处理方式警告或校验失败
+func TestDuplicateDomains(t *testing.T) {
+	conf := &Config{DnsConf: []DnsConfig{
+		{DNS: DNS{Name: "cloudflare", Secret: "token"}},
+		{DNS: DNS{Name: "callback", ID: "https://example.com"}},
+	}}
+	conf.DnsConf[0].Ipv4.Enable = true
+	conf.DnsConf[0].Ipv4.Domains = []string{"www.example.com", "example.com,WWW", "example.com?record=both"}
+	conf.DnsConf[0].Ipv6.Enable = true
+	conf.DnsConf[0].Ipv6.Domains = []string{"www.example.com"}
+	conf.DnsConf[1].Ipv4.Enable = true
+	conf.DnsConf[1].Ipv4.Domains = []string{"a.example.com", "example.com"}
+	// 未启用IPv6时不检测
+	conf.DnsConf[1].Ipv6.Domains = []string{"www.example.com"}
+
+	expected := []duplicateDomain{
+		{0, "Ipv4Domains", 0, "www.example.com", "A"},
+		{0, "Ipv4Domains", 0, "example.com", "A"},
+		{1, "Ipv4Domains", 0, "example.com", "A"},
+	}
+	dups := conf.duplicateDomains()
+	if len(dups) != len(expected) {
+		t.Fatalf("期待 %d 个重复, 得到 %v", len(expected), dups)
+	}
+	for i, e := range expected {
+		if dups[i] != e {
+			t.Errorf("第 %d 个重复期待 %v, 得到 %v", i, e, dups[i])
+		}
+	}
+
+	if warnings := conf.DuplicateDomainWarnings(); len(warnings) != 3 {
+		t.Errorf("期待默认输出 3 个警告, 得到 %v", warnings)
+	}
+	if errs := conf.Validate(); len(errs) != 0 {
+		t.Errorf("期待默认不校验失败, 得到 %v", errs)
+	}
+
+	conf.DuplicateDomains = DuplicateDomainsRefuse
+	if warnings := conf.DuplicateDomainWarnings(); warnings != nil {
+		t.Errorf("期待拒绝时不输出警告, 得到 %v", warnings)
+	}
+	errs := conf.Validate()
+	if len(errs) != 3 || errs[2].Index != 1 || errs[2].Field != "Ipv4Domains" {
+		t.Errorf("期待拒绝时校验失败, 得到 %v", errs)
+	}
+
+	conf.DuplicateDomains = "ignore"
+	if errs := conf.Validate(); len(errs) != 1 || errs[0].Field != "DuplicateDomains" {
+		t.Errorf("期待处理方式不正确, 得到 %v", errs)
+	}
+}
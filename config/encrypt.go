@@ -0,0 +1,146 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ConfigPassphraseENV 配置文件加密密码的环境变量
+const ConfigPassphraseENV = "DDNS_GO_CONFIG_PASSPHRASE"
+
+// encryptedHeader 加密后的配置文件头
+const encryptedHeader = "DDNS-GO-ENCRYPTED:v1\n"
+
+const (
+	saltSize = 16
+	keySize  = 32
+)
+
+// passphrase 终端中输入的密码, 仅输入一次
+var passphrase struct {
+	once  sync.Once
+	value string
+}
+
+// getPassphrase 获得配置文件加密密码, 优先使用环境变量
+// prompt 为 true 且在终端中运行时, 提示输入密码
+func getPassphrase(prompt bool) string {
+	if p := os.Getenv(ConfigPassphraseENV); p != "" {
+		return p
+	}
+	if prompt {
+		passphrase.once.Do(func() {
+			if stat, err := os.Stdin.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+				return
+			}
+			fmt.Print(util.LogStr("请输入配置文件密码: "))
+			line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			passphrase.value = strings.TrimSpace(line)
+		})
+	}
+	return passphrase.value
+}
+
+// isEncrypted 配置文件是否已加密
+func isEncrypted(byt []byte) bool {
+	return bytes.HasPrefix(byt, []byte(encryptedHeader))
+}
+
+// deriveKey 使用 scrypt 从密码派生密钥
+func deriveKey(pass string, salt []byte) ([]byte, error) {
+	return scrypt.Key([]byte(pass), salt, 1<<15, 8, 1, keySize)
+}
+
+// encryptConfig 使用 AES-GCM 加密配置文件
+func encryptConfig(byt []byte, pass string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(pass, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	// salt + nonce + 密文
+	data := append(salt, nonce...)
+	data = gcm.Seal(data, nonce, byt, []byte(encryptedHeader))
+	return []byte(encryptedHeader + base64.StdEncoding.EncodeToString(data) + "\n"), nil
+}
+
+// decryptConfig 解密配置文件
+func decryptConfig(byt []byte, pass string) ([]byte, error) {
+	if pass == "" {
+		return nil, errors.New(util.LogStr("配置文件已加密, 请通过环境变量 %s 设置密码", ConfigPassphraseENV))
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(byt[len(encryptedHeader):])))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < saltSize {
+		return nil, errors.New(util.LogStr("配置文件解密失败, 请检查密码是否正确"))
+	}
+	key, err := deriveKey(pass, data[:saltSize])
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	data = data[saltSize:]
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New(util.LogStr("配置文件解密失败, 请检查密码是否正确"))
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(encryptedHeader))
+	if err != nil {
+		return nil, errors.New(util.LogStr("配置文件解密失败, 请检查密码是否正确"))
+	}
+	return plain, nil
+}
+
+// decodeConfig 已加密时解密配置文件, 否则原样返回
+func decodeConfig(byt []byte) ([]byte, error) {
+	if !isEncrypted(byt) {
+		return byt, nil
+	}
+	return decryptConfig(byt, getPassphrase(true))
+}
+
+// encodeConfig 设置了密码时加密配置文件, 否则原样返回
+func encodeConfig(byt []byte) ([]byte, error) {
+	pass := getPassphrase(false)
+	if pass == "" {
+		return byt, nil
+	}
+	return encryptConfig(byt, pass)
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestEncryptConfig 测试配置文件加解密
+func TestEncryptConfig(t *testing.T) {
+	plain := []byte("dnsconf:\n    - name: test\n")
+
+	encrypted, err := encryptConfig(plain, "passphrase")
+	if err != nil {
+		t.Fatalf("加密失败: %s", err)
+	}
+	if !isEncrypted(encrypted) || bytes.Contains(encrypted, plain) {
+		t.Fatalf("期待已加密, 得到 %s", encrypted)
+	}
+
+	decrypted, err := decryptConfig(encrypted, "passphrase")
+	if err != nil || !bytes.Equal(decrypted, plain) {
+		t.Errorf("解密失败: %s, %s", decrypted, err)
+	}
+
+	if _, err := decryptConfig(encrypted, "wrong"); err == nil {
+		t.Errorf("期待密码错误时解密失败")
+	}
+}
+
+// TestDecodePlainConfig 测试未加密的配置文件原样返回
+func TestDecodePlainConfig(t *testing.T) {
+	plain := []byte("lang: en\n")
+	decoded, err := decodeConfig(plain)
+	if err != nil || !bytes.Equal(decoded, plain) {
+		t.Errorf("期待原样返回, 得到 %s, %s", decoded, err)
+	}
+}
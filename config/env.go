@@ -0,0 +1,294 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// EnvPrefix 环境变量配置的前缀, 如 DDNS_GO_DNS_CONF_0_IPV4_DOMAINS
+const EnvPrefix = "DDNS_GO_"
+
+// envDnsConfReg 环境变量中DNS配置的序号
+var envDnsConfReg = regexp.MustCompile(`^` + EnvPrefix + `DNS_CONF_(\d+)_`)
+
+// envOnly 只使用环境变量中的配置, 不读取及保存配置文件
+var envOnly bool
+
+// SetEnvOnly 设置只使用环境变量中的配置
+func SetEnvOnly(only bool) {
+	envOnly = only
+}
+
+// IsEnvOnly 是否只使用环境变量中的配置
+func IsEnvOnly() bool {
+	return envOnly
+}
+
+// envKey 将字段名转为环境变量名, 如 NotAllowWanAccess => NOT_ALLOW_WAN_ACCESS
+func envKey(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(unicode.ToUpper(r))
+	}
+	return sb.String()
+}
+
+// walkEnvFields 遍历配置中可使用环境变量设置的字段
+// 匿名字段不增加前缀, 配置列表使用序号, 如 DNS_CONF_0_
+func walkEnvFields(prefix string, v reflect.Value, fn func(key string, field reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		field := v.Field(i)
+		if sf.Anonymous && field.Kind() == reflect.Struct {
+			walkEnvFields(prefix, field, fn)
+			continue
+		}
+		key := prefix + envKey(sf.Name)
+		switch {
+		case field.Kind() == reflect.Struct:
+			walkEnvFields(key+"_", field, fn)
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.Struct:
+			for j := 0; j < field.Len(); j++ {
+				walkEnvFields(key+"_"+strconv.Itoa(j)+"_", field.Index(j), fn)
+			}
+		default:
+			fn(key, field)
+		}
+	}
+}
+
+// encodeEnvValue 将字段转为环境变量的值, 换行转为 \n, 列表以逗号分隔
+func encodeEnvValue(field reflect.Value) string {
+	switch field.Kind() {
+	case reflect.String:
+		return strings.ReplaceAll(field.String(), "\n", `\n`)
+	case reflect.Bool:
+		return strconv.FormatBool(field.Bool())
+	case reflect.Int:
+		return strconv.FormatInt(field.Int(), 10)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() == reflect.String {
+			// 值中的逗号写为 \, , 如一行多个域名
+			var list []string
+			for _, v := range field.Interface().([]string) {
+				list = append(list, strings.ReplaceAll(v, ",", `\,`))
+			}
+			return strings.Join(list, ",")
+		}
+	}
+	return ""
+}
+
+// ToEnv 将配置转为环境变量, 每行一个 KEY='value', 忽略空值
+// 值使用单引号, 以便含 $、引号、空格的值可直接粘贴到 shell
+func (conf *Config) ToEnv() []string {
+	var lines []string
+	walkEnvFields(EnvPrefix, reflect.ValueOf(conf).Elem(), func(key string, field reflect.Value) {
+		if field.IsZero() {
+			return
+		}
+		if value := encodeEnvValue(field); value != "" {
+			lines = append(lines, key+"="+quoteEnvValue(value))
+		}
+	})
+	return lines
+}
+
+// quoteEnvValue 使用单引号包裹值, 值中的单引号写为
+//
+//	'\''
+func quoteEnvValue(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// unquoteEnvValue 去除 quoteEnvValue 添加的单引号, 如 --env-file 不经过 shell 时
+// 不是单引号包裹的值原样返回
+func unquoteEnvValue(value string) string {
+	if len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+		return value
+	}
+	var b strings.Builder
+	for rest := value; rest != ""; {
+		switch {
+		case strings.HasPrefix(rest, `\'`):
+			b.WriteByte('\'')
+			rest = rest[2:]
+		case rest[0] == '\'':
+			end := strings.IndexByte(rest[1:], '\'')
+			if end < 0 {
+				return value
+			}
+			b.WriteString(rest[1 : end+1])
+			rest = rest[end+2:]
+		default:
+			return value
+		}
+	}
+	return b.String()
+}
+
+// decodeEnvValue 将环境变量的值写入字段
+func decodeEnvValue(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(strings.ReplaceAll(value, `\n`, "\n"))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		i, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(i))
+	case reflect.Slice:
+		var list []string
+		for _, v := range splitEnvList(value) {
+			if v = strings.TrimSpace(v); v != "" {
+				list = append(list, v)
+			}
+		}
+		field.Set(reflect.ValueOf(list))
+	}
+	return nil
+}
+
+// splitEnvList 以逗号分割列表, 忽略 \, 转义的逗号
+func splitEnvList(value string) (list []string) {
+	var item strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value) && value[i+1] == ',':
+			item.WriteByte(',')
+			i++
+		case value[i] == ',':
+			list = append(list, item.String())
+			item.Reset()
+		default:
+			item.WriteByte(value[i])
+		}
+	}
+	return append(list, item.String())
+}
+
+// ApplyEnv 使用环境变量覆盖配置, DNS配置按序号覆盖, 序号超出时追加
+// 返回使用的环境变量名
+func (conf *Config) ApplyEnv(environ []string) (keys []string, err error) {
+	env := map[string]string{}
+	for _, kv := range environ {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(key, EnvPrefix) {
+			continue
+		}
+		env[key] = unquoteEnvValue(value)
+		// 按最大的序号补齐DNS配置
+		if m := envDnsConfReg.FindStringSubmatch(key); m != nil {
+			if i, err := strconv.Atoi(m[1]); err == nil && i < 100 {
+				for len(conf.DnsConf) <= i {
+					conf.DnsConf = append(conf.DnsConf, DnsConfig{})
+				}
+			}
+		}
+	}
+	if len(env) == 0 {
+		return
+	}
+
+	walkEnvFields(EnvPrefix, reflect.ValueOf(conf).Elem(), func(key string, field reflect.Value) {
+		value, ok := env[key]
+		if !ok || err != nil {
+			return
+		}
+		if e := decodeEnvValue(field, value); e != nil {
+			err = errors.New(util.LogStr("环境变量 %s 的值 %q 不正确", key, value))
+			return
+		}
+		keys = append(keys, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 环境变量中的密码可为明文
+	for _, pwd := range []struct {
+		key   string
+		value *string
+	}{{EnvPrefix + "PASSWORD", &conf.Password}, {EnvPrefix + "READ_ONLY_PASSWORD", &conf.ReadOnlyPassword}, {EnvPrefix + "BASIC_AUTH_PASSWORD", &conf.BasicAuthPassword}} {
+		if slices.Contains(keys, pwd.key) && *pwd.value != "" && !util.IsHashedPassword(*pwd.value) {
+			if *pwd.value, err = util.HashPassword(*pwd.value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(keys) > 0 {
+		return keys, conf.validateEnvDnsConf()
+	}
+	return
+}
+
+// envFieldValues 获得配置中可使用环境变量设置的字段, 键为环境变量名
+func envFieldValues(conf *Config) map[string]reflect.Value {
+	values := map[string]reflect.Value{}
+	if conf != nil {
+		walkEnvFields(EnvPrefix, reflect.ValueOf(conf).Elem(), func(key string, field reflect.Value) {
+			values[key] = field
+		})
+	}
+	return values
+}
+
+// restoreEnvFields 将环境变量设置的字段还原为配置文件中的值, 配置文件中没有时为空值
+// 与读取时(cached)不同的字段输出日志, 这些字段的修改不会保存
+func (conf *Config) restoreEnvFields(keys []string, fileConf *Config, cached *Config) {
+	fileValues := envFieldValues(fileConf)
+	cachedValues := envFieldValues(cached)
+	walkEnvFields(EnvPrefix, reflect.ValueOf(conf).Elem(), func(key string, field reflect.Value) {
+		if !slices.Contains(keys, key) {
+			return
+		}
+		if value, ok := cachedValues[key]; ok && !reflect.DeepEqual(value.Interface(), field.Interface()) {
+			util.Log("%s 由环境变量设置, 修改不会保存", key)
+		}
+		if value, ok := fileValues[key]; ok {
+			field.Set(value)
+		} else {
+			field.Set(reflect.Zero(field.Type()))
+		}
+	})
+}
+
+// validateEnvDnsConf 校验环境变量中的DNS配置
+func (conf *Config) validateEnvDnsConf() error {
+	for i, dc := range conf.DnsConf {
+		if dc.DNS.Name == "" {
+			return errors.New(util.LogStr("环境变量中第 %d 个DNS配置未设置 %s", i, EnvPrefix+"DNS_CONF_"+strconv.Itoa(i)+"_DNS_NAME"))
+		}
+		if !dc.Ipv4.Enable && !dc.Ipv6.Enable {
+			util.Log("环境变量中第 %d 个DNS配置未启用IPv4及IPv6", i)
+		}
+	}
+	return nil
+}
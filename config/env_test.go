@@ -0,0 +1,221 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestEnvKey 测试字段名转为环境变量名
+func TestEnvKey(t *testing.T) {
+	tests := map[string]string{
+		"DnsConf":           "DNS_CONF",
+		"NotAllowWanAccess": "NOT_ALLOW_WAN_ACCESS",
+		"IPEndpoint":        "IP_ENDPOINT",
+		"Ipv4":              "IPV4",
+		"Ipv6Reg":           "IPV6_REG",
+		"URL":               "URL",
+		"NoIPAction":        "NO_IP_ACTION",
+		"TTL":               "TTL",
+	}
+	for name, want := range tests {
+		if got := envKey(name); got != want {
+			t.Errorf("%s 期待 %s, 得到 %s", name, want, got)
+		}
+	}
+}
+
+// TestToEnv 测试将配置转为环境变量
+func TestToEnv(t *testing.T) {
+	conf := &Config{NotAllowWanAccess: true}
+	conf.Username = "admin"
+	conf.WebhookRequestBody = "{\n\"ip\": \"#{ipv4Addr}\"\n}"
+	dc := DnsConfig{Name: "home", TTL: "600", CacheTimes: 3}
+	dc.DNS.Name = "cloudflare"
+	dc.DNS.Secret = "token"
+	dc.Ipv4.Enable = true
+	dc.Ipv4.GetType = "url"
+	dc.Ipv4.Domains = []string{"a.example.com", "example.com,www"}
+	conf.DnsConf = []DnsConfig{dc}
+
+	got := conf.ToEnv()
+	want := []string{
+		"DDNS_GO_DNS_CONF_0_NAME='home'",
+		"DDNS_GO_DNS_CONF_0_IPV4_ENABLE='true'",
+		"DDNS_GO_DNS_CONF_0_IPV4_GET_TYPE='url'",
+		`DDNS_GO_DNS_CONF_0_IPV4_DOMAINS='a.example.com,example.com\,www'`,
+		"DDNS_GO_DNS_CONF_0_DNS_NAME='cloudflare'",
+		"DDNS_GO_DNS_CONF_0_DNS_SECRET='token'",
+		"DDNS_GO_DNS_CONF_0_TTL='600'",
+		"DDNS_GO_DNS_CONF_0_CACHE_TIMES='3'",
+		"DDNS_GO_USERNAME='admin'",
+		`DDNS_GO_WEBHOOK_REQUEST_BODY='{\n"ip": "#{ipv4Addr}"\n}'`,
+		"DDNS_GO_NOT_ALLOW_WAN_ACCESS='true'",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("期待\n%v\n得到\n%v", want, got)
+	}
+
+	// 导出后可再读取
+	var decoded Config
+	if _, err := decoded.ApplyEnv(got); err != nil || !reflect.DeepEqual(&decoded, conf) {
+		t.Errorf("期待读取后与原配置相同, 得到 %+v, %v", decoded, err)
+	}
+}
+
+// TestToEnvQuote 测试含 $、引号、空格的值导出后可再读取
+func TestToEnvQuote(t *testing.T) {
+	conf := &Config{}
+	conf.Username = "it's me"
+	conf.Password = "$2a$10$abc def"
+	conf.WebhookRequestBody = `{"msg": "it's $HOME", 'a': "b c"}`
+
+	got := conf.ToEnv()
+	want := []string{
+		`DDNS_GO_USERNAME='it'\''s me'`,
+		`DDNS_GO_PASSWORD='$2a$10$abc def'`,
+		`DDNS_GO_WEBHOOK_REQUEST_BODY='{"msg": "it'\''s $HOME", '\''a'\'': "b c"}'`,
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("期待\n%v\n得到\n%v", want, got)
+	}
+
+	// 直接读取, 如 --env-file
+	var decoded Config
+	if _, err := decoded.ApplyEnv(got); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Username != conf.Username || decoded.WebhookRequestBody != conf.WebhookRequestBody {
+		t.Errorf("期待读取后与原配置相同, 得到 %+v", decoded)
+	}
+
+	// 经过 shell 后读取
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("未找到 sh")
+	}
+	out, err := exec.Command(sh, "-c", "env -i "+strings.Join(got, " ")+" env").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded = Config{}
+	if _, err := decoded.ApplyEnv(strings.Split(strings.TrimSpace(string(out)), "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Username != conf.Username || decoded.WebhookRequestBody != conf.WebhookRequestBody {
+		t.Errorf("期待经过 shell 后与原配置相同, 得到 %+v", decoded)
+	}
+}
+
+// TestApplyEnv 测试使用环境变量覆盖配置
+func TestApplyEnv(t *testing.T) {
+	conf := &Config{}
+	dc := DnsConfig{Name: "file"}
+	dc.DNS.Name = "alidns"
+	dc.Ipv4.Enable = true
+	dc.Ipv4.Domains = []string{"file.example.com"}
+	conf.DnsConf = []DnsConfig{dc}
+
+	keys, err := conf.ApplyEnv([]string{
+		"PATH=/usr/bin",
+		"DDNS_GO_CONFIG_PASSPHRASE=ignored",
+		"DDNS_GO_USERNAME=admin",
+		"DDNS_GO_PASSWORD=plain-password",
+		"DDNS_GO_NOT_ALLOW_WAN_ACCESS=true",
+		`DDNS_GO_WEBHOOK_REQUEST_BODY={\n"ip": "#{ipv4Addr}"\n}`,
+		"DDNS_GO_DNS_CONF_0_IPV4_DOMAINS=a.example.com, b.example.com",
+		"DDNS_GO_DNS_CONF_1_DNS_NAME=cloudflare",
+		"DDNS_GO_DNS_CONF_1_DNS_SECRET=token",
+		"DDNS_GO_DNS_CONF_1_IPV6_ENABLE=true",
+		"DDNS_GO_DNS_CONF_1_CACHE_TIMES=3",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 9 {
+		t.Errorf("期待使用 9 个环境变量, 得到 %v", keys)
+	}
+	if conf.Username != "admin" || !conf.NotAllowWanAccess || conf.WebhookRequestBody != "{\n\"ip\": \"#{ipv4Addr}\"\n}" {
+		t.Errorf("全局配置不正确: %+v", conf)
+	}
+	if !util.PasswordOK(conf.Password, "plain-password") {
+		t.Errorf("期待明文密码被加密")
+	}
+	if len(conf.DnsConf) != 2 {
+		t.Fatalf("期待 2 个DNS配置, 得到 %d", len(conf.DnsConf))
+	}
+	if conf.DnsConf[0].DNS.Name != "alidns" || !slices.Equal(conf.DnsConf[0].Ipv4.Domains, []string{"a.example.com", "b.example.com"}) {
+		t.Errorf("期待覆盖配置文件中的域名, 得到 %+v", conf.DnsConf[0])
+	}
+	if d := conf.DnsConf[1]; d.DNS.Name != "cloudflare" || d.DNS.Secret != "token" || !d.Ipv6.Enable || d.CacheTimes != 3 {
+		t.Errorf("期待追加DNS配置, 得到 %+v", d)
+	}
+}
+
+// TestApplyEnvInvalid 测试环境变量不正确
+func TestApplyEnvInvalid(t *testing.T) {
+	for _, environ := range [][]string{
+		{"DDNS_GO_NOT_ALLOW_WAN_ACCESS=yes please"},
+		{"DDNS_GO_DNS_CONF_0_CACHE_TIMES=three", "DDNS_GO_DNS_CONF_0_DNS_NAME=alidns"},
+		{"DDNS_GO_DNS_CONF_0_IPV4_ENABLE=true"},
+	} {
+		if _, err := (&Config{}).ApplyEnv(environ); err == nil {
+			t.Errorf("%v 期待异常", environ)
+		}
+	}
+
+	if keys, err := (&Config{}).ApplyEnv(nil); err != nil || len(keys) != 0 {
+		t.Errorf("无环境变量时期待不修改配置, 得到 %v, %v", keys, err)
+	}
+}
+
+// TestSaveConfigEnv 测试保存时不写入环境变量设置的字段, 其它字段的修改正常保存
+func TestSaveConfigEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ddns_go_config.yaml")
+	os.WriteFile(path, []byte(`
+lang: zh
+dnsconf:
+    - name: home
+      dns:
+        name: cloudflare
+        secret: file-token
+`), 0600)
+	t.Setenv(util.ConfigFilePathENV, path)
+	t.Setenv(EnvPrefix+"DNS_CONF_0_DNS_SECRET", "env-token")
+	t.Setenv(EnvPrefix+"DNS_CONF_1_DNS_NAME", "dnspod")
+	t.Setenv(EnvPrefix+"WEBHOOK_URL", "https://example.com/hook")
+	defer func() { cache.ConfigSingle = nil }()
+
+	cache.ConfigSingle = nil
+	conf, err := GetConfigCached()
+	if err != nil || conf.DnsConf[0].DNS.Secret != "env-token" || len(conf.DnsConf) != 2 {
+		t.Fatalf("读取失败: %v %+v", err, conf.DnsConf)
+	}
+	conf.Lang = "en"
+	conf.WebhookURL = "https://example.com/changed"
+	if err = conf.SaveConfig(); err != nil {
+		t.Fatalf("保存失败: %s", err)
+	}
+
+	byt, _ := os.ReadFile(path)
+	for _, s := range []string{"env-token", "dnspod", "example.com"} {
+		if strings.Contains(string(byt), s) {
+			t.Errorf("期待不保存环境变量设置的 %s, 得到\n%s", s, byt)
+		}
+	}
+	if !strings.Contains(string(byt), "file-token") || !strings.Contains(string(byt), "lang: en") {
+		t.Errorf("期待保留配置文件中的值并保存其它修改, 得到\n%s", byt)
+	}
+
+	// 重新读取时仍使用环境变量
+	conf, _ = GetConfigCached()
+	if conf.DnsConf[0].DNS.Secret != "env-token" || conf.DnsConf[1].DNS.Name != "dnspod" || conf.WebhookURL != "https://example.com/hook" || conf.Lang != "en" {
+		t.Errorf("期待重新读取后使用环境变量, 得到 %+v", conf)
+	}
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// postUpdateCmdTimeout 更新后命令的超时时间
+var postUpdateCmdTimeout = 30 * time.Second
+
+// execPostUpdateCmd 执行更新后的命令, 通过环境变量传递新的IP、结果及更新成功的域名
+func execPostUpdateCmd(domains *Domains, conf *Config, v4Status updateStatusType, v6Status updateStatusType) {
+	if conf.PostUpdateCmd == "" {
+		return
+	}
+
+	out, err := util.RunShell(conf.PostUpdateCmd, postUpdateCmdEnv(domains, v4Status, v6Status, conf.Lang), postUpdateCmdTimeout)
+	output := strings.TrimSpace(string(out))
+	if err == nil {
+		util.Log("更新后命令执行成功! 输出: %s", output)
+	} else {
+		util.Log("更新后命令执行失败! 异常信息: %s, 输出: %s", err, output)
+	}
+}
+
+// postUpdateCmdEnv 更新后命令的环境变量, 如 DDNS_GO_IPV4_ADDR DDNS_GO_IPV4_RESULT DDNS_GO_IPV4_DOMAINS
+func postUpdateCmdEnv(domains *Domains, v4Status updateStatusType, v6Status updateStatusType, lang string) []string {
+	return []string{
+		fmt.Sprintf("%sIPV4_ADDR=%s", EnvPrefix, domains.Ipv4Addr),
+		fmt.Sprintf("%sIPV4_RESULT=%s", EnvPrefix, util.LangStr(lang, string(v4Status))),
+		fmt.Sprintf("%sIPV4_DOMAINS=%s", EnvPrefix, getDomainsStr(getUpdatedDomains(domains.Ipv4Domains))),
+		fmt.Sprintf("%sIPV6_ADDR=%s", EnvPrefix, domains.Ipv6Addr),
+		fmt.Sprintf("%sIPV6_RESULT=%s", EnvPrefix, util.LangStr(lang, string(v6Status))),
+		fmt.Sprintf("%sIPV6_DOMAINS=%s", EnvPrefix, getDomainsStr(getUpdatedDomains(domains.Ipv6Domains))),
+	}
+}
+
+// getUpdatedDomains 获得更新成功的域名
+func getUpdatedDomains(domains []*Domain) []*Domain {
+	var updated []*Domain
+	for _, domain := range domains {
+		if domain.UpdateStatus == UpdatedSuccess {
+			updated = append(updated, domain)
+		}
+	}
+	return updated
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestPostUpdateCmdEnv 测试更新后命令的环境变量只包含更新成功的域名
+func TestPostUpdateCmdEnv(t *testing.T) {
+	domains := &Domains{
+		Ipv4Addr: "1.2.3.4",
+		Ipv4Domains: []*Domain{
+			{DomainName: "example.com", SubDomain: "www", UpdateStatus: UpdatedSuccess},
+			{DomainName: "example.com", SubDomain: "api", UpdateStatus: UpdatedNothing},
+		},
+		Ipv6Domains: []*Domain{{DomainName: "example.com", UpdateStatus: UpdatedFailed}},
+	}
+
+	env := postUpdateCmdEnv(domains, UpdatedSuccess, UpdatedFailed, "")
+	for _, expected := range []string{
+		"DDNS_GO_IPV4_ADDR=1.2.3.4",
+		"DDNS_GO_IPV4_DOMAINS=www.example.com",
+		"DDNS_GO_IPV4_RESULT=" + util.LogStr(UpdatedSuccess),
+		"DDNS_GO_IPV6_ADDR=",
+		"DDNS_GO_IPV6_DOMAINS=",
+		"DDNS_GO_IPV6_RESULT=" + util.LogStr(UpdatedFailed),
+	} {
+		if !slices.Contains(env, expected) {
+			t.Errorf("期待包含 %s, 得到 %v", expected, env)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// parseMaintenanceWindow 解析维护时段, 如 02:00-04:00, 支持跨天如 23:00-01:00
+// 返回开始、结束时间距当天零点的分钟数
+func parseMaintenanceWindow(window string) (start int, end int, err error) {
+	startStr, endStr, ok := strings.Cut(window, "-")
+	if !ok {
+		return 0, 0, errors.New(util.LogStr("维护时段格式不正确, 如 02:00-04:00"))
+	}
+	parse := func(s string) (int, error) {
+		t, err := time.Parse("15:04", strings.TrimSpace(s))
+		if err != nil {
+			return 0, errors.New(util.LogStr("维护时段格式不正确, 如 02:00-04:00"))
+		}
+		return t.Hour()*60 + t.Minute(), nil
+	}
+	if start, err = parse(startStr); err != nil {
+		return
+	}
+	if end, err = parse(endStr); err != nil {
+		return
+	}
+	if start == end {
+		return 0, 0, errors.New(util.LogStr("维护时段格式不正确, 如 02:00-04:00"))
+	}
+	return
+}
+
+// CheckMaintenanceWindow 校验维护时段及时区
+func CheckMaintenanceWindow(window string, timezone string) error {
+	if window == "" {
+		return nil
+	}
+	if _, _, err := parseMaintenanceWindow(window); err != nil {
+		return err
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return errors.New(util.LogStr("时区不正确: %s", timezone))
+	}
+	return nil
+}
+
+// InMaintenanceWindow 是否在维护时段内, 维护时段内只获取IP不更新
+func (conf *Config) InMaintenanceWindow(t time.Time) bool {
+	if conf.MaintenanceWindow == "" {
+		return false
+	}
+	start, end, err := parseMaintenanceWindow(conf.MaintenanceWindow)
+	if err != nil {
+		return false
+	}
+	// 为空时使用本地时区
+	if loc, err := time.LoadLocation(conf.MaintenanceTimezone); err == nil {
+		t = t.In(loc)
+	}
+
+	now := t.Hour()*60 + t.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	// 跨天
+	return now >= start || now < end
+}
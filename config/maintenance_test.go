@@ -0,0 +1,67 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// TestInMaintenanceWindow 测试是否在维护时段内
+func TestInMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		window string
+		now    string
+		want   bool
+	}{
+		{"", "03:00", false},
+		{"02:00-04:00", "01:59", false},
+		{"02:00-04:00", "02:00", true},
+		{"02:00-04:00", "03:30", true},
+		{"02:00-04:00", "04:00", false},
+		{"23:00-01:00", "23:30", true},
+		{"23:00-01:00", "00:30", true},
+		{"23:00-01:00", "12:00", false},
+		{"bad", "03:00", false},
+	}
+
+	for _, tt := range tests {
+		conf := &Config{MaintenanceWindow: tt.window, MaintenanceTimezone: "UTC"}
+		now, _ := time.Parse("15:04", tt.now)
+		if got := conf.InMaintenanceWindow(now); got != tt.want {
+			t.Errorf("维护时段 %q 在 %s 期待 %v, 得到 %v", tt.window, tt.now, tt.want, got)
+		}
+	}
+}
+
+// TestInMaintenanceWindowTimezone 测试维护时段的时区
+func TestInMaintenanceWindowTimezone(t *testing.T) {
+	conf := &Config{MaintenanceWindow: "02:00-04:00", MaintenanceTimezone: "Asia/Shanghai"}
+	// UTC 19:00 为上海 03:00
+	now := time.Date(2024, 1, 1, 19, 0, 0, 0, time.UTC)
+	if !conf.InMaintenanceWindow(now) {
+		t.Errorf("期待在维护时段内")
+	}
+}
+
+// TestCheckMaintenanceWindow 测试校验维护时段
+func TestCheckMaintenanceWindow(t *testing.T) {
+	tests := []struct {
+		window   string
+		timezone string
+		wantErr  bool
+	}{
+		{"", "bad/zone", false},
+		{"02:00-04:00", "", false},
+		{"02:00-04:00", "Asia/Shanghai", false},
+		{"02:00", "", true},
+		{"02:00-25:00", "", true},
+		{"02:00-02:00", "", true},
+		{"02:00-04:00", "bad/zone", true},
+	}
+
+	for _, tt := range tests {
+		err := CheckMaintenanceWindow(tt.window, tt.timezone)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("维护时段 %q 时区 %q 期待异常 %v, 得到 %v", tt.window, tt.timezone, tt.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"gopkg.in/yaml.v3"
+)
+
+// getConfigFilePaths 获得配置文件路径列表
+// 支持以逗号分隔的多个路径或目录(目录中的 *.yaml、*.yml 按文件名排序)
+func getConfigFilePaths() (paths []string) {
+	for _, path := range strings.Split(util.GetConfigFilePath(), ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if stat, err := os.Stat(path); err == nil && stat.IsDir() {
+			files, _ := filepath.Glob(filepath.Join(path, "*.yaml"))
+			ymlFiles, _ := filepath.Glob(filepath.Join(path, "*.yml"))
+			files = append(files, ymlFiles...)
+			sort.Strings(files)
+			paths = append(paths, files...)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return
+}
+
+// getSaveFilePath 获得保存配置的路径, 多个配置文件时为最后一个, 此时不能保存
+func getSaveFilePath() string {
+	if paths := getConfigFilePaths(); len(paths) > 0 {
+		return paths[len(paths)-1]
+	}
+	// 空目录
+	parts := strings.Split(util.GetConfigFilePath(), ",")
+	return filepath.Join(strings.TrimSpace(parts[len(parts)-1]), "ddns_go_config.yaml")
+}
+
+// readConfigFile 读取并解密配置文件
+func readConfigFile(path string) ([]byte, error) {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeConfig(byt)
+}
+
+// loadConfig 按顺序读取并合并配置文件
+// 后面的文件覆盖前面的全局配置, DNS配置按名称覆盖, 名称不同则追加
+func loadConfig(conf *Config) error {
+	paths := getConfigFilePaths()
+	if len(paths) == 0 {
+		return os.ErrNotExist
+	}
+
+	loaded := 0
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			// 只有一个配置文件时, 不存在则返回异常
+			if len(paths) == 1 {
+				return err
+			}
+			util.Log("配置文件 %s 不存在, 已跳过", path)
+			continue
+		}
+
+		byt, err := readConfigFile(path)
+		if err != nil {
+			util.Log("异常信息: %s", err)
+			return err
+		}
+
+		dnsConf := conf.DnsConf
+		conf.DnsConf = nil
+		err = yaml.Unmarshal(byt, conf)
+		if err != nil {
+			util.Log("异常信息: %s", err)
+			return err
+		}
+		conf.DnsConf = mergeDnsConf(dnsConf, conf.DnsConf)
+		loaded++
+	}
+
+	if loaded == 0 {
+		return os.ErrNotExist
+	}
+	if len(paths) > 1 {
+		return conf.validateDnsConf()
+	}
+	return nil
+}
+
+// mergeDnsConf 合并DNS配置, 名称相同的覆盖, 否则追加
+func mergeDnsConf(base []DnsConfig, override []DnsConfig) []DnsConfig {
+	for _, dc := range override {
+		replaced := false
+		for i := range base {
+			if dc.Name != "" && base[i].Name == dc.Name {
+				base[i] = dc
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			base = append(base, dc)
+		}
+	}
+	return base
+}
+
+// validateDnsConf 校验合并后的DNS配置
+func (conf *Config) validateDnsConf() error {
+	for i, dc := range conf.DnsConf {
+		if dc.DNS.Name == "" {
+			return errors.New(util.LogStr("合并后的第 %s 个配置未指定DNS服务商", util.Ordinal(i+1, conf.Lang)))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestLoadConfigMerge 测试按顺序合并多个配置文件
+func TestLoadConfigMerge(t *testing.T) {
+	dir := t.TempDir()
+	common := filepath.Join(dir, "common.yaml")
+	host := filepath.Join(dir, "host.yaml")
+	os.WriteFile(common, []byte(`
+lang: zh
+dnsconf:
+    - name: home
+      dns:
+        name: alidns
+    - name: office
+      dns:
+        name: dnspod
+`), 0600)
+	os.WriteFile(host, []byte(`
+lang: en
+dnsconf:
+    - name: home
+      dns:
+        name: cloudflare
+    - name: nas
+      dns:
+        name: dynv6
+`), 0600)
+
+	os.Setenv(util.ConfigFilePathENV, common+","+host)
+	defer os.Unsetenv(util.ConfigFilePathENV)
+
+	var conf Config
+	if err := loadConfig(&conf); err != nil {
+		t.Fatalf("合并失败: %s", err)
+	}
+	if conf.Lang != "en" {
+		t.Errorf("期待 lang 为 en, 得到 %s", conf.Lang)
+	}
+	expected := map[string]string{"home": "cloudflare", "office": "dnspod", "nas": "dynv6"}
+	if len(conf.DnsConf) != len(expected) {
+		t.Fatalf("期待 %d 个配置, 得到 %d", len(expected), len(conf.DnsConf))
+	}
+	for _, dc := range conf.DnsConf {
+		if expected[dc.Name] != dc.DNS.Name {
+			t.Errorf("%s 期待 %s, 得到 %s", dc.Name, expected[dc.Name], dc.DNS.Name)
+		}
+	}
+	if getSaveFilePath() != host {
+		t.Errorf("期待保存到 %s, 得到 %s", host, getSaveFilePath())
+	}
+
+	// 目录按文件名排序
+	os.Setenv(util.ConfigFilePathENV, dir)
+	conf = Config{}
+	if err := loadConfig(&conf); err != nil || conf.Lang != "en" {
+		t.Errorf("从目录合并失败: %v %s", err, conf.Lang)
+	}
+}
+
+// TestSaveConfigMerge 测试多个配置文件时不保存, 单个配置文件保存后重新读取再保存内容不变
+func TestSaveConfigMerge(t *testing.T) {
+	dir := t.TempDir()
+	common := filepath.Join(dir, "common.yaml")
+	host := filepath.Join(dir, "host.yaml")
+	os.WriteFile(common, []byte(`
+dnsconf:
+    - dns:
+        name: alidns
+`), 0600)
+	hostContent := `
+dnsconf:
+    - name: nas
+      dns:
+        name: dynv6
+`
+	os.WriteFile(host, []byte(hostContent), 0600)
+	defer os.Unsetenv(util.ConfigFilePathENV)
+
+	saveAndReload := func() []byte {
+		cache.ConfigSingle = nil
+		conf, err := GetConfigCached()
+		if err != nil {
+			t.Fatalf("读取失败: %s", err)
+		}
+		if err = conf.SaveConfig(); err != nil {
+			t.Fatalf("保存失败: %s", err)
+		}
+		byt, _ := os.ReadFile(getSaveFilePath())
+		return byt
+	}
+
+	os.Setenv(util.ConfigFilePathENV, common+","+host)
+	cache.ConfigSingle = nil
+	conf, err := GetConfigCached()
+	if err != nil || len(conf.DnsConf) != 2 {
+		t.Fatalf("合并失败: %v %d", err, len(conf.DnsConf))
+	}
+	if err = conf.SaveConfig(); err == nil {
+		t.Errorf("期待多个配置文件时不能保存")
+	}
+	if byt, _ := os.ReadFile(host); string(byt) != hostContent {
+		t.Errorf("期待最后一个配置文件未被修改")
+	}
+
+	os.Setenv(util.ConfigFilePathENV, common)
+	first := saveAndReload()
+	if second := saveAndReload(); string(first) != string(second) {
+		t.Errorf("期待保存后重新读取再保存内容不变, 得到\n%s\n%s", first, second)
+	}
+	cache.ConfigSingle = nil
+}
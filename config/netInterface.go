@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"net"
+
+	"github.com/jeessy2/ddns-go/v6/util"
 )
 
 // NetInterface 本机网络
@@ -11,6 +13,44 @@ type NetInterface struct {
 	Address []string
 }
 
+// getAddrFromDefaultRoute 从默认路由所在的网卡获得IP
+// 优先使用默认路由的本地地址, 否则使用该网卡的第一个地址
+func getAddrFromDefaultRoute(addrType string) string {
+	network := "udp4"
+	if addrType == "IPv6" {
+		network = "udp6"
+	}
+	name, addr, err := util.GetDefaultRouteAddr(network)
+	if err != nil {
+		util.Log("从默认路由获取%s失败! 异常信息: %s", addrType, err)
+		return ""
+	}
+
+	ipv4, ipv6, err := GetNetInterface()
+	if err != nil {
+		util.Log("从默认路由获取%s失败! 异常信息: %s", addrType, err)
+		return ""
+	}
+	netInterfaces := ipv4
+	if addrType == "IPv6" {
+		netInterfaces = ipv6
+	}
+	for _, netInterface := range netInterfaces {
+		if netInterface.Name != name {
+			continue
+		}
+		for _, address := range netInterface.Address {
+			if address == addr.String() {
+				return address
+			}
+		}
+		return netInterface.Address[0]
+	}
+
+	util.Log("默认路由所在的网卡 %s 没有%s公网地址", name, addrType)
+	return ""
+}
+
 // GetNetInterface 获得网卡地址
 // 返回ipv4, ipv6地址
 func GetNetInterface() (ipv4NetInterfaces []NetInterface, ipv6NetInterfaces []NetInterface, err error) {
@@ -67,3 +107,38 @@ func GetNetInterface() (ipv4NetInterfaces []NetInterface, ipv6NetInterfaces []Ne
 
 	return ipv4NetInterfaces, ipv6NetInterfaces, nil
 }
+
+// interfaceUsable 网卡是否已启用且有公网地址
+// IPv4 需为全局单播地址, IPv6 需为 2000::/3 的地址
+func interfaceUsable(flags net.Flags, addrs []net.Addr, addrType string) bool {
+	if flags&net.FlagUp == 0 || flags&net.FlagRunning == 0 {
+		return false
+	}
+	for _, address := range addrs {
+		ipnet, ok := address.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if addrType == "IPv4" {
+			if ipnet.IP.To4() != nil && ipnet.IP.IsGlobalUnicast() {
+				return true
+			}
+		} else if util.GetIPv6Scope(ipnet.IP) == util.IPv6ScopeGlobal {
+			return true
+		}
+	}
+	return false
+}
+
+// checkInterfaceUp 网卡未启用或没有公网地址时跳过本次更新, 避免网络切换时更新为临时地址
+func checkInterfaceUp(name string, addrType string) bool {
+	iface, err := net.InterfaceByName(name)
+	if err == nil {
+		addrs, _ := iface.Addrs()
+		if interfaceUsable(iface.Flags, addrs, addrType) {
+			return true
+		}
+	}
+	util.Log("网卡 %s 未启用或没有公网%s地址, 跳过本次更新", name, addrType)
+	return false
+}
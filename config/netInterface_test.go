@@ -1,6 +1,7 @@
 package config
 
 import (
+	"net"
 	"testing"
 )
 
@@ -11,3 +12,42 @@ func TestGetNetInterface(t *testing.T) {
 	}
 	t.Log(ipv4NetInterfaces, ipv6NetInterfaces)
 }
+
+// TestInterfaceUsable 测试网卡是否已启用且有公网地址
+func TestInterfaceUsable(t *testing.T) {
+	addrs := func(cidrs ...string) (result []net.Addr) {
+		for _, cidr := range cidrs {
+			ip, ipnet, _ := net.ParseCIDR(cidr)
+			ipnet.IP = ip
+			result = append(result, ipnet)
+		}
+		return
+	}
+	up := net.FlagUp | net.FlagRunning
+
+	tests := []struct {
+		name     string
+		flags    net.Flags
+		addrs    []net.Addr
+		addrType string
+		want     bool
+	}{
+		{"IPv4", up, addrs("192.168.1.2/24"), "IPv4", true},
+		{"IPv4 down", net.FlagUp, addrs("192.168.1.2/24"), "IPv4", false},
+		{"IPv4 link-local", up, addrs("169.254.1.2/16"), "IPv4", false},
+		{"IPv6", up, addrs("fe80::1/64", "2001:db8::1/64"), "IPv6", true},
+		{"IPv6 link-local", up, addrs("fe80::1/64", "fd00::1/64"), "IPv6", false},
+		{"IPv6 down", 0, addrs("2001:db8::1/64"), "IPv6", false},
+		{"IPv6 only IPv4", up, addrs("192.168.1.2/24"), "IPv6", false},
+	}
+
+	for _, tt := range tests {
+		if got := interfaceUsable(tt.flags, tt.addrs, tt.addrType); got != tt.want {
+			t.Errorf("%s 期待 %v, 得到 %v", tt.name, tt.want, got)
+		}
+	}
+
+	if checkInterfaceUp("ddns-go-not-exist", "IPv4") {
+		t.Errorf("不存在的网卡期待跳过")
+	}
+}
@@ -0,0 +1,253 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// Notify 通知服务, 与Webhook在相同的时机发送
+type Notify struct {
+	// 通知内容, 支持与Webhook相同的变量, 为空时使用默认内容
+	NotifyTemplate string
+	// Pushover 的应用令牌及用户密钥
+	PushoverToken string
+	PushoverUser  string
+	// Gotify 的服务地址及应用令牌
+	GotifyURL   string
+	GotifyToken string
+	// Matrix 的服务地址、访问令牌及房间
+	MatrixHomeserver  string
+	MatrixAccessToken string
+	MatrixRoomID      string
+	// Server酱 的 SendKey
+	ServerChanSendKey string
+	// PushDeer 的 PushKey
+	PushDeerKey string
+}
+
+// 通知服务的接口地址
+var (
+	pushoverEndpoint   = "https://api.pushover.net/1/messages.json"
+	serverChanEndpoint = "https://sctapi.ftqq.com/"
+	pushDeerEndpoint   = "https://api2.pushdeer.com/message/push"
+)
+
+// notifyTitle 通知标题
+const notifyTitle = "ddns-go"
+
+// defaultNotifyTemplate 默认的通知内容, 按配置的语言翻译
+const defaultNotifyTemplate = "IPv4: #{ipv4Addr} #{ipv4Result}, 域名: #{ipv4Domains}\nIPv6: #{ipv6Addr} #{ipv6Result}, 域名: #{ipv6Domains}"
+
+// notifier 通知服务
+type notifier struct {
+	name    string
+	enabled func(conf *Config) bool
+	send    func(conf *Config, message string) ([]byte, error)
+}
+
+var notifiers = []notifier{
+	{
+		name:    "Pushover",
+		enabled: func(conf *Config) bool { return conf.PushoverToken != "" && conf.PushoverUser != "" },
+		send:    sendPushover,
+	},
+	{
+		name:    "Gotify",
+		enabled: func(conf *Config) bool { return conf.GotifyURL != "" && conf.GotifyToken != "" },
+		send:    sendGotify,
+	},
+	{
+		name: "Matrix",
+		enabled: func(conf *Config) bool {
+			return conf.MatrixHomeserver != "" && conf.MatrixAccessToken != "" && conf.MatrixRoomID != ""
+		},
+		send: sendMatrix,
+	},
+	{
+		name:    "Server酱",
+		enabled: func(conf *Config) bool { return conf.ServerChanSendKey != "" },
+		send:    sendServerChan,
+	},
+	{
+		name:    "PushDeer",
+		enabled: func(conf *Config) bool { return conf.PushDeerKey != "" },
+		send:    sendPushDeer,
+	},
+}
+
+// matrixTxnCounter 同一时刻发送多条消息时区分事务ID
+var matrixTxnCounter atomic.Uint64
+
+// hasNotifier 是否配置了通知服务
+func hasNotifier(conf *Config) bool {
+	for _, n := range notifiers {
+		if n.enabled(conf) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendNotify 发送通知到已配置的通知服务
+func sendNotify(domains *Domains, conf *Config, v4Status updateStatusType, v6Status updateStatusType) {
+	tmpl := conf.NotifyTemplate
+	if tmpl == "" {
+		tmpl = util.LangStr(conf.Lang, defaultNotifyTemplate)
+	}
+	message := replacePara(domains, tmpl, v4Status, v6Status, conf.Lang)
+
+	for _, n := range notifiers {
+		if !n.enabled(conf) {
+			continue
+		}
+		body, err := n.send(conf, message)
+		if err == nil {
+			util.Log("%s通知发送成功! 返回数据：%s", n.name, string(body))
+		} else {
+			util.Log("%s通知发送失败! 异常信息：%s", n.name, err)
+		}
+	}
+}
+
+// sendPushover 发送 Pushover 通知
+// https://pushover.net/api
+func sendPushover(conf *Config, message string) ([]byte, error) {
+	form := url.Values{}
+	form.Set("token", conf.PushoverToken)
+	form.Set("user", conf.PushoverUser)
+	form.Set("title", notifyTitle)
+	form.Set("message", message)
+
+	req, err := http.NewRequest(http.MethodPost, pushoverEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	clt := util.CreateHTTPClient()
+	resp, err := clt.Do(req)
+	return util.GetHTTPResponseOrg(resp, err)
+}
+
+// sendGotify 发送 Gotify 通知
+// https://gotify.net/docs/pushmsg
+func sendGotify(conf *Config, message string) ([]byte, error) {
+	byt, _ := json.Marshal(map[string]interface{}{
+		"title":    notifyTitle,
+		"message":  message,
+		"priority": 5,
+	})
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(conf.GotifyURL, "/")+"/message", bytes.NewReader(byt))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Gotify-Key", conf.GotifyToken)
+
+	clt := util.CreateHTTPClient()
+	resp, err := clt.Do(req)
+	return util.GetHTTPResponseOrg(resp, err)
+}
+
+// sendMatrix 发送 Matrix 消息
+// https://spec.matrix.org/v1.11/client-server-api/#put_matrixclientv3roomsroomidsendeventtypetxnid
+func sendMatrix(conf *Config, message string) ([]byte, error) {
+	byt, _ := json.Marshal(map[string]string{
+		"msgtype": "m.text",
+		"body":    message,
+	})
+
+	// 事务ID在同一访问令牌下需唯一, 重试时使用相同的事务ID, 服务端不会重复发送
+	txnID := fmt.Sprintf("ddns-go-%d-%d", time.Now().UnixNano(), matrixTxnCounter.Add(1))
+	requestURL := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimSuffix(conf.MatrixHomeserver, "/"), url.PathEscape(conf.MatrixRoomID), txnID)
+
+	var body []byte
+	var err error
+	for i := 0; i < 2; i++ {
+		var req *http.Request
+		req, err = http.NewRequest(http.MethodPut, requestURL, bytes.NewReader(byt))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+conf.MatrixAccessToken)
+
+		clt := util.CreateHTTPClient()
+		var resp *http.Response
+		resp, err = clt.Do(req)
+		body, err = util.GetHTTPResponseOrg(resp, err)
+		// 只在未收到响应时重试
+		if err == nil || resp != nil {
+			break
+		}
+	}
+	return body, err
+}
+
+// sendServerChan 发送 Server酱 通知
+// https://sct.ftqq.com/sendkey
+func sendServerChan(conf *Config, message string) ([]byte, error) {
+	form := url.Values{}
+	form.Set("title", notifyTitle)
+	form.Set("desp", message)
+	return postNotifyForm(serverChanEndpoint+url.PathEscape(conf.ServerChanSendKey)+".send", form)
+}
+
+// sendPushDeer 发送 PushDeer 通知
+// https://www.pushdeer.com/dev.html
+func sendPushDeer(conf *Config, message string) ([]byte, error) {
+	form := url.Values{}
+	form.Set("pushkey", conf.PushDeerKey)
+	form.Set("text", notifyTitle)
+	form.Set("desp", message)
+	form.Set("type", "markdown")
+	return postNotifyForm(pushDeerEndpoint, form)
+}
+
+// postNotifyForm 以表单提交通知, 返回的 code 不为0时返回异常
+func postNotifyForm(endpoint string, form url.Values) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	clt := util.CreateHTTPClient()
+	resp, err := clt.Do(req)
+	body, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		return body, err
+	}
+	return body, checkNotifyCode(body)
+}
+
+// checkNotifyCode 检查返回的 code, 如 {"code":0,"message":""} 或 {"code":80501,"error":"invalid pushkey"}
+func checkNotifyCode(body []byte) error {
+	var result struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return errors.New(util.LogStr("返回内容: %s", string(body)))
+	}
+	if result.Code != 0 {
+		msg := result.Message
+		if msg == "" {
+			msg = result.Error
+		}
+		return fmt.Errorf("%s (%d)", msg, result.Code)
+	}
+	return nil
+}
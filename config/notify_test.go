@@ -0,0 +1,165 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestSendNotify 测试发送 Pushover 及 Gotify 通知
+func TestSendNotify(t *testing.T) {
+	var pushoverMsg, gotifyMsg, gotifyKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/1/messages.json":
+			r.ParseForm()
+			if r.PostForm.Get("token") != "app-token" || r.PostForm.Get("user") != "user-key" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"status":0,"errors":["application token is invalid"]}`))
+				return
+			}
+			pushoverMsg = r.PostForm.Get("message")
+			w.Write([]byte(`{"status":1,"request":"5042853c"}`))
+		case "/gotify/message":
+			var data struct {
+				Message string `json:"message"`
+			}
+			json.NewDecoder(r.Body).Decode(&data)
+			gotifyMsg = data.Message
+			gotifyKey = r.Header.Get("X-Gotify-Key")
+			w.Write([]byte(`{"id":1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	oldEndpoint := pushoverEndpoint
+	pushoverEndpoint = server.URL + "/1/messages.json"
+	defer func() { pushoverEndpoint = oldEndpoint }()
+
+	conf := &Config{Notify: Notify{
+		NotifyTemplate: "#{ipv4Addr} #{ipv4Domains}",
+		PushoverToken:  "app-token",
+		PushoverUser:   "user-key",
+		GotifyURL:      server.URL + "/gotify/",
+		GotifyToken:    "gotify-token",
+	}}
+	domains := &Domains{
+		Ipv4Addr:    "1.2.3.4",
+		Ipv4Domains: []*Domain{{DomainName: "example.com", SubDomain: "www", UpdateStatus: UpdatedSuccess}},
+	}
+
+	if !hasNotifier(conf) {
+		t.Fatal("期待已配置通知服务")
+	}
+	sendNotify(domains, conf, UpdatedSuccess, UpdatedNothing)
+
+	expected := "1.2.3.4 www.example.com"
+	if pushoverMsg != expected {
+		t.Errorf("Pushover 期待 %s, 得到 %s", expected, pushoverMsg)
+	}
+	if gotifyMsg != expected || gotifyKey != "gotify-token" {
+		t.Errorf("Gotify 期待 %s, 得到 %s %s", expected, gotifyMsg, gotifyKey)
+	}
+
+	// 只配置了令牌时不发送
+	if hasNotifier(&Config{Notify: Notify{PushoverToken: "app-token"}}) {
+		t.Error("未配置用户密钥时不应发送 Pushover 通知")
+	}
+}
+
+// TestSendMatrix 测试发送 Matrix 消息, 每条消息的事务ID不同
+func TestSendMatrix(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"errcode":"M_UNKNOWN_TOKEN","error":"Invalid access token"}`))
+			return
+		}
+		paths = append(paths, r.URL.EscapedPath())
+		w.Write([]byte(`{"event_id":"$event"}`))
+	}))
+	defer server.Close()
+
+	conf := &Config{Notify: Notify{
+		MatrixHomeserver:  server.URL + "/",
+		MatrixAccessToken: "access-token",
+		MatrixRoomID:      "!room:example.org",
+	}}
+	for i := 0; i < 2; i++ {
+		if _, err := sendMatrix(conf, "test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prefix := "/_matrix/client/v3/rooms/%21room:example.org/send/m.room.message/"
+	if len(paths) != 2 || !strings.HasPrefix(paths[0], prefix) || !strings.HasPrefix(paths[1], prefix) {
+		t.Fatalf("请求地址不正确: %v", paths)
+	}
+	if paths[0] == paths[1] {
+		t.Errorf("事务ID应不同, 得到 %s", paths[0])
+	}
+
+	conf.MatrixAccessToken = "wrong"
+	if _, err := sendMatrix(conf, "test"); err == nil {
+		t.Error("令牌不正确时期待返回异常")
+	}
+}
+
+// TestSendServerChanPushDeer 测试发送 Server酱 及 PushDeer 通知, code 不为0时返回异常
+func TestSendServerChanPushDeer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch {
+		case r.URL.Path == "/SCT123.send" && r.PostForm.Get("desp") == "test":
+			w.Write([]byte(`{"code":0,"message":"","data":{"pushid":"1"}}`))
+		case r.URL.Path == "/message/push" && r.PostForm.Get("pushkey") == "PDU123":
+			w.Write([]byte(`{"code":0,"content":{"result":["{}"]}}`))
+		case r.URL.Path == "/message/push":
+			w.Write([]byte(`{"code":80501,"error":"invalid pushkey"}`))
+		default:
+			w.Write([]byte(`{"code":40001,"message":"bad pushtoken"}`))
+		}
+	}))
+	defer server.Close()
+
+	oldServerChan, oldPushDeer := serverChanEndpoint, pushDeerEndpoint
+	serverChanEndpoint, pushDeerEndpoint = server.URL+"/", server.URL+"/message/push"
+	defer func() { serverChanEndpoint, pushDeerEndpoint = oldServerChan, oldPushDeer }()
+
+	if _, err := sendServerChan(&Config{Notify: Notify{ServerChanSendKey: "SCT123"}}, "test"); err != nil {
+		t.Errorf("Server酱 期待成功, 得到 %s", err)
+	}
+	if _, err := sendServerChan(&Config{Notify: Notify{ServerChanSendKey: "wrong"}}, "test"); err == nil || err.Error() != "bad pushtoken (40001)" {
+		t.Errorf("Server酱 期待异常 bad pushtoken (40001), 得到 %v", err)
+	}
+	if _, err := sendPushDeer(&Config{Notify: Notify{PushDeerKey: "PDU123"}}, "test"); err != nil {
+		t.Errorf("PushDeer 期待成功, 得到 %s", err)
+	}
+	if _, err := sendPushDeer(&Config{Notify: Notify{PushDeerKey: "wrong"}}, "test"); err == nil || err.Error() != "invalid pushkey (80501)" {
+		t.Errorf("PushDeer 期待异常 invalid pushkey (80501), 得到 %v", err)
+	}
+}
+
+// TestNotifyLang 测试默认通知内容及结果按配置的语言翻译
+func TestNotifyLang(t *testing.T) {
+	domains := &Domains{
+		Ipv4Addr:    "1.2.3.4",
+		Ipv4Domains: []*Domain{{DomainName: "example.com", UpdateStatus: UpdatedSuccess}},
+	}
+	for lang, expected := range map[string]string{
+		"zh": "IPv4: 1.2.3.4 成功, 域名: example.com\nIPv6:  未改变, 域名: ",
+		"en": "IPv4: 1.2.3.4 success, domains: example.com\nIPv6:  no changed, domains: ",
+	} {
+		message := replacePara(domains, util.LangStr(lang, defaultNotifyTemplate), UpdatedSuccess, UpdatedNothing, lang)
+		if message != expected {
+			t.Errorf("%s 期待 %q, 得到 %q", lang, expected, message)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package config
+
+import "github.com/jeessy2/ddns-go/v6/util"
+
+// skipWritten 只更新IP变化的域名时, 跳过上次已成功更新为当前IP的域名
+// forced 为 true 时为达到缓存次数的强制比对, 不跳过. 全部跳过时返回的IP为空, 不调用服务商接口
+func (domains *Domains) skipWritten(cache *util.IpCache, ipAddr string, forced bool, domainArr []*Domain) (string, []*Domain) {
+	if !domains.OnlyChanged || forced || ipAddr == "" {
+		return ipAddr, domainArr
+	}
+
+	var pending []*Domain
+	for _, domain := range domainArr {
+		if cache.Written[domain.String()] == ipAddr {
+			util.Log("域名 %s 已更新为 %s, 跳过更新", domain, ipAddr)
+			domain.UpdateStatus = UpdatedNothing
+			continue
+		}
+		pending = append(pending, domain)
+	}
+
+	if len(pending) == 0 {
+		return "", domainArr
+	}
+	return ipAddr, pending
+}
+
+// RecordWritten 记录本次已是当前IP的域名, 更新失败的域名下次重新更新
+func (domains *Domains) RecordWritten() {
+	record := func(cache *util.IpCache, ipAddr string, domainArr []*Domain) {
+		if cache == nil || ipAddr == "" {
+			return
+		}
+		for _, domain := range domainArr {
+			switch domain.UpdateStatus {
+			case UpdatedSuccess, UpdatedNothing:
+				if cache.Written == nil {
+					cache.Written = map[string]string{}
+				}
+				cache.Written[domain.String()] = ipAddr
+			case UpdatedFailed:
+				delete(cache.Written, domain.String())
+			}
+		}
+	}
+	record(domains.Ipv4Cache, domains.Ipv4Addr, domains.Ipv4Domains)
+	record(domains.Ipv6Cache, domains.Ipv6Addr, domains.Ipv6Domains)
+}
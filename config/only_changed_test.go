@@ -0,0 +1,78 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestOnlyChanged 测试只更新IP变化的类型及域名, 达到缓存次数时仍全部比对
+func TestOnlyChanged(t *testing.T) {
+	v4 := &Domain{DomainName: "example.com", SubDomain: "v4"}
+	a := &Domain{DomainName: "example.com", SubDomain: "a"}
+	b := &Domain{DomainName: "example.com", SubDomain: "b"}
+	v4Cache := &util.IpCache{Addr: "192.0.2.1", Times: 5}
+	v6Cache := &util.IpCache{Addr: "2001:db8::1", Times: 5}
+	newDomains := func(ipv6Addr string) *Domains {
+		for _, d := range []*Domain{v4, a, b} {
+			d.UpdateStatus = ""
+		}
+		return &Domains{
+			Ipv4Addr: "192.0.2.1", Ipv4Cache: v4Cache, Ipv4Domains: []*Domain{v4},
+			Ipv6Addr: ipv6Addr, Ipv6Cache: v6Cache, Ipv6Domains: []*Domain{a, b},
+			OnlyChanged: true,
+		}
+	}
+
+	// 只有IPv6变化时不更新IPv4
+	domains := newDomains("2001:db8::2")
+	if ipAddr, _ := domains.GetNewIpResult("A"); ipAddr != "" {
+		t.Errorf("期待IPv4未改变时不更新, 得到 %s", ipAddr)
+	}
+	ipAddr, pending := domains.GetNewIpResult("AAAA")
+	if ipAddr != "2001:db8::2" || len(pending) != 2 {
+		t.Fatalf("期待更新IPv6的全部域名, 得到 %s %v", ipAddr, pending)
+	}
+	a.UpdateStatus, b.UpdateStatus = UpdatedSuccess, UpdatedFailed
+	domains.RecordWritten()
+	if _, ok := v4Cache.Written[v4.String()]; ok {
+		t.Errorf("期待未更新的IPv4域名不记录")
+	}
+	// 与 runDnsConf 相同, 失败时重置缓存并保留已更新的域名
+	*v6Cache = util.IpCache{Written: v6Cache.Written}
+
+	// 重试时只更新失败的域名
+	domains = newDomains("2001:db8::2")
+	ipAddr, pending = domains.GetNewIpResult("AAAA")
+	if ipAddr != "2001:db8::2" || len(pending) != 1 || pending[0] != b {
+		t.Fatalf("期待只重试 %s, 得到 %s %v", b, ipAddr, pending)
+	}
+	if a.UpdateStatus != UpdatedNothing {
+		t.Errorf("期待 %s 未改变, 得到 %s", a, a.UpdateStatus)
+	}
+	b.UpdateStatus = UpdatedSuccess
+	domains.RecordWritten()
+
+	// 全部已更新时不调用服务商接口
+	*v6Cache = util.IpCache{Written: v6Cache.Written}
+	domains = newDomains("2001:db8::2")
+	if ipAddr, _ := domains.GetNewIpResult("AAAA"); ipAddr != "" {
+		t.Errorf("期待全部已更新时不调用服务商接口, 得到 %s", ipAddr)
+	}
+
+	// 达到缓存次数时仍全部比对
+	v6Cache.Times = 1
+	domains = newDomains("2001:db8::2")
+	ipAddr, pending = domains.GetNewIpResult("AAAA")
+	if ipAddr != "2001:db8::2" || len(pending) != 2 {
+		t.Errorf("期待达到缓存次数时全部比对, 得到 %s %v", ipAddr, pending)
+	}
+
+	// 未开启时与之前相同
+	*v6Cache = util.IpCache{Written: v6Cache.Written}
+	domains = newDomains("2001:db8::2")
+	domains.OnlyChanged = false
+	if _, pending := domains.GetNewIpResult("AAAA"); len(pending) != 2 {
+		t.Errorf("期待未开启时全部比对, 得到 %v", pending)
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"errors"
+	"net/netip"
+	"os"
+	"regexp"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// defaultPrefixSuffix 未填写后缀时使用的接口标识
+const defaultPrefixSuffix = "::1"
+
+// prefixReg 匹配 IPv6 前缀, 如 odhcp6c 的 PREFIXES=2001:db8::/56,3600,7200、dhcpcd 的 delegated_prefix=2001:db8::/56、dhclient 的 iaprefix 2001:db8::/56
+var prefixReg = regexp.MustCompile(`[0-9A-Fa-f:]*:[0-9A-Fa-f:]*/\d{1,3}`)
+
+// parsePrefix 返回内容中第一个可用的 IPv6 前缀, 跳过链路本地等地址
+func parsePrefix(content string) (netip.Prefix, bool) {
+	for _, s := range prefixReg.FindAllString(content, -1) {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil || !prefix.Addr().Is6() || !prefix.Addr().IsGlobalUnicast() {
+			continue
+		}
+		return prefix.Masked(), true
+	}
+	return netip.Prefix{}, false
+}
+
+// parsePrefixSuffix 解析后缀, 为空时使用 ::1
+func parsePrefixSuffix(suffix string) (netip.Addr, error) {
+	if suffix == "" {
+		suffix = defaultPrefixSuffix
+	}
+	addr, err := netip.ParseAddr(suffix)
+	if err != nil || !addr.Is6() || addr.Zone() != "" {
+		return netip.Addr{}, errors.New(util.LogStr("后缀 %s 不正确, 如: ::1", suffix))
+	}
+	return addr, nil
+}
+
+// composePrefix 使用前缀的网络位及后缀的主机位组合地址
+func composePrefix(prefix netip.Prefix, suffix netip.Addr) netip.Addr {
+	p := prefix.Addr().As16()
+	s := suffix.As16()
+	bits := prefix.Bits()
+	for i := range p {
+		// 当前字节中属于前缀的位
+		n := min(max(bits-i*8, 0), 8)
+		mask := byte(0xff << (8 - n))
+		p[i] = p[i]&mask | s[i]&^mask
+	}
+	return netip.AddrFrom16(p)
+}
+
+// getIpv6AddrFromPrefixFile 从 DHCPv6-PD 的前缀文件获取前缀, 与后缀组合为 IPv6 地址
+func (conf *DnsConfig) getIpv6AddrFromPrefixFile() string {
+	suffix, err := parsePrefixSuffix(conf.Ipv6.PrefixSuffix)
+	if err != nil {
+		util.Log(err.Error())
+		return ""
+	}
+	content, err := os.ReadFile(conf.Ipv6.PrefixFile)
+	if err != nil {
+		util.Log("读取前缀文件 %s 失败: %s", conf.Ipv6.PrefixFile, err)
+		return ""
+	}
+	prefix, ok := parsePrefix(string(content))
+	if !ok {
+		util.Log("前缀文件 %s 中没有找到IPv6前缀", conf.Ipv6.PrefixFile)
+		return ""
+	}
+	addr := composePrefix(prefix, suffix)
+	util.Log("从文件 %s 获取到前缀 %s, 组合后的地址: %s", conf.Ipv6.PrefixFile, prefix, addr)
+	return addr.String()
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestParsePrefix 测试解析常见的前缀文件格式
+func TestParsePrefix(t *testing.T) {
+	tests := map[string]string{
+		"2001:db8:1200::/56\n":                         "2001:db8:1200::/56",
+		"PREFIXES=2001:db8:1200::/56,3600,7200\n":      "2001:db8:1200::/56",
+		"delegated_prefix='2001:db8:1234:5600::/56'\n": "2001:db8:1234:5600::/56",
+		"  ia-pd 0a0b0c0d {\n    iaprefix 2001:db8:1200::/56 {\n      starts 1700000000;\n": "2001:db8:1200::/56",
+		// 跳过链路本地的前缀
+		"fe80::/64 2001:db8:1234:5678::/64": "2001:db8:1234:5678::/64",
+		// 主机位不为0时使用网络位
+		"2001:db8:1200::1/56": "2001:db8:1200::/56",
+	}
+	for content, want := range tests {
+		prefix, ok := parsePrefix(content)
+		if !ok || prefix.String() != want {
+			t.Errorf("%q 期待 %s, 得到 %s", content, want, prefix)
+		}
+	}
+	if _, ok := parsePrefix("address 192.168.1.1/24"); ok {
+		t.Errorf("期待没有IPv6前缀")
+	}
+}
+
+// TestComposePrefix 测试前缀与后缀组合
+func TestComposePrefix(t *testing.T) {
+	tests := []struct {
+		prefix, suffix, want string
+	}{
+		{"2001:db8:1200::/56", "::1", "2001:db8:1200::1"},
+		{"2001:db8:1200::/56", "::1:0:0:0:1", "2001:db8:1200:1::1"},
+		{"2001:db8:1200::/60", "::a:0:0:0:1", "2001:db8:1200:a::1"},
+		// 后缀中属于前缀的位被忽略
+		{"2001:db8:1234:5678::/64", "2001:db8::a:b:c:d", "2001:db8:1234:5678:a:b:c:d"},
+	}
+	for _, tt := range tests {
+		suffix, err := parsePrefixSuffix(tt.suffix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := composePrefix(netip.MustParsePrefix(tt.prefix), suffix).String(); got != tt.want {
+			t.Errorf("%s + %s 期待 %s, 得到 %s", tt.prefix, tt.suffix, tt.want, got)
+		}
+	}
+	if _, err := parsePrefixSuffix("1"); err == nil {
+		t.Errorf("期待后缀 1 不正确")
+	}
+}
+
+// TestGetIpv6AddrFromPrefixFile 测试从前缀文件获取IPv6
+func TestGetIpv6AddrFromPrefixFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "prefix")
+	if err := os.WriteFile(file, []byte("PREFIXES=2001:db8:1200::/56,3600,7200\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	dc := DnsConfig{}
+	dc.Ipv6.GetType = "prefixFile"
+	dc.Ipv6.PrefixFile = file
+	if addr := dc.GetIpv6Addr(); addr != "2001:db8:1200::1" {
+		t.Errorf("期待使用默认后缀 ::1, 得到 %s", addr)
+	}
+	dc.Ipv6.PrefixFile = file + ".missing"
+	if addr := dc.GetIpv6Addr(); addr != "" {
+		t.Errorf("期待文件不存在时为空, 得到 %s", addr)
+	}
+}
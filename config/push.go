@@ -0,0 +1,56 @@
+package config
+
+import (
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// pushedAddr 通过 /api/update 推送的IP
+var pushedAddr struct {
+	sync.RWMutex
+	ipv4 string
+	ipv6 string
+}
+
+// CheckPushedAddr 校验推送的IP, addrType 为 IPv4 或 IPv6
+func CheckPushedAddr(addr string, addrType string) error {
+	ip := net.ParseIP(addr)
+	isIPv4 := ip != nil && ip.To4() != nil
+	if ip == nil || isIPv4 != (addrType == "IPv4") {
+		return errors.New(util.LogStr("推送的%s %s 不正确", addrType, addr))
+	}
+	// 与获取IP时相同, 需为单播地址
+	if !ip.IsGlobalUnicast() {
+		return errors.New(util.LogStr("推送的%s %s 不正确", addrType, addr))
+	}
+	return nil
+}
+
+// SetPushedAddr 保存推送的IP, 为空的不修改
+func SetPushedAddr(ipv4Addr string, ipv6Addr string) {
+	pushedAddr.Lock()
+	defer pushedAddr.Unlock()
+	if ipv4Addr != "" {
+		pushedAddr.ipv4 = ipv4Addr
+	}
+	if ipv6Addr != "" {
+		pushedAddr.ipv6 = ipv6Addr
+	}
+}
+
+// getPushedAddr 获得推送的IP
+func getPushedAddr(addrType string) string {
+	pushedAddr.RLock()
+	defer pushedAddr.RUnlock()
+	addr := pushedAddr.ipv4
+	if addrType == "IPv6" {
+		addr = pushedAddr.ipv6
+	}
+	if addr == "" {
+		util.Log("尚未收到推送的%s", addrType)
+	}
+	return addr
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"gopkg.in/yaml.v3"
+)
+
+// IsConfigURL 是否为远程配置地址, 如 https://example.com/ddns_go_config.yaml
+func IsConfigURL(path string) bool {
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+// GetRemoteConfigCachePath 获得远程配置的本地缓存路径
+func GetRemoteConfigCachePath() string {
+	return strings.TrimSuffix(util.GetConfigFilePathDefault(), ".yaml") + "_remote.yaml"
+}
+
+// isRemoteConfig 是否使用远程配置的本地缓存, 下次获取时会覆盖缓存
+func isRemoteConfig() bool {
+	return util.GetConfigFilePath() == GetRemoteConfigCachePath()
+}
+
+// FetchRemoteConfig 从URL获取配置并缓存到本地, 返回本地缓存路径
+// 获取失败时返回异常, 仍可使用上次的缓存启动
+func FetchRemoteConfig(configURL string, headers string) (cachePath string, err error) {
+	cachePath = GetRemoteConfigCachePath()
+
+	req, err := http.NewRequest(http.MethodGet, configURL, http.NoBody)
+	if err != nil {
+		return
+	}
+	for k, v := range ExtractHeaders(headers) {
+		req.Header.Set(k, v)
+	}
+
+	clt := util.CreateHTTPClient()
+	clt.Timeout = 30 * time.Second
+	resp, err := clt.Do(req)
+	byt, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		return
+	}
+
+	// 校验配置文件, 防止缓存错误的内容
+	plain, err := decodeConfig(byt)
+	if err != nil {
+		return
+	}
+	err = yaml.Unmarshal(plain, &Config{})
+	if err != nil {
+		return
+	}
+
+	err = os.WriteFile(cachePath, byt, 0600)
+	if err != nil {
+		return
+	}
+	util.Log("已从 %s 获取配置, 缓存在: %s", configURL, cachePath)
+	return
+}
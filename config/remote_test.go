@@ -0,0 +1,35 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestSaveConfigRemote 测试使用远程配置时不能保存, 以免保存到缓存后被下次获取覆盖
+func TestSaveConfigRemote(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	cachePath := GetRemoteConfigCachePath()
+	content := `
+dnsconf:
+    - dns:
+        name: alidns
+`
+	os.WriteFile(cachePath, []byte(content), 0600)
+	t.Setenv(util.ConfigFilePathENV, cachePath)
+	cache.ConfigSingle = nil
+	defer func() { cache.ConfigSingle = nil }()
+
+	conf, err := GetConfigCached()
+	if err != nil {
+		t.Fatalf("读取失败: %s", err)
+	}
+	conf.Paused = true
+	if err = conf.SaveConfig(); err == nil {
+		t.Error("期待使用远程配置时不能保存")
+	}
+	if byt, _ := os.ReadFile(cachePath); string(byt) != content {
+		t.Errorf("期待缓存不变, 得到 %s", byt)
+	}
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"sync"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// ipSource 获取IP的方式, 相同时获取到的IP相同
+type ipSource struct {
+	addrType     string
+	getType      string
+	url          string
+	netInterface string
+	cmd          string
+	ipv6Reg      string
+	scopeOrder   string
+	prefixFile   string
+	prefixSuffix string
+	requireUp    bool
+}
+
+// sharedAddr 本次运行中已获取的IP
+type sharedAddr struct {
+	once sync.Once
+	addr string
+}
+
+// sharedDetect 运行中的次数及本次运行中按获取IP方式共享的IP
+var sharedDetect struct {
+	sync.Mutex
+	running int
+	addrs   map[ipSource]*sharedAddr
+}
+
+// BeginSharedDetect 开始一次运行, 运行结束前获取IP方式相同的配置只获取一次IP, 需与 EndSharedDetect 成对调用
+func BeginSharedDetect() {
+	sharedDetect.Lock()
+	defer sharedDetect.Unlock()
+	if sharedDetect.running == 0 {
+		sharedDetect.addrs = map[ipSource]*sharedAddr{}
+	}
+	sharedDetect.running++
+}
+
+// EndSharedDetect 结束一次运行, 全部运行结束后下次重新获取IP
+func EndSharedDetect() {
+	sharedDetect.Lock()
+	defer sharedDetect.Unlock()
+	sharedDetect.running--
+	if sharedDetect.running == 0 {
+		sharedDetect.addrs = nil
+	}
+}
+
+// detectShared 运行中使用相同方式已获取的IP, 未获取过时调用 detect 获取, 不在运行中时直接获取
+func detectShared(source ipSource, detect func() string) string {
+	sharedDetect.Lock()
+	if sharedDetect.addrs == nil {
+		sharedDetect.Unlock()
+		return detect()
+	}
+	shared, ok := sharedDetect.addrs[source]
+	if !ok {
+		shared = &sharedAddr{}
+		sharedDetect.addrs[source] = shared
+	}
+	sharedDetect.Unlock()
+
+	reused := true
+	shared.once.Do(func() {
+		shared.addr = detect()
+		reused = false
+	})
+	if reused && shared.addr != "" {
+		util.Log("获取%s的方式与之前的配置相同, 使用已获取到的 %s", source.addrType, shared.addr)
+	}
+	return shared.addr
+}
+
+// getIpv4AddrShared 获得IPv4地址, 运行中与获取方式相同的配置共享
+func (conf *DnsConfig) getIpv4AddrShared() string {
+	return detectShared(ipSource{
+		addrType:     "IPv4",
+		getType:      conf.Ipv4.GetType,
+		url:          conf.Ipv4.URL,
+		netInterface: conf.Ipv4.NetInterface,
+		cmd:          conf.Ipv4.Cmd,
+		requireUp:    conf.RequireInterfaceUp,
+	}, conf.GetIpv4Addr)
+}
+
+// getIpv6AddrShared 获得IPv6地址, 运行中与获取方式相同的配置共享
+func (conf *DnsConfig) getIpv6AddrShared() string {
+	return detectShared(ipSource{
+		addrType:     "IPv6",
+		getType:      conf.Ipv6.GetType,
+		url:          conf.Ipv6.URL,
+		netInterface: conf.Ipv6.NetInterface,
+		cmd:          conf.Ipv6.Cmd,
+		ipv6Reg:      conf.Ipv6.Ipv6Reg,
+		scopeOrder:   conf.Ipv6.ScopeOrder,
+		prefixFile:   conf.Ipv6.PrefixFile,
+		prefixSuffix: conf.Ipv6.PrefixSuffix,
+		requireUp:    conf.RequireInterfaceUp,
+	}, conf.GetIpv6Addr)
+}
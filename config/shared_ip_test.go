@@ -0,0 +1,42 @@
+package config
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestSharedDetect 测试运行中获取IP方式相同的配置只获取一次IP
+func TestSharedDetect(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("192.0.2.1"))
+	}))
+	defer server.Close()
+
+	newConf := func(url string) *DnsConfig {
+		dc := &DnsConfig{}
+		dc.Ipv4.GetType = "url"
+		dc.Ipv4.URL = url
+		return dc
+	}
+
+	BeginSharedDetect()
+	for _, url := range []string{server.URL, server.URL, server.URL + "/other"} {
+		if addr := newConf(url).getIpv4AddrShared(); addr != "192.0.2.1" {
+			t.Fatalf("期待 192.0.2.1, 得到 %s", addr)
+		}
+	}
+	EndSharedDetect()
+	if n := requests.Load(); n != 2 {
+		t.Errorf("期待相同的接口只请求一次, 共请求 2 次, 得到 %d", n)
+	}
+
+	// 运行结束后重新获取
+	newConf(server.URL).getIpv4AddrShared()
+	if n := requests.Load(); n != 3 {
+		t.Errorf("期待运行结束后重新获取, 得到 %d", n)
+	}
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// sourceInternal 域名参数 source=internal 使用本机网卡的IP, 用于内外网分别解析
+// 可指定网卡, 如 source=internal:eth0, 未指定时使用默认路由的网卡
+const sourceInternal = "internal"
+
+// getDomainSource 获得域名的 source 参数, 返回是否为内网及网卡名称
+func getDomainSource(domainStr string) (internal bool, netInterface string) {
+	_, rawQuery, ok := strings.Cut(domainStr, "?")
+	if !ok {
+		return
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil || !query.Has("source") {
+		return
+	}
+	source := strings.TrimSpace(query.Get("source"))
+	name, netInterface, _ := strings.Cut(source, ":")
+	if name != sourceInternal {
+		util.Log("域名 %s 的 source 参数不正确, 将使用配置的获取IP方式", domainStr)
+		return false, ""
+	}
+	return true, strings.TrimSpace(netInterface)
+}
+
+// SplitHorizon 按域名的 source 参数拆分配置
+// 第一个为使用配置的获取IP方式的配置, 其余为使用网卡IP的内网配置, 同一网卡的域名在同一个配置中
+func (dnsConf DnsConfig) SplitHorizon() []DnsConfig {
+	external := dnsConf
+	external.Ipv4.Domains, external.Ipv6.Domains = nil, nil
+	result := []DnsConfig{external}
+	// 网卡名称 => result中的下标
+	internalIndex := make(map[string]int)
+
+	add := func(domainStr string, ipv6 bool) {
+		internal, netInterface := getDomainSource(domainStr)
+		i := 0
+		if internal {
+			var ok bool
+			if i, ok = internalIndex[netInterface]; !ok {
+				i = len(result)
+				internalIndex[netInterface] = i
+				result = append(result, dnsConf.internalConfig(netInterface))
+			}
+		}
+		if ipv6 {
+			result[i].Ipv6.Domains = append(result[i].Ipv6.Domains, domainStr)
+		} else {
+			result[i].Ipv4.Domains = append(result[i].Ipv4.Domains, domainStr)
+		}
+	}
+	for _, domainStr := range dnsConf.Ipv4.Domains {
+		add(domainStr, false)
+	}
+	for _, domainStr := range dnsConf.Ipv6.Domains {
+		add(domainStr, true)
+	}
+	return result
+}
+
+// internalConfig 使用网卡IP的内网配置, netInterface 为空时使用默认路由的网卡
+func (dnsConf DnsConfig) internalConfig(netInterface string) DnsConfig {
+	internal := dnsConf
+	internal.Ipv4.Domains, internal.Ipv6.Domains = nil, nil
+	// 托管区域及未获取到IP的处理由外网配置统一处理
+	internal.ManagedZone = false
+	internal.NoIPAction = NoIPKeep
+	getType := "auto"
+	if netInterface != "" {
+		getType = "netInterface"
+	}
+	internal.Ipv4.GetType, internal.Ipv4.NetInterface = getType, netInterface
+	internal.Ipv6.GetType, internal.Ipv6.NetInterface = getType, netInterface
+	return internal
+}
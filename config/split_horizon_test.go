@@ -0,0 +1,54 @@
+package config
+
+import "testing"
+
+// TestSplitHorizon 测试按 source 参数拆分内外网配置
+func TestSplitHorizon(t *testing.T) {
+	dc := DnsConfig{NoIPAction: NoIPNotify, ManagedZone: true}
+	dc.Ipv4.Enable = true
+	dc.Ipv4.GetType = "url"
+	dc.Ipv4.Domains = []string{
+		"www.example.com",
+		"nas.example.com?source=internal",
+		"router.example.com?source=internal:eth1&TTL=600",
+		"pc.example.com?source=internal",
+	}
+	dc.Ipv6.Enable = true
+	dc.Ipv6.GetType = "url"
+	dc.Ipv6.Domains = []string{"nas.example.com?source=internal", "other.example.com?source=unknown"}
+
+	confs := dc.SplitHorizon()
+	if len(confs) != 3 {
+		t.Fatalf("期待拆分为 3 个配置, 得到 %d 个", len(confs))
+	}
+
+	external := confs[0]
+	if external.Ipv4.GetType != "url" || len(external.Ipv4.Domains) != 1 || len(external.Ipv6.Domains) != 1 {
+		t.Errorf("外网配置不正确: %+v", external)
+	}
+	if !external.ManagedZone || external.NoIPAction != NoIPNotify {
+		t.Errorf("期待外网配置保留托管区域及未获取到IP的处理方式")
+	}
+
+	auto := confs[1]
+	if auto.Ipv4.GetType != "auto" || auto.Ipv6.GetType != "auto" {
+		t.Errorf("期待未指定网卡时使用默认路由, 得到 %s %s", auto.Ipv4.GetType, auto.Ipv6.GetType)
+	}
+	if len(auto.Ipv4.Domains) != 2 || len(auto.Ipv6.Domains) != 1 {
+		t.Errorf("期待 2 个IPv4域名及 1 个IPv6域名, 得到 %v %v", auto.Ipv4.Domains, auto.Ipv6.Domains)
+	}
+	if auto.ManagedZone || auto.NoIPAction != NoIPKeep {
+		t.Errorf("期待内网配置不处理托管区域及未获取到IP")
+	}
+
+	eth1 := confs[2]
+	if eth1.Ipv4.GetType != "netInterface" || eth1.Ipv4.NetInterface != "eth1" || len(eth1.Ipv4.Domains) != 1 {
+		t.Errorf("期待使用网卡 eth1, 得到 %+v", eth1.Ipv4)
+	}
+
+	// source 参数不传递给DNS服务商
+	parsed := checkParseDomains(eth1.Ipv4.Domains)
+	if len(parsed) != 1 || parsed[0].CustomParams != "TTL=600" {
+		t.Errorf("期待参数为 TTL=600, 得到 %v", parsed)
+	}
+}
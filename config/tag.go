@@ -0,0 +1,58 @@
+package config
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// parseTags 解析参数 tag, 可多次指定或以逗号分隔, 如 tag=home,lab
+func parseTags(values []string) (tags []string) {
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" && !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return
+}
+
+// HasTag 是否带有标签
+func (d Domain) HasTag(tag string) bool {
+	return slices.Contains(d.Tags, tag)
+}
+
+// FilterTag 只保留带有标签 tag 的行, 同一行的域名共用参数, 返回是否还有域名
+func (dnsConf *DnsConfig) FilterTag(tag string) bool {
+	filter := func(lines []string) (kept []string) {
+		for _, line := range lines {
+			_, rawQuery, _ := strings.Cut(line, "?")
+			// 与 parseDomainParams 相同, 忽略无法解析的参数
+			query, _ := url.ParseQuery(rawQuery)
+			if slices.Contains(parseTags(query["tag"]), tag) {
+				kept = append(kept, line)
+			}
+		}
+		return
+	}
+	dnsConf.Ipv4.Domains = filter(dnsConf.Ipv4.Domains)
+	dnsConf.Ipv6.Domains = filter(dnsConf.Ipv6.Domains)
+	return len(dnsConf.Ipv4.Domains) > 0 || len(dnsConf.Ipv6.Domains) > 0
+}
+
+// DomainTags 全部标签及带有该标签的域名
+func (conf *Config) DomainTags() map[string][]string {
+	tags := map[string][]string{}
+	for _, dc := range conf.DnsConf {
+		ipv4Domains, ipv6Domains := dc.ParseDomains()
+		for _, domain := range append(ipv4Domains, ipv6Domains...) {
+			for _, tag := range domain.Tags {
+				if !slices.Contains(tags[tag], domain.String()) {
+					tags[tag] = append(tags[tag], domain.String())
+				}
+			}
+		}
+	}
+	return tags
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestParseTagDomains 测试参数 tag 不传递给DNS服务商
+func TestParseTagDomains(t *testing.T) {
+	parsed := checkParseDomains([]string{"example.com,www?tag=home,lab&tag=home&line=cn"})
+	if len(parsed) != 2 {
+		t.Fatalf("解析失败")
+	}
+	for _, d := range parsed {
+		if !slices.Equal(d.Tags, []string{"home", "lab"}) || d.CustomParams != "line=cn" {
+			t.Errorf("期待 %s 的标签为 home lab 且参数为 line=cn, 得到 %v %s", d, d.Tags, d.CustomParams)
+		}
+	}
+	if !parsed[0].HasTag("lab") || parsed[0].HasTag("work") {
+		t.Errorf("期待 %s 带有标签 lab, 不带有 work", parsed[0])
+	}
+}
+
+// TestFilterTag 测试只保留带有标签的行
+func TestFilterTag(t *testing.T) {
+	dc := DnsConfig{}
+	dc.Ipv4.Domains = []string{"a.example.com?tag=home", "b.example.com?tag=lab", "c.example.com"}
+	dc.Ipv6.Domains = []string{"d.example.com?tag=lab,home"}
+	if !dc.FilterTag("home") {
+		t.Fatalf("期待还有带有标签 home 的域名")
+	}
+	if !slices.Equal(dc.Ipv4.Domains, []string{"a.example.com?tag=home"}) || !slices.Equal(dc.Ipv6.Domains, []string{"d.example.com?tag=lab,home"}) {
+		t.Errorf("期待只保留带有标签 home 的行, 得到 %v %v", dc.Ipv4.Domains, dc.Ipv6.Domains)
+	}
+	if dc.FilterTag("work") {
+		t.Errorf("期待没有带有标签 work 的域名")
+	}
+}
+
+// TestDomainTags 测试按标签汇总域名
+func TestDomainTags(t *testing.T) {
+	dc := DnsConfig{}
+	dc.Ipv4.Enable = true
+	dc.Ipv4.Domains = []string{"a.example.com?tag=home", "b.example.com"}
+	dc.Ipv6.Enable = true
+	dc.Ipv6.Domains = []string{"a.example.com?tag=home,lab"}
+	conf := Config{DnsConf: []DnsConfig{dc}}
+	tags := conf.DomainTags()
+	if len(tags) != 2 || !slices.Equal(tags["home"], []string{"a.example.com"}) || !slices.Equal(tags["lab"], []string{"a.example.com"}) {
+		t.Errorf("期待 home 及 lab 均为 a.example.com, 得到 %v", tags)
+	}
+}
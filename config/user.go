@@ -1,7 +1,38 @@
 package config
 
+import "time"
+
+// 登录有效期
+const (
+	// DefaultSessionTimeout 默认登录有效期(分钟), 1天
+	DefaultSessionTimeout = 24 * 60
+	// RememberMeTimeout 记住我时的登录有效期
+	RememberMeTimeout = 30 * 24 * time.Hour
+)
+
 // User 登录用户
 type User struct {
 	Username string
 	Password string
+	// 登录有效期(分钟), 为0时使用默认值
+	SessionTimeout int
+	// 只读用户, 只能查看配置、日志及状态
+	ReadOnlyUsername string
+	ReadOnlyPassword string
+	// 访问任何页面前先要求 HTTP Basic 认证, 与登录相互独立, 默认关闭
+	BasicAuth         bool
+	BasicAuthUsername string
+	BasicAuthPassword string
+}
+
+// GetSessionTimeout 获得登录有效期, 记住我时不短于30天
+func (user *User) GetSessionTimeout(remember bool) time.Duration {
+	timeout := time.Duration(DefaultSessionTimeout) * time.Minute
+	if user.SessionTimeout > 0 {
+		timeout = time.Duration(user.SessionTimeout) * time.Minute
+	}
+	if remember && timeout < RememberMeTimeout {
+		timeout = RememberMeTimeout
+	}
+	return timeout
 }
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGetSessionTimeout 测试登录有效期
+func TestGetSessionTimeout(t *testing.T) {
+	tests := []struct {
+		sessionTimeout int
+		remember       bool
+		want           time.Duration
+	}{
+		{0, false, 24 * time.Hour},
+		{30, false, 30 * time.Minute},
+		{0, true, RememberMeTimeout},
+		{30, true, RememberMeTimeout},
+		{60 * 24 * 60, true, 60 * 24 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		user := &User{SessionTimeout: tt.sessionTimeout}
+		if got := user.GetSessionTimeout(tt.remember); got != tt.want {
+			t.Errorf("SessionTimeout %d remember %v 期待 %s, 得到 %s", tt.sessionTimeout, tt.remember, tt.want, got)
+		}
+	}
+}
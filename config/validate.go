@@ -0,0 +1,164 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// FieldError 配置项的校验错误
+type FieldError struct {
+	// Index DNS配置的序号, 从0开始, 为-1时为全局配置
+	Index int `json:"index"`
+	// Field 配置项的名称, 与保存接口的字段名相同, 如 DnsSecret、TTL
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors 配置的校验错误, 页面保存、导入及 -check 共用
+type ValidationErrors []FieldError
+
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		messages = append(messages, e.Message)
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Add 添加全局配置的错误, index 为-1; 否则为第 index 个DNS配置的错误
+func (errs *ValidationErrors) Add(index int, field string, message string) {
+	*errs = append(*errs, FieldError{Index: index, Field: field, Message: message})
+}
+
+// providersWithoutID 不需要填写ID的服务商, 与 static/constant.js 中 idLabel 为空的一致
+var providersWithoutID = map[string]bool{
+	"cloudflare": true, "namecheap": true, "namesilo": true, "vercel": true,
+	"dynadot": true, "dynv6": true, "dnsexit": true, "njalla": true,
+}
+
+// providersWithoutSecret 不需要填写Secret的服务商, Callback 的 Secret 为请求体
+var providersWithoutSecret = map[string]bool{"callback": true}
+
+// Validate 校验配置, 返回所有配置项的错误, 没有错误时返回 nil
+func (conf *Config) Validate() ValidationErrors {
+	var errs ValidationErrors
+	if conf.ReadOnlyUsername != "" {
+		if conf.ReadOnlyUsername == conf.Username {
+			errs.Add(-1, "ReadOnlyUsername", util.LogStr("只读用户的用户名不能与管理员相同"))
+		}
+		if conf.ReadOnlyPassword == "" {
+			errs.Add(-1, "ReadOnlyPassword", util.LogStr("必须输入只读用户的密码"))
+		}
+	}
+	if conf.BasicAuth {
+		if conf.BasicAuthUsername == "" {
+			errs.Add(-1, "BasicAuthUsername", util.LogStr("开启Basic认证时必须输入用户名/密码"))
+		}
+		if conf.BasicAuthPassword == "" {
+			errs.Add(-1, "BasicAuthPassword", util.LogStr("开启Basic认证时必须输入用户名/密码"))
+		}
+	}
+	if err := CheckMaintenanceWindow(conf.MaintenanceWindow, conf.MaintenanceTimezone); err != nil {
+		errs.Add(-1, "MaintenanceWindow", err.Error())
+	}
+	if err := CheckWebhookSuccessCodes(conf.WebhookSuccessCodes); err != nil {
+		errs.Add(-1, "WebhookSuccessCodes", err.Error())
+	}
+	if err := CheckFavicon(strings.TrimSpace(conf.Favicon)); err != nil {
+		errs.Add(-1, "Favicon", err.Error())
+	}
+
+	for i, dc := range conf.DnsConf {
+		errs = append(errs, dc.validate(i, conf.Lang)...)
+	}
+	switch conf.DuplicateDomains {
+	case "", DuplicateDomainsWarn:
+	case DuplicateDomainsRefuse:
+		for _, d := range conf.duplicateDomains() {
+			errs.Add(d.index, d.field, d.message(conf.Lang))
+		}
+	default:
+		errs.Add(-1, "DuplicateDomains", util.LogStr("重复域名的处理方式 %s 不正确, 只支持 warn、refuse", conf.DuplicateDomains))
+	}
+	return errs
+}
+
+// validate 校验第 i 个DNS配置
+func (dc *DnsConfig) validate(i int, lang string) ValidationErrors {
+	var errs ValidationErrors
+	ordinal := util.Ordinal(i+1, lang)
+
+	if dc.DNS.Name == "" {
+		errs.Add(i, "DnsName", util.LogStr("第 %s 个配置未选择DNS服务商", ordinal))
+	} else {
+		if !providersWithoutID[dc.DNS.Name] && dc.DNS.ID == "" {
+			errs.Add(i, "DnsID", util.LogStr("第 %s 个配置未填写 %s 的ID", ordinal, dc.DNS.Name))
+		}
+		if !providersWithoutSecret[dc.DNS.Name] && dc.DNS.Secret == "" {
+			errs.Add(i, "DnsSecret", util.LogStr("第 %s 个配置未填写 %s 的Secret", ordinal, dc.DNS.Name))
+		}
+	}
+	if dc.DNS.Proxy != "" {
+		if _, err := util.ParseProxyURL(dc.DNS.Proxy); err != nil {
+			errs.Add(i, "DnsProxy", err.Error())
+		}
+	}
+	if ttl, err := strconv.Atoi(dc.TTL); dc.TTL != "" && (err != nil || ttl <= 0) {
+		errs.Add(i, "TTL", util.LogStr("第 %s 个配置的TTL %s 不正确", ordinal, dc.TTL))
+	}
+	if dc.CacheTimes < 0 {
+		errs.Add(i, "CacheTimes", util.LogStr("第 %s 个配置的缓存次数不正确", ordinal))
+	}
+	if dc.StableTimes < 0 {
+		errs.Add(i, "StableTimes", util.LogStr("第 %s 个配置的稳定次数不正确", ordinal))
+	}
+	if dc.Interval < 0 {
+		errs.Add(i, "Interval", util.LogStr("第 %s 个配置的更新间隔不正确", ordinal))
+	}
+	if dc.Ipv6.Enable && dc.Ipv6.GetType == "prefixFile" {
+		if dc.Ipv6.PrefixFile == "" {
+			errs.Add(i, "Ipv6PrefixFile", util.LogStr("第 %s 个配置未填写前缀文件", ordinal))
+		}
+		if _, err := parsePrefixSuffix(dc.Ipv6.PrefixSuffix); err != nil {
+			errs.Add(i, "Ipv6PrefixSuffix", err.Error())
+		}
+	}
+	rules := dc.nameRules()
+	for _, name := range rules.invalidDomains(dc.Ipv4.Domains) {
+		errs.Add(i, "Ipv4Domains", util.LogStr("第 %s 个配置的域名 %s 不正确", ordinal, name))
+	}
+	for _, name := range rules.invalidDomains(dc.Ipv6.Domains) {
+		errs.Add(i, "Ipv6Domains", util.LogStr("第 %s 个配置的域名 %s 不正确", ordinal, name))
+	}
+	return errs
+}
+
+// invalidDomains 返回不正确的域名, 规则与 parseDomains 相同
+func (rules nameRules) invalidDomains(domainArr []string) (invalid []string) {
+	for _, domainStr := range domainArr {
+		domainStr = strings.TrimSpace(domainStr)
+		if domainStr == "" {
+			continue
+		}
+		qp := strings.Split(domainStr, "?")
+		first := true
+		for _, name := range strings.Split(qp[0], ",") {
+			name = rules.normalize(name)
+			if name == "" || (!first && isRelativeDomain(name)) {
+				if name != "" && name != "@" && rules.check(rules.toASCII(name)) != nil {
+					invalid = append(invalid, name)
+				}
+				continue
+			}
+			domain, err := rules.splitDomain(name)
+			if err != nil || rules.check(domain.String()) != nil {
+				invalid = append(invalid, name)
+				continue
+			}
+			first = false
+		}
+	}
+	return
+}
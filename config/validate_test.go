@@ -0,0 +1,50 @@
+package config
+
+import "testing"
+
+// TestValidate 测试返回各配置项的错误
+func TestValidate(t *testing.T) {
+	conf := &Config{
+		MaintenanceWindow: "02:00",
+		User:              User{BasicAuth: true, BasicAuthUsername: "gate"},
+		DnsConf: []DnsConfig{
+			{DNS: DNS{Name: "cloudflare", Secret: "token"}, TTL: "600"},
+			{DNS: DNS{Name: "alidns", ID: "key"}, TTL: "abc"},
+			{DNS: DNS{Name: "callback", ID: "https://example.com"}},
+		},
+	}
+	conf.DnsConf[0].Ipv4.Domains = []string{"example.com,www", "www:example.com?comment=a"}
+	conf.DnsConf[1].Ipv4.Domains = []string{"example.com", "localhost"}
+
+	expected := []struct {
+		index int
+		field string
+	}{
+		{-1, "BasicAuthPassword"},
+		{-1, "MaintenanceWindow"},
+		{1, "DnsSecret"},
+		{1, "TTL"},
+		{1, "Ipv4Domains"},
+	}
+	errs := conf.Validate()
+	if len(errs) != len(expected) {
+		t.Fatalf("期待 %d 个错误, 得到 %v", len(expected), errs)
+	}
+	for i, e := range expected {
+		if errs[i].Index != e.index || errs[i].Field != e.field {
+			t.Errorf("第 %d 个错误期待 %d %s, 得到 %d %s", i, e.index, e.field, errs[i].Index, errs[i].Field)
+		}
+	}
+
+	if errs := (&Config{}).Validate(); errs != nil {
+		t.Errorf("空配置期待没有错误, 得到 %v", errs)
+	}
+}
+
+// TestInvalidDomains 测试校验域名, 相对于根域名的名称需在正确的域名之后
+func TestInvalidDomains(t *testing.T) {
+	invalid := nameRules{}.invalidDomains([]string{"example.com,www,@", "www", "a:b", "a:b:c.com", "sub:example.com?ptr=true", ""})
+	if len(invalid) != 3 || invalid[0] != "www" || invalid[1] != "a:b" || invalid[2] != "a:b:c.com" {
+		t.Errorf("期待 www a:b a:b:c.com, 得到 %v", invalid)
+	}
+}
@@ -1,11 +1,20 @@
 package config
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/jeessy2/ddns-go/v6/util"
 )
@@ -15,6 +24,14 @@ type Webhook struct {
 	WebhookURL         string
 	WebhookRequestBody string
 	WebhookHeaders     string
+	// 签名密钥, 不为空时使用 HMAC-SHA256 签名
+	WebhookSecret string
+	// 启动时触发Webhook
+	WebhookNotifyStartup bool
+	// 停止时触发Webhook
+	WebhookNotifyShutdown bool
+	// 成功的返回状态码, 如 200,202 或 200-299, 为空时 2xx 为成功
+	WebhookSuccessCodes string
 }
 
 // updateStatusType 更新状态
@@ -27,10 +44,16 @@ const (
 	UpdatedFailed = "失败"
 	// UpdatedSuccess 更新成功
 	UpdatedSuccess = "成功"
+	// Started 已启动
+	Started = "已启动"
+	// Stopped 已停止
+	Stopped = "已停止"
+	// NoIPDetected 未获取到IP
+	NoIPDetected = "未获取到IP"
 )
 
-// 更新失败次数
-var updatedFailedTimes = 0
+// 更新失败次数, 多个配置可同时更新
+var updatedFailedTimes atomic.Int64
 
 // hasJSONPrefix returns true if the string starts with a JSON open brace.
 func hasJSONPrefix(s string) bool {
@@ -42,60 +65,218 @@ func ExecWebhook(domains *Domains, conf *Config) (v4Status updateStatusType, v6S
 	v4Status = getDomainsStatus(domains.Ipv4Domains)
 	v6Status = getDomainsStatus(domains.Ipv6Domains)
 
-	if conf.WebhookURL != "" && (v4Status != UpdatedNothing || v6Status != UpdatedNothing) {
-		// 第3次失败才触发一次webhook
+	if (hasWebhookOrNotifier(conf) || conf.PostUpdateCmd != "") && (v4Status != UpdatedNothing || v6Status != UpdatedNothing) {
+		// 第3次失败才触发一次webhook, 另一种记录更新成功时仍触发, 失败不会掩盖成功的结果
 		if v4Status == UpdatedFailed || v6Status == UpdatedFailed {
-			updatedFailedTimes++
-			if updatedFailedTimes != 3 {
-				util.Log("将不会触发Webhook, 仅在第 3 次失败时触发一次Webhook, 当前失败次数：%d", updatedFailedTimes)
+			times := updatedFailedTimes.Add(1)
+			if times != 3 && v4Status != UpdatedSuccess && v6Status != UpdatedSuccess {
+				util.Log("将不会触发Webhook, 仅在第 3 次失败时触发一次Webhook, 当前失败次数：%d", times)
 				return
 			}
 		} else {
-			updatedFailedTimes = 0
+			updatedFailedTimes.Store(0)
 		}
 
 		// 成功和失败都要触发webhook
-		method := "GET"
-		postPara := ""
-		contentType := "application/x-www-form-urlencoded"
-		if conf.WebhookRequestBody != "" {
-			method = "POST"
-			postPara = replacePara(domains, conf.WebhookRequestBody, v4Status, v6Status)
-			if json.Valid([]byte(postPara)) {
-				contentType = "application/json"
-			} else if hasJSONPrefix(postPara) {
-				// 如果 RequestBody 的 JSON 无效但前缀为 JSON，提示无效
-				util.Log("Webhook中的 RequestBody JSON 无效")
-			}
+		sendWebhookAndNotify(domains, conf, v4Status, v6Status)
+		execPostUpdateCmd(domains, conf, v4Status, v6Status)
+	}
+	return
+}
+
+// ExecLifecycleWebhook 启动/停止时触发Webhook
+func ExecLifecycleWebhook(conf *Config, status updateStatusType, ipv4Addr string, ipv6Addr string) {
+	if !hasWebhookOrNotifier(conf) {
+		return
+	}
+	if (status == Started && !conf.WebhookNotifyStartup) ||
+		(status == Stopped && !conf.WebhookNotifyShutdown) {
+		return
+	}
+
+	domains := &Domains{Ipv4Addr: ipv4Addr, Ipv6Addr: ipv6Addr}
+	for _, dc := range conf.DnsConf {
+		ipv4Domains, ipv6Domains := dc.ParseDomains()
+		domains.Ipv4Domains = append(domains.Ipv4Domains, ipv4Domains...)
+		domains.Ipv6Domains = append(domains.Ipv6Domains, ipv6Domains...)
+	}
+	sendWebhookAndNotify(domains, conf, status, status)
+}
+
+// ExecNoIPWebhook 未获取到IP时立即触发Webhook
+func ExecNoIPWebhook(domains *Domains, conf *Config, recordType string) {
+	if !hasWebhookOrNotifier(conf) {
+		return
+	}
+	v4Status, v6Status := UpdatedNothing, UpdatedNothing
+	if recordType == "A" {
+		v4Status = NoIPDetected
+	} else {
+		v6Status = NoIPDetected
+	}
+	sendWebhookAndNotify(domains, conf, v4Status, v6Status)
+}
+
+// hasWebhookOrNotifier 是否配置了Webhook或通知服务
+func hasWebhookOrNotifier(conf *Config) bool {
+	return conf.WebhookURL != "" || hasNotifier(conf)
+}
+
+// sendWebhookAndNotify 发送Webhook请求及通知
+func sendWebhookAndNotify(domains *Domains, conf *Config, v4Status updateStatusType, v6Status updateStatusType) {
+	if conf.WebhookURL != "" {
+		sendWebhook(domains, conf, v4Status, v6Status)
+	}
+	sendNotify(domains, conf, v4Status, v6Status)
+}
+
+// sendWebhook 发送Webhook请求
+func sendWebhook(domains *Domains, conf *Config, v4Status updateStatusType, v6Status updateStatusType) {
+	method := "GET"
+	postPara := ""
+	contentType := "application/x-www-form-urlencoded"
+	if conf.WebhookRequestBody != "" {
+		method = "POST"
+		postPara = replacePara(domains, conf.WebhookRequestBody, v4Status, v6Status, conf.Lang)
+		if json.Valid([]byte(postPara)) {
+			contentType = "application/json"
+		} else if hasJSONPrefix(postPara) {
+			// 如果 RequestBody 的 JSON 无效但前缀为 JSON，提示无效
+			util.Log("Webhook中的 RequestBody JSON 无效")
+		}
+	}
+	requestURL := replacePara(domains, conf.WebhookURL, v4Status, v6Status, conf.Lang)
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		util.Log("Webhook配置中的URL不正确")
+		return
+	}
+	req, err := http.NewRequest(method, fmt.Sprintf("%s://%s%s?%s", u.Scheme, u.Host, u.EscapedPath(), u.Query().Encode()), strings.NewReader(postPara))
+	if err != nil {
+		util.Log("Webhook调用失败! 异常信息：%s", err)
+		return
+	}
+
+	headers := ExtractHeaders(conf.WebhookHeaders)
+	for key, value := range headers {
+		req.Header.Add(key, value)
+	}
+	req.Header.Add("content-type", contentType)
+	if conf.WebhookSecret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Timestamp", timestamp)
+		req.Header.Set("X-Signature", "sha256="+signWebhook(conf.WebhookSecret, timestamp, postPara))
+	}
+
+	clt := util.CreateHTTPClient()
+	resp, err := clt.Do(req)
+	if err != nil {
+		util.Log("Webhook调用失败! 异常信息：%s", err)
+		return
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024000))
+	if err != nil {
+		util.Log("Webhook调用失败! 异常信息：%s", err)
+		return
+	}
+	if isWebhookSuccessCode(conf.WebhookSuccessCodes, resp.StatusCode) {
+		util.Log("Webhook调用成功! 返回数据：%s", string(body))
+	} else {
+		util.Log("Webhook调用失败! 返回状态码: %d, 返回数据：%s", resp.StatusCode, string(body))
+	}
+}
+
+// parseWebhookSuccessCodes 解析成功的返回状态码, 如 200,202 或 200-299
+func parseWebhookSuccessCodes(codes string) (ranges [][2]int, err error) {
+	for _, item := range strings.Split(codes, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
 		}
-		requestURL := replacePara(domains, conf.WebhookURL, v4Status, v6Status)
-		u, err := url.Parse(requestURL)
-		if err != nil {
-			util.Log("Webhook配置中的URL不正确")
-			return
+		start, end, found := strings.Cut(item, "-")
+		from, err1 := strconv.Atoi(strings.TrimSpace(start))
+		to := from
+		var err2 error
+		if found {
+			to, err2 = strconv.Atoi(strings.TrimSpace(end))
 		}
-		req, err := http.NewRequest(method, fmt.Sprintf("%s://%s%s?%s", u.Scheme, u.Host, u.EscapedPath(), u.Query().Encode()), strings.NewReader(postPara))
-		if err != nil {
-			util.Log("Webhook调用失败! 异常信息：%s", err)
-			return
+		if err1 != nil || err2 != nil || from < 100 || to > 599 || from > to {
+			return nil, errors.New(util.LogStr("Webhook成功状态码不正确: %s", item))
 		}
+		ranges = append(ranges, [2]int{from, to})
+	}
+	return
+}
 
-		headers := extractHeaders(conf.WebhookHeaders)
-		for key, value := range headers {
-			req.Header.Add(key, value)
+// CheckWebhookSuccessCodes 校验成功的返回状态码
+func CheckWebhookSuccessCodes(codes string) error {
+	_, err := parseWebhookSuccessCodes(codes)
+	return err
+}
+
+// isWebhookSuccessCode 返回状态码是否为成功, 未配置或配置不正确时 2xx 为成功
+func isWebhookSuccessCode(codes string, statusCode int) bool {
+	ranges, err := parseWebhookSuccessCodes(codes)
+	if err != nil || len(ranges) == 0 {
+		return statusCode >= 200 && statusCode < 300
+	}
+	for _, r := range ranges {
+		if statusCode >= r[0] && statusCode <= r[1] {
+			return true
 		}
-		req.Header.Add("content-type", contentType)
+	}
+	return false
+}
 
-		clt := util.CreateHTTPClient()
-		resp, err := clt.Do(req)
-		body, err := util.GetHTTPResponseOrg(resp, err)
-		if err == nil {
-			util.Log("Webhook调用成功! 返回数据：%s", string(body))
-		} else {
-			util.Log("Webhook调用失败! 异常信息：%s", err)
+// ExecDomainWebhook 域名更新成功后调用该域名的Webhook
+func ExecDomainWebhook(domains *Domains) {
+	for _, domain := range domains.Ipv4Domains {
+		if domain.Webhook != "" && domain.UpdateStatus == UpdatedSuccess {
+			sendDomainWebhook(domain, domains.Ipv4Addr, "A")
 		}
 	}
-	return
+	for _, domain := range domains.Ipv6Domains {
+		if domain.Webhook != "" && domain.UpdateStatus == UpdatedSuccess {
+			sendDomainWebhook(domain, domains.Ipv6Addr, "AAAA")
+		}
+	}
+}
+
+// sendDomainWebhook 发送域名的Webhook请求, 支持变量 #{domain} #{ip} #{recordType} #{version}
+func sendDomainWebhook(domain *Domain, ipAddr string, recordType string) {
+	requestURL := strings.NewReplacer(
+		"#{domain}", domain.ToUnicode(),
+		"#{ip}", ipAddr,
+		"#{recordType}", recordType,
+		"#{version}", os.Getenv(util.VersionENV),
+	).Replace(domain.Webhook)
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		util.Log("域名 %s 的Webhook URL不正确", domain)
+		return
+	}
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s://%s%s?%s", u.Scheme, u.Host, u.EscapedPath(), u.Query().Encode()), http.NoBody)
+	if err != nil {
+		util.Log("域名 %s 的Webhook调用失败! 异常信息：%s", domain, err)
+		return
+	}
+
+	clt := util.CreateHTTPClient()
+	resp, err := clt.Do(req)
+	body, err := util.GetHTTPResponseOrg(resp, err)
+	if err == nil {
+		util.Log("域名 %s 的Webhook调用成功! 返回数据：%s", domain, string(body))
+	} else {
+		util.Log("域名 %s 的Webhook调用失败! 异常信息：%s", domain, err)
+	}
+}
+
+// signWebhook 计算Webhook签名, 签名字符串为 时间戳 + "." + 请求体
+func signWebhook(secret string, timestamp string, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + body))
+	return hex.EncodeToString(mac.Sum(nil))
 }
 
 // getDomainsStatus 获取域名状态
@@ -118,15 +299,16 @@ func getDomainsStatus(domains []*Domain) updateStatusType {
 	return UpdatedNothing
 }
 
-// replacePara 替换参数
-func replacePara(domains *Domains, orgPara string, ipv4Result updateStatusType, ipv6Result updateStatusType) string {
+// replacePara 替换参数, 结果按配置的语言 lang 翻译
+func replacePara(domains *Domains, orgPara string, ipv4Result updateStatusType, ipv6Result updateStatusType, lang string) string {
 	return strings.NewReplacer(
 		"#{ipv4Addr}", domains.Ipv4Addr,
-		"#{ipv4Result}", util.LogStr(string(ipv4Result)), // i18n
+		"#{ipv4Result}", util.LangStr(lang, string(ipv4Result)), // i18n
 		"#{ipv4Domains}", getDomainsStr(domains.Ipv4Domains),
 		"#{ipv6Addr}", domains.Ipv6Addr,
-		"#{ipv6Result}", util.LogStr(string(ipv6Result)), // i18n
+		"#{ipv6Result}", util.LangStr(lang, string(ipv6Result)), // i18n
 		"#{ipv6Domains}", getDomainsStr(domains.Ipv6Domains),
+		"#{version}", os.Getenv(util.VersionENV),
 	).Replace(orgPara)
 }
 
@@ -134,7 +316,7 @@ func replacePara(domains *Domains, orgPara string, ipv4Result updateStatusType,
 func getDomainsStr(domains []*Domain) string {
 	str := ""
 	for i, v46 := range domains {
-		str += v46.String()
+		str += v46.ToUnicode()
 		if i != len(domains)-1 {
 			str += ","
 		}
@@ -143,10 +325,10 @@ func getDomainsStr(domains []*Domain) string {
 	return str
 }
 
-// extractHeaders converts s into a map of headers.
+// ExtractHeaders converts s into a map of headers.
 //
 // See also: https://github.com/appleboy/gorush/blob/v1.17.0/notify/feedback.go#L15
-func extractHeaders(s string) map[string]string {
+func ExtractHeaders(s string) map[string]string {
 	lines := util.SplitLines(s)
 	headers := make(map[string]string, len(lines))
 
@@ -158,7 +340,7 @@ func extractHeaders(s string) map[string]string {
 
 		parts := strings.Split(line, ":")
 		if len(parts) != 2 {
-			util.Log("Webhook Header不正确: %s", line)
+			util.Log("Header不正确: %s", line)
 			continue
 		}
 
@@ -1,8 +1,14 @@
 package config
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
 	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/util"
 )
 
 // TestExtractHeaders 测试 parseHeaderArr
@@ -15,8 +21,109 @@ b: bar`
 		"b": "bar",
 	}
 
-	parsedHeaders := extractHeaders(input)
+	parsedHeaders := ExtractHeaders(input)
 	if !reflect.DeepEqual(parsedHeaders, expected) {
 		t.Errorf("Expected %v, got %v", expected, parsedHeaders)
 	}
 }
+
+// TestSignWebhook 测试Webhook签名
+func TestSignWebhook(t *testing.T) {
+	// echo -n '1700000000.{"ip":"1.1.1.1"}' | openssl dgst -sha256 -hmac secret
+	expected := "4473d277454177db1e355de318d0042e09cd0cfba1bcc8c73c2d834389a4fbeb"
+	got := signWebhook("secret", "1700000000", `{"ip":"1.1.1.1"}`)
+	if got != expected {
+		t.Errorf("期待 %s, 得到 %s", expected, got)
+	}
+}
+
+// TestExecDomainWebhook 测试只调用更新成功的域名的Webhook
+func TestExecDomainWebhook(t *testing.T) {
+	var called []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = append(called, r.URL.Query().Get("domain")+" "+r.URL.Query().Get("ip")+" "+r.URL.Query().Get("type"))
+	}))
+	defer server.Close()
+
+	webhook := url.QueryEscape(server.URL + "/reload?domain=#{domain}&ip=#{ip}&type=#{recordType}")
+	domains := checkParseDomains([]string{"www.example.com?webhook=" + webhook, "api.example.com?webhook=" + webhook, "blog.example.com"})
+	if domains[0].Webhook == "" || domains[0].CustomParams != "" {
+		t.Fatalf("参数 webhook 解析不正确: %+v", domains[0])
+	}
+	domains[0].UpdateStatus = UpdatedSuccess
+	domains[1].UpdateStatus = UpdatedNothing
+	domains[2].UpdateStatus = UpdatedSuccess
+
+	ExecDomainWebhook(&Domains{Ipv6Addr: "2001:db8::1", Ipv6Domains: domains})
+
+	expected := []string{"www.example.com 2001:db8::1 AAAA"}
+	if !reflect.DeepEqual(called, expected) {
+		t.Errorf("期待 %v, 得到 %v", expected, called)
+	}
+}
+
+// TestIsWebhookSuccessCode 测试Webhook成功的返回状态码
+func TestIsWebhookSuccessCode(t *testing.T) {
+	data := []struct {
+		codes      string
+		statusCode int
+		success    bool
+	}{
+		{"", 200, true},
+		{"", 202, true},
+		{"", 204, true},
+		{"", 301, false},
+		{"200", 202, false},
+		{"200,202", 202, true},
+		{"200,202", 204, false},
+		{"200-204", 204, true},
+		{"200-299, 302", 302, true},
+		{"200-299, 302", 404, false},
+		// 配置不正确时 2xx 为成功
+		{"abc", 204, true},
+	}
+
+	for _, d := range data {
+		if got := isWebhookSuccessCode(d.codes, d.statusCode); got != d.success {
+			t.Errorf("%q %d 期待 %v, 得到 %v", d.codes, d.statusCode, d.success, got)
+		}
+	}
+
+	for _, codes := range []string{"abc", "299-200", "200-", "99"} {
+		if CheckWebhookSuccessCodes(codes) == nil {
+			t.Errorf("%q 期待返回异常", codes)
+		}
+	}
+}
+
+// TestExecWebhookPartialFailure 测试 IPv6 失败时 IPv4 成功的结果仍会触发Webhook
+func TestExecWebhookPartialFailure(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	updatedFailedTimes.Store(0)
+	defer updatedFailedTimes.Store(0)
+	conf := &Config{Webhook: Webhook{WebhookURL: server.URL, WebhookRequestBody: "#{ipv4Result} #{ipv6Result}"}}
+	newDomains := func(v4 updateStatusType, v6 updateStatusType) *Domains {
+		return &Domains{
+			Ipv4Domains: []*Domain{{DomainName: "example.com", UpdateStatus: v4}},
+			Ipv6Domains: []*Domain{{DomainName: "example.com", UpdateStatus: v6}},
+		}
+	}
+
+	v4Status, v6Status := ExecWebhook(newDomains(UpdatedSuccess, UpdatedFailed), conf)
+	if v4Status != UpdatedSuccess || v6Status != UpdatedFailed {
+		t.Errorf("期待分别返回 IPv4/IPv6 的结果, 得到 %s %s", v4Status, v6Status)
+	}
+	// 仅失败时第3次才触发
+	ExecWebhook(newDomains(UpdatedNothing, UpdatedFailed), conf)
+
+	expected := []string{util.LogStr(UpdatedSuccess) + " " + util.LogStr(UpdatedFailed)}
+	if !reflect.DeepEqual(bodies, expected) {
+		t.Errorf("期待 %v, 得到 %v", expected, bodies)
+	}
+}
@@ -26,6 +26,7 @@ type AlidnsRecord struct {
 	DomainName string
 	RecordID   string
 	Value      string
+	Weight     int
 }
 
 // AlidnsSubDomainRecords 记录
@@ -108,6 +109,9 @@ func (ali *Alidns) addUpdateDomainRecords(recordType string) {
 
 // 创建
 func (ali *Alidns) create(domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	params := domain.GetCustomParams()
 	params.Set("Action", "AddDomainRecord")
 	params.Set("DomainName", domain.DomainName)
@@ -128,6 +132,7 @@ func (ali *Alidns) create(domain *config.Domain, recordType string, ipAddr strin
 	if result.RecordID != "" {
 		util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
+		ali.setWeight(result.RecordID, domain, recordType)
 	} else {
 		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
 		domain.UpdateStatus = config.UpdatedFailed
@@ -137,9 +142,12 @@ func (ali *Alidns) create(domain *config.Domain, recordType string, ipAddr strin
 // 修改
 func (ali *Alidns) modify(recordSelected AlidnsRecord, domain *config.Domain, recordType string, ipAddr string) {
 
-	// 相同不修改
+	// 相同不修改, 只更新不同的权重
 	if recordSelected.Value == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		if weight, ok := domain.GetWeight(); ok && weight != recordSelected.Weight {
+			ali.setWeight(recordSelected.RecordID, domain, recordType)
+		}
+		setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 		return
 	}
 
@@ -163,12 +171,44 @@ func (ali *Alidns) modify(recordSelected AlidnsRecord, domain *config.Domain, re
 	if result.RecordID != "" {
 		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
 		domain.UpdateStatus = config.UpdatedSuccess
+		if weight, ok := domain.GetWeight(); ok && weight != recordSelected.Weight {
+			ali.setWeight(recordSelected.RecordID, domain, recordType)
+		}
 	} else {
 		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, "返回RecordId为空")
 		domain.UpdateStatus = config.UpdatedFailed
 	}
 }
 
+// setWeight 开启权重配置并设置记录的权重, 未设置权重时跳过
+// https://help.aliyun.com/document_detail/2355677.html
+func (ali *Alidns) setWeight(recordID string, domain *config.Domain, recordType string) {
+	if domain.Weight == "" {
+		return
+	}
+	params := url.Values{}
+	params.Set("Action", "SetDNSSLBStatus")
+	params.Set("DomainName", domain.DomainName)
+	params.Set("SubDomain", domain.GetFullDomain())
+	params.Set("Type", recordType)
+	params.Set("Open", "true")
+	var status struct{ RequestID string }
+	if err := ali.request(params, &status); err != nil {
+		util.Log("更新域名 %s 的权重失败! 异常信息: %s", domain, err)
+		return
+	}
+
+	params = url.Values{}
+	params.Set("Action", "UpdateDNSSLBWeight")
+	params.Set("RecordId", recordID)
+	params.Set("Weight", domain.Weight)
+	if err := ali.request(params, &status); err != nil {
+		util.Log("更新域名 %s 的权重失败! 异常信息: %s", domain, err)
+		return
+	}
+	util.Log("更新域名 %s 的权重为 %s 成功", domain, domain.Weight)
+}
+
 // request 统一请求接口
 func (ali *Alidns) request(params url.Values, result interface{}) (err error) {
 
@@ -185,7 +225,7 @@ func (ali *Alidns) request(params url.Values, result interface{}) (err error) {
 		return
 	}
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(ali.DNS)
 	resp, err := client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
@@ -132,6 +132,9 @@ func (baidu *BaiduCloud) addUpdateDomainRecords(recordType string) {
 
 // create 创建新的解析
 func (baidu *BaiduCloud) create(domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	var baiduCreateRequest = BaiduCreateRequest{
 		Domain:   domain.GetSubDomain(), //处理一下@
 		RdType:   recordType,
@@ -155,7 +158,7 @@ func (baidu *BaiduCloud) create(domain *config.Domain, recordType string, ipAddr
 func (baidu *BaiduCloud) modify(record BaiduRecord, domain *config.Domain, rdType string, ipAddr string) {
 	//没有变化直接跳过
 	if record.Rdata == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 		return
 	}
 	var baiduModifyRequest = BaiduModifyRequest{
@@ -198,7 +201,7 @@ func (baidu *BaiduCloud) request(method string, url string, data interface{}, re
 
 	util.BaiduSigner(baidu.DNS.ID, baidu.DNS.Secret, req)
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(baidu.DNS)
 	resp, err := client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
@@ -1,11 +1,13 @@
 package dns
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"text/template"
 
 	"github.com/jeessy2/ddns-go/v6/config"
 	"github.com/jeessy2/ddns-go/v6/util"
@@ -69,12 +71,30 @@ func (cb *Callback) addUpdateDomainRecords(recordType string) {
 		contentType := "application/x-www-form-urlencoded"
 		if cb.DNS.Secret != "" {
 			method = "POST"
-			postPara = replacePara(cb.DNS.Secret, ipAddr, domain, recordType, cb.TTL)
+			body, err := replacePara(cb.DNS.Secret, ipAddr, domain, recordType, cb.TTL)
+			if err != nil {
+				util.Log("Callback的模板不正确, 异常信息: %s", err)
+				domain.UpdateStatus = config.UpdatedFailed
+				continue
+			}
+			postPara = body
 			if json.Valid([]byte(postPara)) {
 				contentType = "application/json"
 			}
 		}
-		requestURL := replacePara(cb.DNS.ID, ipAddr, domain, recordType, cb.TTL)
+		// 指定了请求方法则使用指定的
+		if m := strings.ToUpper(strings.TrimSpace(cb.DNS.Method)); m != "" {
+			method = m
+		}
+		if method == http.MethodGet {
+			postPara = ""
+		}
+		requestURL, err := replacePara(cb.DNS.ID, ipAddr, domain, recordType, cb.TTL)
+		if err != nil {
+			util.Log("Callback的模板不正确, 异常信息: %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			continue
+		}
 		u, err := url.Parse(requestURL)
 		if err != nil {
 			util.Log("Callback的URL不正确")
@@ -87,22 +107,70 @@ func (cb *Callback) addUpdateDomainRecords(recordType string) {
 			return
 		}
 		req.Header.Add("content-type", contentType)
+		for k, v := range config.ExtractHeaders(cb.DNS.Headers) {
+			req.Header.Set(k, v)
+		}
 
-		clt := util.CreateHTTPClient()
+		clt := createHTTPClient(cb.DNS)
 		resp, err := clt.Do(req)
 		body, err := util.GetHTTPResponseOrg(resp, err)
-		if err == nil {
-			util.Log("Callback调用成功, 域名: %s, IP: %s, 返回数据: %s", domain, ipAddr, string(body))
-			domain.UpdateStatus = config.UpdatedSuccess
-		} else {
+		if err != nil {
 			util.Log("Callback调用失败, 异常信息: %s", err)
 			domain.UpdateStatus = config.UpdatedFailed
+			continue
+		}
+		if !callbackSucceeded(cb.DNS.SuccessMatch, body) {
+			util.Log("Callback调用失败, 返回数据与 %s 不匹配: %s", cb.DNS.SuccessMatch, string(body))
+			domain.UpdateStatus = config.UpdatedFailed
+			continue
+		}
+		util.Log("Callback调用成功, 域名: %s, IP: %s, 返回数据: %s", domain, ipAddr, string(body))
+		domain.UpdateStatus = config.UpdatedSuccess
+	}
+}
+
+// callbackSucceeded 根据返回数据判断是否成功
+// 为空不判断; json:a.b=value 比较JSON字段; 其它为包含的字符串
+func callbackSucceeded(pattern string, body []byte) bool {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" {
+		return true
+	}
+
+	jsonPattern, ok := strings.CutPrefix(pattern, "json:")
+	if !ok {
+		return strings.Contains(string(body), pattern)
+	}
+
+	path, expected, _ := strings.Cut(jsonPattern, "=")
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return false
+	}
+	for _, key := range strings.Split(strings.TrimSpace(path), ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if value, ok = m[key]; !ok {
+			return false
 		}
 	}
+	return fmt.Sprint(value) == strings.TrimSpace(expected)
 }
 
-// replacePara 替换参数
-func replacePara(orgPara, ipAddr string, domain *config.Domain, recordType string, ttl string) string {
+// callbackTemplateData 模板中可使用的变量, 如 {{.Domain}}
+type callbackTemplateData struct {
+	Domain     string
+	DomainName string
+	SubDomain  string
+	RecordType string
+	IP         string
+	TTL        string
+}
+
+// replacePara 替换参数, 同时支持 #{ip} 和 {{.IP}} 两种写法
+func replacePara(orgPara, ipAddr string, domain *config.Domain, recordType string, ttl string) (string, error) {
 	// params 使用 map 以便添加更多参数
 	params := map[string]string{
 		"ip":         ipAddr,
@@ -126,5 +194,26 @@ func replacePara(orgPara, ipAddr string, domain *config.Domain, recordType strin
 		oldnew = append(oldnew, k, v)
 	}
 
-	return strings.NewReplacer(oldnew...).Replace(orgPara)
+	para := strings.NewReplacer(oldnew...).Replace(orgPara)
+	if !strings.Contains(para, "{{") {
+		return para, nil
+	}
+
+	tmpl, err := template.New("callback").Parse(para)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, callbackTemplateData{
+		Domain:     domain.String(),
+		DomainName: domain.DomainName,
+		SubDomain:  domain.SubDomain,
+		RecordType: recordType,
+		IP:         ipAddr,
+		TTL:        ttl,
+	})
+	if err != nil {
+		return "", err
+	}
+	return buf.String(), nil
 }
@@ -0,0 +1,95 @@
+package dns
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestCallbackSucceeded 测试根据返回数据判断 Callback 是否成功
+func TestCallbackSucceeded(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		body    string
+		want    bool
+	}{
+		{"未设置", "", `{"code":1}`, true},
+		{"仅空格", "  ", "error", true},
+		{"包含字符串", "good", "status: good 1.1.1.1", true},
+		{"不包含字符串", "good", "nochg", false},
+		{"JSON字段", "json:code=0", `{"code":0}`, true},
+		{"JSON字符串字段", "json:status=ok", `{"status":"ok"}`, true},
+		{"JSON嵌套字段", "json: data.result = true ", `{"data":{"result":true}}`, true},
+		{"JSON字段不匹配", "json:code=0", `{"code":1}`, false},
+		{"JSON字段不存在", "json:data.result=true", `{"data":{}}`, false},
+		{"JSON路径不是对象", "json:data.result=true", `{"data":"ok"}`, false},
+		{"不是JSON", "json:code=0", "code=0", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := callbackSucceeded(tt.pattern, []byte(tt.body)); got != tt.want {
+				t.Errorf("%q 匹配 %q 期待 %v, 得到 %v", tt.pattern, tt.body, tt.want, got)
+			}
+		})
+	}
+}
+
+// TestCallback 测试 Callback 的请求头、参数替换及返回数据匹配
+func TestCallback(t *testing.T) {
+	type request struct {
+		method, uri, contentType, token, body string
+	}
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, request{r.Method, r.RequestURI, r.Header.Get("Content-Type"), r.Header.Get("X-Token"), string(body)})
+		if r.URL.Query().Get("domain") == "fail.example.com" {
+			w.Write([]byte(`{"code":1,"msg":"denied"}`))
+			return
+		}
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+
+	dc := &config.DnsConfig{TTL: "300"}
+	dc.DNS = config.DNS{
+		Name:         "callback",
+		ID:           server.URL + "/update?domain=#{domain}&ip=#{ip}&type={{.RecordType}}",
+		Secret:       `{"sub":"{{.SubDomain}}","ip":"#{ip}","ttl":#{ttl},"zone":"#{zone}"}`,
+		Headers:      "X-Token: secret\nContent-Type: application/vnd.test+json",
+		SuccessMatch: "json:code=0",
+	}
+	dc.Ipv4.Enable = true
+	dc.Ipv4.GetType = "push"
+	dc.Ipv4.Domains = []string{"www.example.com?zone=z1", "fail.example.com"}
+
+	cb := &Callback{}
+	cb.Init(dc, &util.IpCache{}, &util.IpCache{})
+	cb.Domains.Ipv4Addr = "2.2.2.2"
+	domains := cb.AddUpdateDomainRecords()
+
+	if got := domains.Ipv4Domains[0].UpdateStatus; got != config.UpdatedSuccess {
+		t.Errorf("期待 www.example.com 更新成功, 得到 %s", got)
+	}
+	if got := domains.Ipv4Domains[1].UpdateStatus; got != config.UpdatedFailed {
+		t.Errorf("期待返回数据不匹配的 fail.example.com 更新失败, 得到 %s", got)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("期待 2 次请求, 得到 %d 次", len(requests))
+	}
+	want := request{
+		method:      http.MethodPost,
+		uri:         "/update?domain=www.example.com&ip=2.2.2.2&type=A",
+		contentType: "application/vnd.test+json",
+		token:       "secret",
+		body:        `{"sub":"www","ip":"2.2.2.2","ttl":300,"zone":"z1"}`,
+	}
+	if requests[0] != want {
+		t.Errorf("期待请求\n%+v\n得到\n%+v", want, requests[0])
+	}
+}
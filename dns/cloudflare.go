@@ -3,6 +3,7 @@ package dns
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -13,30 +14,58 @@ import (
 	"github.com/jeessy2/ddns-go/v6/util"
 )
 
-const zonesAPI = "https://api.cloudflare.com/client/v4/zones"
+// managedComment 托管区域中 ddns-go 创建的记录的备注, 仅删除带有该备注的记录
+const managedComment = "managed by ddns-go"
+
+// getManagedComment 获得实例的备注, 设置了实例ID时为 managed by ddns-go (ID)
+func getManagedComment(id string) string {
+	if id == "" {
+		return managedComment
+	}
+	return managedComment + " (" + id + ")"
+}
+
+// zonesAPI 区域接口, 测试时替换为本地服务
+var zonesAPI = "https://api.cloudflare.com/client/v4/zones"
 
 // Cloudflare Cloudflare实现
 type Cloudflare struct {
-	DNS     config.DNS
-	Domains config.Domains
-	TTL     int
+	DNS         config.DNS
+	Domains     config.Domains
+	TTL         int
+	managedZone bool
+	// managedComment 托管区域中标记记录的备注
+	managedComment string
+	// zones 账户中的区域, 本次运行中只查询一次
+	zones []CloudflareZone
 }
 
 // CloudflareZonesResp cloudflare zones返回结果
 type CloudflareZonesResp struct {
 	CloudflareStatus
-	Result []struct {
-		ID     string
-		Name   string
-		Status string
-		Paused bool
-	}
+	Result     []CloudflareZone
+	ResultInfo CloudflareResultInfo `json:"result_info"`
+}
+
+// CloudflareZone 区域
+type CloudflareZone struct {
+	ID     string
+	Name   string
+	Status string
+	Paused bool
 }
 
 // CloudflareRecordsResp records
 type CloudflareRecordsResp struct {
 	CloudflareStatus
-	Result []CloudflareRecord
+	Result     []CloudflareRecord
+	ResultInfo CloudflareResultInfo `json:"result_info"`
+}
+
+// CloudflareResultInfo 列表的分页信息
+type CloudflareResultInfo struct {
+	Page       int `json:"page"`
+	TotalPages int `json:"total_pages"`
 }
 
 // CloudflareRecord 记录实体
@@ -61,6 +90,8 @@ func (cf *Cloudflare) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, i
 	cf.Domains.Ipv4Cache = ipv4cache
 	cf.Domains.Ipv6Cache = ipv6cache
 	cf.DNS = dnsConf.DNS
+	cf.managedZone = dnsConf.ManagedZone
+	cf.managedComment = getManagedComment(dnsConf.ManagedZoneID)
 	cf.Domains.GetNewIp(dnsConf)
 	if dnsConf.TTL == "" {
 		// 默认1 auto ttl
@@ -113,8 +144,8 @@ func (cf *Cloudflare) addUpdateDomainRecords(recordType string) {
 		params.Set("name", domain.ToASCII())
 		params.Set("per_page", "50")
 		// Add a comment only if it exists
-		if c := domain.GetCustomParams().Get("comment"); c != "" {
-			params.Set("comment", c)
+		if domain.Comment != "" {
+			params.Set("comment", domain.Comment)
 		}
 
 		zoneID := result.Result[0].ID
@@ -152,13 +183,20 @@ func (cf *Cloudflare) addUpdateDomainRecords(recordType string) {
 
 // 创建
 func (cf *Cloudflare) create(zoneID string, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	record := &CloudflareRecord{
 		Type:    recordType,
 		Name:    domain.ToASCII(),
 		Content: ipAddr,
 		Proxied: false,
 		TTL:     cf.TTL,
-		Comment: domain.GetCustomParams().Get("comment"),
+		Comment: domain.Comment,
+	}
+	// 托管区域中标记为 ddns-go 创建
+	if cf.managedZone && record.Comment == "" {
+		record.Comment = cf.managedComment
 	}
 	record.Proxied = domain.GetCustomParams().Get("proxied") == "true"
 	var status CloudflareStatus
@@ -189,12 +227,15 @@ func (cf *Cloudflare) modify(result CloudflareRecordsResp, zoneID string, domain
 	for _, record := range result.Result {
 		// 相同不修改
 		if record.Content == ipAddr {
-			util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+			setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 			continue
 		}
 		var status CloudflareStatus
 		record.Content = ipAddr
 		record.TTL = cf.TTL
+		if domain.Comment != "" {
+			record.Comment = domain.Comment
+		}
 		// 存在参数才修改proxied
 		if domain.GetCustomParams().Has("proxied") {
 			record.Proxied = domain.GetCustomParams().Get("proxied") == "true"
@@ -222,10 +263,255 @@ func (cf *Cloudflare) modify(result CloudflareRecordsResp, zoneID string, domain
 	}
 }
 
+// DeleteRecords 删除域名的记录
+func (cf *Cloudflare) DeleteRecords(domain *config.Domain, recordType string) (count int, err error) {
+	result, err := cf.getZones(domain)
+	if err != nil {
+		return
+	}
+	if len(result.Result) == 0 {
+		return 0, errors.New(util.LogStr("在DNS服务商中未找到根域名: %s", domain.DomainName))
+	}
+	zoneID := result.Result[0].ID
+
+	params := url.Values{}
+	params.Set("type", recordType)
+	params.Set("name", domain.ToASCII())
+	params.Set("per_page", "50")
+	if domain.Comment != "" {
+		params.Set("comment", domain.Comment)
+	}
+	var records CloudflareRecordsResp
+	err = cf.request(
+		"GET",
+		fmt.Sprintf(zonesAPI+"/%s/dns_records?%s", zoneID, params.Encode()),
+		nil,
+		&records,
+	)
+	if err != nil {
+		return
+	}
+	if !records.Success {
+		return 0, errors.New(strings.Join(records.Messages, ", "))
+	}
+
+	for _, record := range records.Result {
+		var status CloudflareStatus
+		err = cf.request(
+			"DELETE",
+			fmt.Sprintf(zonesAPI+"/%s/dns_records/%s", zoneID, record.ID),
+			nil,
+			&status,
+		)
+		if err != nil {
+			return
+		}
+		if !status.Success {
+			return count, errors.New(strings.Join(status.Messages, ", "))
+		}
+		count++
+	}
+	return
+}
+
+// DeleteStaleRecords 删除托管区域中由 ddns-go 创建但已不在配置中的记录
+// domains 为全部配置中的域名, 只查询这些域名所在的区域, 只删除备注为本实例 managedComment 的A/AAAA记录
+func (cf *Cloudflare) DeleteStaleRecords(domains *config.Domains) {
+	if cf.zones == nil {
+		var err error
+		if cf.zones, err = cf.listZones(); err != nil {
+			// 查询失败时不删除任何记录
+			util.Log("托管区域查询失败, 跳过删除过期记录! 异常信息: %s", err)
+			return
+		}
+	}
+
+	keep := make(map[string]bool)
+	zoneIDs := make(map[string]bool)
+	add := func(recordType string, list []*config.Domain) {
+		for _, domain := range list {
+			keep[recordType+" "+domain.ToASCII()] = true
+			if zoneID := findCloudflareZone(strings.ToLower(domain.ToASCII()), cf.zones); zoneID != "" {
+				zoneIDs[zoneID] = true
+			}
+		}
+	}
+	add("A", domains.Ipv4Domains)
+	add("AAAA", domains.Ipv6Domains)
+
+	for _, zone := range cf.zones {
+		if !zoneIDs[zone.ID] {
+			continue
+		}
+		records, err := cf.listManagedRecords(zone.ID)
+		if err != nil {
+			util.Log("托管区域 %s 查询失败, 跳过删除过期记录", zone.Name)
+			continue
+		}
+
+		for _, record := range records {
+			if record.Comment != cf.managedComment ||
+				(record.Type != "A" && record.Type != "AAAA") ||
+				keep[record.Type+" "+record.Name] {
+				continue
+			}
+			var status CloudflareStatus
+			err = cf.request(
+				"DELETE",
+				fmt.Sprintf(zonesAPI+"/%s/dns_records/%s", zone.ID, record.ID),
+				nil,
+				&status,
+			)
+			if err == nil && !status.Success {
+				err = errors.New(strings.Join(status.Messages, ", "))
+			}
+			if err != nil {
+				util.Log("删除过期记录 %s(%s) 失败! 异常信息: %s", record.Name, record.Type, err)
+				continue
+			}
+			util.Log("删除过期记录 %s(%s) 成功! IP: %s", record.Name, record.Type, record.Content)
+		}
+	}
+}
+
+// listZones 获得账户中全部已启用的区域
+func (cf *Cloudflare) listZones() (zones []CloudflareZone, err error) {
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("status", "active")
+		params.Set("per_page", "50")
+		params.Set("page", strconv.Itoa(page))
+		var result CloudflareZonesResp
+		err = cf.request("GET", fmt.Sprintf(zonesAPI+"?%s", params.Encode()), nil, &result)
+		if err == nil && !result.Success {
+			err = errors.New(strings.Join(result.Messages, ", "))
+		}
+		if err != nil {
+			return nil, err
+		}
+		zones = append(zones, result.Result...)
+		if page >= result.ResultInfo.TotalPages {
+			return
+		}
+	}
+}
+
+// listManagedRecords 获得区域中备注为本实例 managedComment 的全部记录
+func (cf *Cloudflare) listManagedRecords(zoneID string) (records []CloudflareRecord, err error) {
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("comment", cf.managedComment)
+		params.Set("per_page", "100")
+		params.Set("page", strconv.Itoa(page))
+		var result CloudflareRecordsResp
+		err = cf.request("GET", fmt.Sprintf(zonesAPI+"/%s/dns_records?%s", zoneID, params.Encode()), nil, &result)
+		if err == nil && !result.Success {
+			err = errors.New(strings.Join(result.Messages, ", "))
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, result.Result...)
+		if page >= result.ResultInfo.TotalPages {
+			return
+		}
+	}
+}
+
+// UpdatePTR 在反向解析区域中将IP指向域名
+func (cf *Cloudflare) UpdatePTR(domain *config.Domain, ipAddr string) error {
+	name, err := util.ReverseAddr(ipAddr)
+	if err != nil {
+		return err
+	}
+
+	// 查询一次账户中的区域, 使用最长的匹配, 如 2.0.192.in-addr.arpa
+	if cf.zones == nil {
+		if cf.zones, err = cf.listZones(); err != nil {
+			return err
+		}
+	}
+	zoneID := findCloudflareZone(name, cf.zones)
+	if zoneID == "" {
+		return errors.New(util.LogStr("在DNS服务商中未找到反向解析区域: %s", name))
+	}
+
+	params := url.Values{}
+	params.Set("type", "PTR")
+	params.Set("name", name)
+	var records CloudflareRecordsResp
+	err = cf.request(
+		"GET",
+		fmt.Sprintf(zonesAPI+"/%s/dns_records?%s", zoneID, params.Encode()),
+		nil,
+		&records,
+	)
+	if err != nil {
+		return err
+	}
+	if !records.Success {
+		return errors.New(strings.Join(records.Messages, ", "))
+	}
+
+	record := CloudflareRecord{Type: "PTR", Name: name, TTL: cf.TTL}
+	method, api := "POST", fmt.Sprintf(zonesAPI+"/%s/dns_records", zoneID)
+	if len(records.Result) > 0 {
+		record = records.Result[0]
+		// 相同不修改
+		if record.Content == domain.ToASCII() {
+			return nil
+		}
+		method, api = "PUT", fmt.Sprintf(zonesAPI+"/%s/dns_records/%s", zoneID, record.ID)
+	}
+	record.Content = domain.ToASCII()
+
+	var status CloudflareStatus
+	err = cf.request(method, api, record, &status)
+	if err != nil {
+		return err
+	}
+	if !status.Success {
+		return errors.New(strings.Join(status.Messages, ", "))
+	}
+	return nil
+}
+
+// findCloudflareZone 获得包含 name 的最长的区域ID, 没有时返回空
+func findCloudflareZone(name string, zones []CloudflareZone) (zoneID string) {
+	longest := 0
+	for _, zone := range zones {
+		zoneName := strings.ToLower(strings.TrimSuffix(zone.Name, "."))
+		if zoneName != "" && len(zoneName) > longest && (name == zoneName || strings.HasSuffix(name, "."+zoneName)) {
+			zoneID, longest = zone.ID, len(zoneName)
+		}
+	}
+	return
+}
+
 // 获得域名记录列表
 func (cf *Cloudflare) getZones(domain *config.Domain) (result CloudflareZonesResp, err error) {
+	return cf.getZonesByName(getCloudflareZoneName(domain))
+}
+
+// getCloudflareZoneName 获得域名所在的区域, 多级子域名可通过参数 zone 指定, 如 a.b.c.example.com?zone=c.example.com
+// 未指定或指定的区域不包含该域名时使用根域名
+func getCloudflareZoneName(domain *config.Domain) string {
+	zone := strings.ToLower(strings.Trim(strings.TrimSpace(domain.GetCustomParams().Get("zone")), "."))
+	if zone == "" {
+		return domain.DomainName
+	}
+	name := strings.ToLower(domain.String())
+	if name != zone && !strings.HasSuffix(name, "."+zone) {
+		util.Log("区域 %s 不包含域名 %s, 将使用根域名", zone, domain)
+		return domain.DomainName
+	}
+	return zone
+}
+
+// getZonesByName 按名称获得区域列表
+func (cf *Cloudflare) getZonesByName(name string) (result CloudflareZonesResp, err error) {
 	params := url.Values{}
-	params.Set("name", domain.DomainName)
+	params.Set("name", name)
 	params.Set("status", "active")
 	params.Set("per_page", "50")
 
@@ -256,7 +542,7 @@ func (cf *Cloudflare) request(method string, url string, data interface{}, resul
 	req.Header.Set("Authorization", "Bearer "+cf.DNS.Secret)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(cf.DNS)
 	resp, err := client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
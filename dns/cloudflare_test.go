@@ -0,0 +1,125 @@
+package dns
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// TestGetCloudflareZoneName 测试多级子域名指定区域
+func TestGetCloudflareZoneName(t *testing.T) {
+	tests := []struct {
+		subDomain    string
+		customParams string
+		expected     string
+	}{
+		{"a.b.c", "", "example.com"},
+		{"a.b.c", "zone=c.example.com", "c.example.com"},
+		{"a.b.c", "zone=b.c.example.com", "b.c.example.com"},
+		{"a.b.c", "zone=a.b.c.example.com", "a.b.c.example.com"},
+		{"a.b.c", "zone=C.Example.com.", "c.example.com"},
+		{"a.b.c", "zone=bc.example.com", "example.com"},
+		{"a.b.c", "zone=other.com", "example.com"},
+		{"", "zone=example.com", "example.com"},
+	}
+
+	for _, tt := range tests {
+		domain := &config.Domain{SubDomain: tt.subDomain, DomainName: "example.com", CustomParams: tt.customParams}
+		if got := getCloudflareZoneName(domain); got != tt.expected {
+			t.Errorf("%s?%s 期待区域 %s, 得到 %s", domain, tt.customParams, tt.expected, got)
+		}
+	}
+}
+
+// TestCloudflareDeleteStaleRecords 测试分页查询已配置域名所在的区域, 只删除本实例创建且已不在任何配置中的记录
+func TestCloudflareDeleteStaleRecords(t *testing.T) {
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		switch {
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.Write([]byte(`{"success":true}`))
+		case r.URL.Path == "/zones" && page == "1":
+			w.Write([]byte(`{"success":true,"result":[{"id":"z1","name":"example.com"}],"result_info":{"page":1,"total_pages":2}}`))
+		case r.URL.Path == "/zones":
+			// 不含任何配置中域名的区域, 不查询
+			w.Write([]byte(`{"success":true,"result":[{"id":"z2","name":"example.net"}],"result_info":{"page":2,"total_pages":2}}`))
+		case r.URL.Path == "/zones/z1/dns_records" && r.URL.Query().Get("comment") != "managed by ddns-go (home)":
+			t.Errorf("期待按本实例的备注查询, 得到 %s", r.URL.Query().Get("comment"))
+		case r.URL.Path == "/zones/z1/dns_records" && page == "1":
+			w.Write([]byte(`{"success":true,"result":[
+				{"id":"r1","name":"a.example.com","type":"A","comment":"managed by ddns-go (home)"},
+				{"id":"r2","name":"b.example.com","type":"A","comment":"managed by ddns-go (home)"}
+			],"result_info":{"page":1,"total_pages":2}}`))
+		case r.URL.Path == "/zones/z1/dns_records":
+			w.Write([]byte(`{"success":true,"result":[
+				{"id":"r3","name":"old.example.com","type":"AAAA","comment":"managed by ddns-go (home)"},
+				{"id":"r4","name":"manual.example.com","type":"A","comment":"manual"},
+				{"id":"r5","name":"office.example.com","type":"A","comment":"managed by ddns-go"}
+			],"result_info":{"page":2,"total_pages":2}}`))
+		default:
+			t.Errorf("未预期的请求 %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+	defer func(api string) { zonesAPI = api }(zonesAPI)
+	zonesAPI = server.URL + "/zones"
+
+	// 两个托管区域的配置共用 example.com
+	conf := &config.Config{DnsConf: []config.DnsConfig{{ManagedZone: true, ManagedZoneID: "home"}, {ManagedZone: true, ManagedZoneID: "home"}}}
+	conf.DnsConf[0].Ipv4.Domains = []string{"a.example.com"}
+	conf.DnsConf[1].Ipv4.Domains = []string{"b.example.com"}
+	keep := configuredDomains(conf)
+
+	cf := &Cloudflare{DNS: config.DNS{Name: "cloudflare", Secret: "token"}, managedComment: getManagedComment("home")}
+	cf.DeleteStaleRecords(&keep)
+
+	want := []string{"/zones/z1/dns_records/r3"}
+	if !slices.Equal(deleted, want) {
+		t.Errorf("期待删除 %v, 得到 %v", want, deleted)
+	}
+}
+
+// TestCloudflareUpdatePTR 测试更新PTR记录时只查询一次区域, 使用最长的反向解析区域
+func TestCloudflareUpdatePTR(t *testing.T) {
+	zoneRequests := 0
+	var created string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/zones":
+			zoneRequests++
+			w.Write([]byte(`{"success":true,"result":[
+				{"id":"z1","name":"ip6.arpa"},
+				{"id":"z2","name":"8.b.d.0.1.0.0.2.ip6.arpa"},
+				{"id":"z3","name":"example.com"}
+			],"result_info":{"page":1,"total_pages":1}}`))
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"success":true,"result":[]}`))
+		case r.Method == http.MethodPost:
+			created = r.URL.Path
+			w.Write([]byte(`{"success":true}`))
+		default:
+			t.Errorf("未预期的请求 %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+	defer func(api string) { zonesAPI = api }(zonesAPI)
+	zonesAPI = server.URL + "/zones"
+
+	cf := &Cloudflare{DNS: config.DNS{Name: "cloudflare", Secret: "token"}}
+	for _, domain := range []*config.Domain{{DomainName: "example.com", SubDomain: "a"}, {DomainName: "example.com", SubDomain: "b"}} {
+		if err := cf.UpdatePTR(domain, "2001:db8::1"); err != nil {
+			t.Fatalf("更新PTR失败: %s", err)
+		}
+	}
+	if zoneRequests != 1 || created != "/zones/z2/dns_records" {
+		t.Errorf("期待只查询一次区域并使用 z2, 得到 %d %s", zoneRequests, created)
+	}
+	if zoneID := findCloudflareZone("4.3.2.1.in-addr.arpa", cf.zones); zoneID != "" {
+		t.Errorf("期待没有匹配的区域, 得到 %s", zoneID)
+	}
+}
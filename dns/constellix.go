@@ -0,0 +1,181 @@
+package dns
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const constellixEndpoint = "https://api.dns.constellix.com/v1"
+
+// errConstellixNotFound 接口返回 404, 如查询的记录不存在
+var errConstellixNotFound = errors.New("404 Not Found")
+
+// Constellix Constellix DNS API v1
+// https://api-docs.constellix.com/
+type Constellix struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+}
+
+// ConstellixRecord 记录实体
+type ConstellixRecord struct {
+	ID         int                    `json:"id,omitempty"`
+	Name       string                 `json:"name"`
+	TTL        int                    `json:"ttl"`
+	RoundRobin []ConstellixRoundRobin `json:"roundRobin"`
+}
+
+// ConstellixRoundRobin 记录的值
+type ConstellixRoundRobin struct {
+	Value       string `json:"value"`
+	DisableFlag bool   `json:"disableFlag"`
+}
+
+// Init 初始化
+func (cns *Constellix) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	cns.Domains.Ipv4Cache = ipv4cache
+	cns.Domains.Ipv6Cache = ipv6cache
+	cns.DNS = dnsConf.DNS
+	cns.Domains.GetNewIp(dnsConf)
+	if dnsConf.TTL == "" {
+		// 默认600s
+		cns.TTL = 600
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			cns.TTL = 600
+		} else {
+			cns.TTL = ttl
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (cns *Constellix) AddUpdateDomainRecords() config.Domains {
+	cns.addUpdateDomainRecords("A")
+	cns.addUpdateDomainRecords("AAAA")
+	return cns.Domains
+}
+
+func (cns *Constellix) addUpdateDomainRecords(recordType string) {
+	ipAddr, domains := cns.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	for _, domain := range domains {
+		var zones []struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		}
+		err := cns.request(http.MethodGet, "/domains/search?exact="+url.QueryEscape(domain.DomainName), nil, &zones)
+		if err != nil {
+			util.Log("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+		if len(zones) == 0 {
+			util.Log("在DNS服务商中未找到根域名: %s", domain.DomainName)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+		recordsPath := fmt.Sprintf("/domains/%d/records/%s", zones[0].ID, recordType)
+
+		// 根域名的记录名称为空, 未找到记录时返回 404
+		var records []ConstellixRecord
+		err = cns.request(http.MethodGet, recordsPath+"/search?exact="+url.QueryEscape(domain.SubDomain), nil, &records)
+		if err != nil && err != errConstellixNotFound {
+			util.Log("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		record := ConstellixRecord{
+			Name:       domain.SubDomain,
+			TTL:        cns.TTL,
+			RoundRobin: []ConstellixRoundRobin{{Value: ipAddr}},
+		}
+		if len(records) == 0 {
+			cns.create(domain, recordsPath, record, ipAddr)
+			continue
+		}
+		old := records[0]
+		if len(old.RoundRobin) == 1 && old.RoundRobin[0].Value == ipAddr {
+			setUpdateResult(domain, ipAddr, resultUnchanged, nil)
+			continue
+		}
+		cns.modify(domain, fmt.Sprintf("%s/%d", recordsPath, old.ID), record, ipAddr)
+	}
+}
+
+// create 创建新的解析
+func (cns *Constellix) create(domain *config.Domain, recordsPath string, record ConstellixRecord, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
+	err := cns.request(http.MethodPost, recordsPath, record, nil)
+	if err != nil {
+		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// modify 更新解析
+func (cns *Constellix) modify(domain *config.Domain, recordPath string, record ConstellixRecord, ipAddr string) {
+	err := cns.request(http.MethodPut, recordPath, record, nil)
+	if err != nil {
+		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// constellixSign 签名, 格式为 apiKey:HMAC-SHA1(毫秒时间戳):毫秒时间戳, HMAC 使用 base64 编码
+func constellixSign(apiKey string, secret string, now time.Time) string {
+	timestamp := strconv.FormatInt(now.UnixMilli(), 10)
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	return apiKey + ":" + base64.StdEncoding.EncodeToString(mac.Sum(nil)) + ":" + timestamp
+}
+
+// request 统一请求接口
+func (cns *Constellix) request(method string, path string, data interface{}, result interface{}) error {
+	var byt []byte
+	if data != nil {
+		byt, _ = json.Marshal(data)
+	}
+	req, err := http.NewRequest(method, constellixEndpoint+path, bytes.NewReader(byt))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-cns-security-token", constellixSign(cns.DNS.ID, cns.DNS.Secret, time.Now()))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	client := createHTTPClient(cns.DNS)
+	resp, err := client.Do(req)
+	if err == nil && resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return errConstellixNotFound
+	}
+	return util.GetHTTPResponse(resp, err, result)
+}
@@ -0,0 +1,25 @@
+package dns
+
+import (
+	"testing"
+	"time"
+)
+
+// TestConstellixSign 测试 Constellix 的签名
+// 期待值由 printf '%s' <时间戳> | openssl dgst -sha1 -hmac <secret> -binary | base64 计算
+func TestConstellixSign(t *testing.T) {
+	tests := []struct {
+		apiKey, secret string
+		now            time.Time
+		want           string
+	}{
+		{"key", "secret", time.Unix(1297544344, 0), "key:8e3jlD18aTIzE/9P2nfTkkSdeP8=:1297544344000"},
+		// 时间戳精确到毫秒
+		{"apikey", "s3cr3t", time.UnixMilli(1700000000123), "apikey:pEORX0cqL5Ovfave6cQxRv1L2bI=:1700000000123"},
+	}
+	for _, tt := range tests {
+		if got := constellixSign(tt.apiKey, tt.secret, tt.now); got != tt.want {
+			t.Errorf("期待 Constellix 签名为 %s, 得到 %s", tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package dns
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const dnsExitEndpoint = "https://api.dnsexit.com/dns/ud/"
+
+// DNSExit DNSExit 动态DNS接口
+// https://dnsexit.com/dns/dns-api/#dynamic-ip-update
+type DNSExit struct {
+	DNS     config.DNS
+	Domains config.Domains
+}
+
+// DNSExitResp 返回值, 如 {"code":0,"message":"Success"}
+type DNSExitResp struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Init 初始化
+func (de *DNSExit) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	de.Domains.Ipv4Cache = ipv4cache
+	de.Domains.Ipv6Cache = ipv6cache
+	de.DNS = dnsConf.DNS
+	de.Domains.GetNewIp(dnsConf)
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (de *DNSExit) AddUpdateDomainRecords() config.Domains {
+	de.addUpdateDomainRecords("A")
+	de.addUpdateDomainRecords("AAAA")
+	return de.Domains
+}
+
+func (de *DNSExit) addUpdateDomainRecords(recordType string) {
+	ipAddr, domains := de.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	for _, domain := range domains {
+		de.modify(domain, ipAddr)
+	}
+}
+
+// 修改
+func (de *DNSExit) modify(domain *config.Domain, ipAddr string) {
+	result, err := de.request(domain, ipAddr)
+	if err != nil {
+		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	// 0 成功, 1 IP未变化, 其它为失败, 如 2 API密钥错误、4 更新过于频繁
+	switch result.Code {
+	case 0:
+		setUpdateResult(domain, ipAddr, resultUpdated, nil)
+	case 1:
+		setUpdateResult(domain, ipAddr, resultUnchanged, nil)
+	default:
+		setUpdateResult(domain, ipAddr, resultFailed, fmt.Errorf("%s (%d)", result.Message, result.Code))
+	}
+}
+
+// request 统一请求接口
+func (de *DNSExit) request(domain *config.Domain, ipAddr string) (result DNSExitResp, err error) {
+	query := url.Values{}
+	query.Set("apikey", de.DNS.Secret)
+	query.Set("host", domain.ToASCII())
+	query.Set("ip", ipAddr)
+	// 域名的自定义参数
+	for k, v := range domain.GetCustomParams() {
+		for _, value := range v {
+			query.Add(k, value)
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, dnsExitEndpoint+"?"+query.Encode(), http.NoBody)
+	if err != nil {
+		return
+	}
+
+	client := createHTTPClient(de.DNS)
+	resp, err := client.Do(req)
+	body, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		return
+	}
+	return parseDNSExitResp(body)
+}
+
+// parseDNSExitResp 解析返回值, 兼容 JSON 及文本格式, 如 0=Success
+func parseDNSExitResp(body []byte) (result DNSExitResp, err error) {
+	if json.Unmarshal(body, &result) == nil {
+		return
+	}
+	// 文本格式的最后一行为结果
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	code, msg, ok := strings.Cut(strings.TrimSpace(lines[len(lines)-1]), "=")
+	if result.Code, err = strconv.Atoi(code); !ok || err != nil {
+		return result, errors.New(util.LogStr("返回内容: %s", string(body)))
+	}
+	result.Message = msg
+	return
+}
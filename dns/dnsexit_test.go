@@ -0,0 +1,24 @@
+package dns
+
+import "testing"
+
+// TestParseDNSExitResp 测试解析 DNSExit 的 JSON 及文本返回值
+func TestParseDNSExitResp(t *testing.T) {
+	tests := []struct {
+		body    string
+		code    int
+		message string
+		err     bool
+	}{
+		{`{"code":0,"message":"Success"}`, 0, "Success", false},
+		{`{"code":2,"message":"Invalid API Key"}`, 2, "Invalid API Key", false},
+		{"HTTP/1.1 200 OK\n1=IP is the same as the current IP", 1, "IP is the same as the current IP", false},
+		{"<html>error</html>", 0, "", true},
+	}
+	for _, tt := range tests {
+		result, err := parseDNSExitResp([]byte(tt.body))
+		if (err != nil) != tt.err || result.Code != tt.code || result.Message != tt.message {
+			t.Errorf("解析 %q 期待 %d %q %v, 得到 %d %q %v", tt.body, tt.code, tt.message, tt.err, result.Code, result.Message, err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package dns
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const dnsMadeEasyEndpoint = "https://api.dnsmadeeasy.com/V2.0"
+
+// DNSMadeEasy DNS Made Easy API v2.0
+// https://api-docs.dnsmadeeasy.com/
+type DNSMadeEasy struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+}
+
+// DNSMadeEasyRecord 记录实体
+type DNSMadeEasyRecord struct {
+	ID          int    `json:"id,omitempty"`
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Value       string `json:"value"`
+	TTL         int    `json:"ttl"`
+	GtdLocation string `json:"gtdLocation"`
+}
+
+// Init 初始化
+func (dme *DNSMadeEasy) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	dme.Domains.Ipv4Cache = ipv4cache
+	dme.Domains.Ipv6Cache = ipv6cache
+	dme.DNS = dnsConf.DNS
+	dme.Domains.GetNewIp(dnsConf)
+	if dnsConf.TTL == "" {
+		// 默认600s
+		dme.TTL = 600
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			dme.TTL = 600
+		} else {
+			dme.TTL = ttl
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (dme *DNSMadeEasy) AddUpdateDomainRecords() config.Domains {
+	dme.addUpdateDomainRecords("A")
+	dme.addUpdateDomainRecords("AAAA")
+	return dme.Domains
+}
+
+func (dme *DNSMadeEasy) addUpdateDomainRecords(recordType string) {
+	ipAddr, domains := dme.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	for _, domain := range domains {
+		var zone struct {
+			ID int `json:"id"`
+		}
+		err := dme.request(http.MethodGet, "/dns/managed/name?domainname="+url.QueryEscape(domain.DomainName), nil, &zone)
+		if err != nil {
+			util.Log("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+		if zone.ID == 0 {
+			util.Log("在DNS服务商中未找到根域名: %s", domain.DomainName)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		// 根域名的记录名称为空
+		var records struct {
+			Data []DNSMadeEasyRecord `json:"data"`
+		}
+		path := fmt.Sprintf("/dns/managed/%d/records?type=%s&recordName=%s", zone.ID, recordType, url.QueryEscape(domain.SubDomain))
+		err = dme.request(http.MethodGet, path, nil, &records)
+		if err != nil {
+			util.Log("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		var find *DNSMadeEasyRecord
+		for i, r := range records.Data {
+			if r.Type == recordType && r.Name == domain.SubDomain {
+				find = &records.Data[i]
+				break
+			}
+		}
+
+		if find == nil {
+			dme.create(domain, zone.ID, recordType, ipAddr)
+			continue
+		}
+		if find.Value == ipAddr {
+			setUpdateResult(domain, ipAddr, resultUnchanged, nil)
+			continue
+		}
+		dme.modify(domain, zone.ID, *find, ipAddr)
+	}
+}
+
+// create 创建新的解析
+func (dme *DNSMadeEasy) create(domain *config.Domain, zoneID int, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
+	record := DNSMadeEasyRecord{
+		Name:        domain.SubDomain,
+		Type:        recordType,
+		Value:       ipAddr,
+		TTL:         dme.TTL,
+		GtdLocation: "DEFAULT",
+	}
+	err := dme.request(http.MethodPost, fmt.Sprintf("/dns/managed/%d/records", zoneID), record, nil)
+	if err != nil {
+		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// modify 更新解析
+func (dme *DNSMadeEasy) modify(domain *config.Domain, zoneID int, record DNSMadeEasyRecord, ipAddr string) {
+	record.Value = ipAddr
+	record.TTL = dme.TTL
+	if record.GtdLocation == "" {
+		record.GtdLocation = "DEFAULT"
+	}
+	err := dme.request(http.MethodPut, fmt.Sprintf("/dns/managed/%d/records/%d", zoneID, record.ID), record, nil)
+	if err != nil {
+		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// dnsMadeEasySign 签名, 为请求时间的 HMAC-SHA1, 请求时间为 HTTP 格式的 GMT 时间
+func dnsMadeEasySign(secret string, now time.Time) (requestDate string, signature string) {
+	requestDate = now.UTC().Format(http.TimeFormat)
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(requestDate))
+	return requestDate, hex.EncodeToString(mac.Sum(nil))
+}
+
+// request 统一请求接口
+func (dme *DNSMadeEasy) request(method string, path string, data interface{}, result interface{}) error {
+	var byt []byte
+	if data != nil {
+		byt, _ = json.Marshal(data)
+	}
+	req, err := http.NewRequest(method, dnsMadeEasyEndpoint+path, bytes.NewReader(byt))
+	if err != nil {
+		return err
+	}
+	requestDate, signature := dnsMadeEasySign(dme.DNS.Secret, time.Now())
+	req.Header.Set("x-dnsme-apiKey", dme.DNS.ID)
+	req.Header.Set("x-dnsme-requestDate", requestDate)
+	req.Header.Set("x-dnsme-hmac", signature)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	client := createHTTPClient(dme.DNS)
+	resp, err := client.Do(req)
+	return util.GetHTTPResponse(resp, err, result)
+}
@@ -0,0 +1,19 @@
+package dns
+
+import (
+	"testing"
+	"time"
+)
+
+// TestHMACSign 测试 DNS Made Easy 的签名
+func TestHMACSign(t *testing.T) {
+	now := time.Unix(1297544344, 0)
+
+	requestDate, signature := dnsMadeEasySign("secret", now)
+	if requestDate != "Sat, 12 Feb 2011 20:59:04 GMT" {
+		t.Errorf("期待请求时间为 Sat, 12 Feb 2011 20:59:04 GMT, 得到 %s", requestDate)
+	}
+	if signature != "219b05ff0cff3f5a862f109536b62663e33509c4" {
+		t.Errorf("DNS Made Easy 签名不正确: %s", signature)
+	}
+}
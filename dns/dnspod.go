@@ -1,7 +1,9 @@
 package dns
 
 import (
+	"encoding/json"
 	"net/url"
+	"strings"
 
 	"github.com/jeessy2/ddns-go/v6/config"
 	"github.com/jeessy2/ddns-go/v6/util"
@@ -28,6 +30,8 @@ type DnspodRecord struct {
 	Type    string
 	Value   string
 	Enabled string
+	// 未设置权重时为 null
+	Weight json.RawMessage
 }
 
 // DnspodRecordListResp recordListAPI结果
@@ -102,6 +106,9 @@ func (dnspod *Dnspod) addUpdateDomainRecords(recordType string) {
 
 // 创建
 func (dnspod *Dnspod) create(domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	params := domain.GetCustomParams()
 	params.Set("login_token", dnspod.DNS.ID+","+dnspod.DNS.Secret)
 	params.Set("domain", domain.DomainName)
@@ -110,6 +117,9 @@ func (dnspod *Dnspod) create(domain *config.Domain, recordType string, ipAddr st
 	params.Set("value", ipAddr)
 	params.Set("ttl", dnspod.TTL)
 	params.Set("format", "json")
+	if domain.Weight != "" {
+		params.Set("weight", domain.Weight)
+	}
 
 	if !params.Has("record_line") {
 		params.Set("record_line", "默认")
@@ -135,9 +145,9 @@ func (dnspod *Dnspod) create(domain *config.Domain, recordType string, ipAddr st
 // 修改
 func (dnspod *Dnspod) modify(record DnspodRecord, domain *config.Domain, recordType string, ipAddr string) {
 
-	// 相同不修改
-	if record.Value == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+	// IP及权重相同不修改
+	if record.Value == ipAddr && (domain.Weight == "" || strings.Trim(string(record.Weight), `"`) == domain.Weight) {
+		setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 		return
 	}
 
@@ -150,6 +160,9 @@ func (dnspod *Dnspod) modify(record DnspodRecord, domain *config.Domain, recordT
 	params.Set("ttl", dnspod.TTL)
 	params.Set("format", "json")
 	params.Set("record_id", record.ID)
+	if domain.Weight != "" {
+		params.Set("weight", domain.Weight)
+	}
 
 	if !params.Has("record_line") {
 		params.Set("record_line", "默认")
@@ -174,7 +187,7 @@ func (dnspod *Dnspod) modify(record DnspodRecord, domain *config.Domain, recordT
 
 // request sends a POST request to the given API with the given values.
 func (dnspod *Dnspod) request(apiAddr string, values url.Values) (status DnspodStatus, err error) {
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(dnspod.DNS)
 	resp, err := client.PostForm(
 		apiAddr,
 		values,
@@ -195,7 +208,7 @@ func (dnspod *Dnspod) getRecordList(domain *config.Domain, typ string) (result D
 	params.Set("sub_domain", domain.GetSubDomain())
 	params.Set("format", "json")
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(dnspod.DNS)
 	resp, err := client.PostForm(
 		recordListAPI,
 		params,
@@ -0,0 +1,169 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// DynDNS2 通用的 DynDNS2 协议, 兼容 No-IP、DynDNS、FreeDNS 等服务商
+type DynDNS2 struct {
+	DNS      config.DNS
+	Domains  config.Domains
+	lastIpv4 string
+	lastIpv6 string
+}
+
+// dynDNS2Errors DynDNS2 返回的错误码
+var dynDNS2Errors = map[string]string{
+	"badauth":  "用户名或密码错误",
+	"!donator": "该功能需要付费账户",
+	"notfqdn":  "主机名不是完整域名",
+	"nohost":   "主机名不存在",
+	"numhost":  "主机名数量过多",
+	"abuse":    "主机名因滥用已被封禁",
+	"badagent": "User-Agent 被拒绝",
+	"dnserr":   "服务商 DNS 错误",
+	"911":      "服务商维护中",
+}
+
+// Init 初始化
+func (dd *DynDNS2) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	dd.Domains.Ipv4Cache = ipv4cache
+	dd.Domains.Ipv6Cache = ipv6cache
+	dd.lastIpv4 = ipv4cache.Addr
+	dd.lastIpv6 = ipv6cache.Addr
+
+	dd.DNS = dnsConf.DNS
+	dd.Domains.GetNewIp(dnsConf)
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (dd *DynDNS2) AddUpdateDomainRecords() config.Domains {
+	dd.addUpdateDomainRecords("A")
+	dd.addUpdateDomainRecords("AAAA")
+	return dd.Domains
+}
+
+func (dd *DynDNS2) addUpdateDomainRecords(recordType string) {
+	ipAddr, domains := dd.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	// 无法查询记录, 重复提交相同的IP可能被服务商视为滥用
+	if recordType == "A" {
+		if dd.lastIpv4 == ipAddr {
+			util.Log("你的IPv4未变化, 未触发 %s 请求", "DynDNS2")
+			return
+		}
+	} else {
+		if dd.lastIpv6 == ipAddr {
+			util.Log("你的IPv6未变化, 未触发 %s 请求", "DynDNS2")
+			return
+		}
+	}
+
+	for _, domain := range domains {
+		dd.modify(domain, ipAddr)
+	}
+}
+
+// 修改
+func (dd *DynDNS2) modify(domain *config.Domain, ipAddr string) {
+	status, err := dd.request(domain, ipAddr)
+	if err != nil {
+		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	result, err := parseDynDNS2Status(status)
+	setUpdateResult(domain, ipAddr, result, err)
+}
+
+// parseDynDNS2Status 解析返回值, 如 good 1.2.3.4 / nochg 1.2.3.4 / badauth
+// nochg 表示IP未变化, 不计为已更新
+func parseDynDNS2Status(status string) (updateResult, error) {
+	code, _, _ := strings.Cut(status, " ")
+	switch code {
+	case "good":
+		return resultUpdated, nil
+	case "nochg":
+		return resultUnchanged, nil
+	}
+	if msg, ok := dynDNS2Errors[code]; ok {
+		status = fmt.Sprintf("%s (%s)", util.LogStr(msg), code)
+	}
+	return resultFailed, errors.New(status)
+}
+
+// request 统一请求接口
+// 更新地址包含 #{hostname} 或 #{ip} 时按模板替换, 以兼容参数名不同的服务商;
+// 否则追加标准的 hostname、myip 参数
+func (dd *DynDNS2) request(domain *config.Domain, ipAddr string) (status string, err error) {
+	endpoint := strings.TrimSpace(dd.DNS.Endpoint)
+	if endpoint == "" {
+		return "", fmt.Errorf(util.LogStr("未填写更新地址"))
+	}
+
+	var u *url.URL
+	if strings.Contains(endpoint, "#{hostname}") || strings.Contains(endpoint, "#{ip}") {
+		u, err = url.Parse(strings.NewReplacer(
+			"#{hostname}", url.QueryEscape(domain.String()),
+			"#{ip}", url.QueryEscape(ipAddr),
+		).Replace(endpoint))
+		if err != nil {
+			return
+		}
+	} else {
+		u, err = url.Parse(endpoint)
+		if err != nil {
+			return
+		}
+		query := u.Query()
+		query.Set("hostname", domain.String())
+		query.Set("myip", ipAddr)
+		u.RawQuery = query.Encode()
+	}
+
+	// 域名的自定义参数, 如 wildcard=ON
+	query := u.Query()
+	for k, v := range domain.GetCustomParams() {
+		for _, value := range v {
+			query.Add(k, value)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return
+	}
+	req.SetBasicAuth(dd.DNS.ID, dd.DNS.Secret)
+
+	client := createHTTPClient(dd.DNS)
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	status = strings.TrimSpace(string(data))
+	if resp.StatusCode >= 300 && status == "" {
+		status = resp.Status
+	}
+	return
+}
@@ -84,8 +84,7 @@ func (dynv6 *Dynv6) addUpdateDomainRecords(recordType string) {
 			// 如果使用的域名是主域名，对比DNS记录确定是否调用更新接口
 			if (recordType == "A" && findZone.Ipv4 == ipAddr) || (recordType == "AAAA" && findZone.Ipv6 == ipAddr) {
 				// ip与dns服务器一致，不执行更新
-				util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
-				domain.UpdateStatus = config.UpdatedNothing
+				setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 			} else {
 				dynv6.modifyMain(domain, zoneId, recordType, ipAddr)
 			}
@@ -113,8 +112,7 @@ func (dynv6 *Dynv6) addUpdateDomainRecords(recordType string) {
 				// 判断是否需要更新
 				if findRecord.Type == recordType && findRecord.Data == ipAddr {
 					// ip与dns服务器一致，不执行更新
-					util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
-					domain.UpdateStatus = config.UpdatedNothing
+					setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 				} else {
 					dynv6.modify(domain, zoneId, findRecord, recordType, ipAddr)
 				}
@@ -211,6 +209,9 @@ func (dynv6 *Dynv6) modifyMain(domain *config.Domain, zoneId string, recordType
 
 // create 创建新的解析
 func (dynv6 *Dynv6) create(domain *config.Domain, zoneId string, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	recordUpdateReq := Dynv6Record{
 		Name: domain.SubDomain,
 		Type: recordType,
@@ -266,7 +267,7 @@ func (dynv6 *Dynv6) request(method string, url string, data interface{}, result
 	req.Header.Add("Authorization", "Bearer "+dynv6.DNS.Secret)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(dynv6.DNS)
 	resp, err := client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 	return err
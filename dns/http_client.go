@@ -0,0 +1,165 @@
+package dns
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// maxDebugBodySize 调试日志中请求体/响应体的最大长度
+const maxDebugBodySize = 4096
+
+// maxRetryAfterWait 按 Retry-After 等待后立即重试的最长时间, 超过时在之后的运行中再请求
+const maxRetryAfterWait = 10 * time.Second
+
+// rateLimitKey 被限流的服务地址及账号, 同一服务地址的不同账号互不影响
+type rateLimitKey struct {
+	host    string
+	account string
+}
+
+// rateLimited 被限流的服务地址及账号可再次请求的时间
+var rateLimited = struct {
+	sync.Mutex
+	until map[rateLimitKey]time.Time
+}{until: map[rateLimitKey]time.Time{}}
+
+// rateLimitAccount 区分账号的标识, 使用凭证的哈希, 不保存凭证
+func rateLimitAccount(dnsConf config.DNS) string {
+	sum := sha256.Sum256([]byte(dnsConf.Name + "\x00" + dnsConf.ID + "\x00" + dnsConf.Secret))
+	return hex.EncodeToString(sum[:8])
+}
+
+// createHTTPClient 创建DNS服务商使用的HTTP客户端, 配置了代理时使用该代理
+func createHTTPClient(dnsConf config.DNS) *http.Client {
+	client, err := util.CreateHTTPClientWithProxy(dnsConf.UserAgent, dnsConf.Proxy)
+	if err != nil {
+		util.Log("%s, 将不使用代理", err)
+		client = util.CreateHTTPClientWithUserAgent(dnsConf.UserAgent)
+	}
+	client.Transport = &metricsTransport{base: client.Transport, provider: dnsConf.Name}
+	if util.IsDebug() {
+		client.Transport = &debugTransport{base: client.Transport}
+	}
+	// 重试的请求同样受频率限制
+	if bucket := getLimiter(dnsConf.Name); bucket != nil {
+		client.Transport = &rateLimitTransport{base: client.Transport, bucket: bucket}
+	}
+	client.Transport = &retryAfterTransport{base: client.Transport, account: rateLimitAccount(dnsConf)}
+	return client
+}
+
+// retryAfterTransport 服务商返回 429/503 及 Retry-After 时, 等待后重试或在之后的运行中再请求
+// 按服务地址及账号限流
+type retryAfterTransport struct {
+	base    http.RoundTripper
+	account string
+}
+
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	key := rateLimitKey{host: host, account: t.account}
+	rateLimited.Lock()
+	until := rateLimited.until[key]
+	rateLimited.Unlock()
+	if time.Now().Before(until) {
+		return nil, errors.New(util.LogStr("%s 已限流, 将在 %s 之后再请求", host, until.Format("2006-01-02 15:04:05")))
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return resp, err
+	}
+	delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if !ok {
+		return resp, nil
+	}
+
+	// 等待时间较短且请求体可重复读取时, 等待后重试一次
+	if delay <= maxRetryAfterWait && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil) {
+		util.Log("%s 返回 %d, 将在 %s 后重试", host, resp.StatusCode, delay)
+		resp.Body.Close()
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		retry := req.Clone(req.Context())
+		if req.GetBody != nil {
+			if retry.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		return t.base.RoundTrip(retry)
+	}
+
+	rateLimited.Lock()
+	rateLimited.until[key] = time.Now().Add(delay)
+	rateLimited.Unlock()
+	util.Log("%s 返回 %d, 将在 %s 后再请求", host, resp.StatusCode, delay)
+	return resp, nil
+}
+
+// parseRetryAfter 解析 Retry-After, 支持秒数及 HTTP 日期
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// debugTransport 输出请求及响应的调试日志, 日志中的密钥会被隐藏
+type debugTransport struct {
+	base http.RoundTripper
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		reqBody, _ = io.ReadAll(req.Body)
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	util.LogDebug("[调试] 请求: %s %s %s", req.Method, req.URL, truncateBody(reqBody))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		util.LogDebug("[调试] 请求 %s %s 失败: %s", req.Method, req.URL, err)
+		return resp, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	if err != nil {
+		return resp, err
+	}
+	util.LogDebug("[调试] 响应: %s %s %s", req.URL, resp.Status, truncateBody(respBody))
+	return resp, nil
+}
+
+// truncateBody 截断过长的内容
+func truncateBody(body []byte) string {
+	if len(body) > maxDebugBodySize {
+		return string(body[:maxDebugBodySize]) + "..."
+	}
+	return string(body)
+}
@@ -0,0 +1,147 @@
+package dns
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestCreateHTTPClientProxy 测试服务商使用该配置的代理
+func TestCreateHTTPClientProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 经过代理的请求为完整地址
+		proxied = r.URL.String()
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer proxy.Close()
+
+	dc := &config.DnsConfig{}
+	dc.DNS = config.DNS{Name: "cloudflare", Secret: "token", Proxy: proxy.URL}
+	var cache [2]util.IpCache
+	cf := &Cloudflare{}
+	cf.Init(dc, &cache[0], &cache[1])
+
+	var status CloudflareStatus
+	// 未使用代理时无法解析该域名
+	if err := cf.request("GET", "http://dns.internal.invalid/zones", nil, &status); err != nil {
+		t.Fatalf("请求失败: %s", err)
+	}
+	if proxied != "http://dns.internal.invalid/zones" {
+		t.Errorf("期待请求经过代理, 代理收到: %q", proxied)
+	}
+}
+
+// TestCreateHTTPClientInvalidProxy 测试代理地址不正确时不使用代理
+func TestCreateHTTPClientInvalidProxy(t *testing.T) {
+	if _, err := util.CreateHTTPClientWithProxy("", "ftp://127.0.0.1:21"); err == nil {
+		t.Errorf("期待代理地址不正确")
+	}
+	if client := createHTTPClient(config.DNS{Proxy: "ftp://127.0.0.1:21"}); client == nil {
+		t.Errorf("期待返回不使用代理的客户端")
+	}
+}
+
+// TestRetryAfter 测试按 Retry-After 重试及限流
+func TestRetryAfter(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path {
+		case "/retry":
+			// 第一次请求限流, 立即重试
+			if requests == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+		case "/limited":
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	client := createHTTPClient(config.DNS{})
+	resp, err := client.Post(server.URL+"/retry", "application/json", strings.NewReader(`{}`))
+	if err != nil || resp.StatusCode != http.StatusOK || requests != 2 {
+		t.Fatalf("期待重试后成功, 得到 %v, %v, 请求次数 %d", resp, err, requests)
+	}
+	resp.Body.Close()
+
+	resp, err = client.Get(server.URL + "/limited")
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("期待返回 429, 得到 %v, %v", resp, err)
+	}
+	resp.Body.Close()
+
+	// 限流期间不再请求
+	if _, err := client.Get(server.URL + "/other"); err == nil || requests != 3 {
+		t.Errorf("期待限流期间不再请求, 得到 %v, 请求次数 %d", err, requests)
+	}
+	rateLimited.Lock()
+	delete(rateLimited.until, rateLimitKey{strings.TrimPrefix(server.URL, "http://"), rateLimitAccount(config.DNS{})})
+	rateLimited.Unlock()
+}
+
+// TestRetryAfterAccounts 测试同一服务地址的一个账号被限流时, 其它账号仍可请求
+func TestRetryAfterAccounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "limited" {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	get := func(secret string) (*http.Response, error) {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		req.Header.Set("Authorization", secret)
+		return createHTTPClient(config.DNS{Name: "cloudflare", Secret: secret}).Do(req)
+	}
+	if resp, err := get("limited"); err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("期待返回 429, 得到 %v, %v", resp, err)
+	}
+	if _, err := get("limited"); err == nil {
+		t.Errorf("期待被限流的账号不再请求")
+	}
+	if resp, err := get("other"); err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("期待其它账号不受影响, 得到 %v, %v", resp, err)
+	}
+
+	rateLimited.Lock()
+	delete(rateLimited.until, rateLimitKey{strings.TrimPrefix(server.URL, "http://"), rateLimitAccount(config.DNS{Name: "cloudflare", Secret: "limited"})})
+	rateLimited.Unlock()
+}
+
+// TestParseRetryAfter 测试解析 Retry-After
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"30", 30 * time.Second, true},
+		{"-1", 0, false},
+		{"Mon, 01 Jan 2024 00:01:00 GMT", time.Minute, true},
+		{"Sun, 31 Dec 2023 23:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%q 期待 %s %v, 得到 %s %v", tt.value, tt.want, tt.ok, got, ok)
+		}
+	}
+}
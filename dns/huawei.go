@@ -39,13 +39,14 @@ type HuaweicloudRecordsResp struct {
 
 // HuaweicloudRecordsets 记录
 type HuaweicloudRecordsets struct {
-	ID      string
-	Name    string `json:"name"`
-	ZoneID  string `json:"zone_id"`
-	Status  string
-	Type    string   `json:"type"`
-	TTL     int      `json:"ttl"`
-	Records []string `json:"records"`
+	ID          string
+	Name        string `json:"name"`
+	ZoneID      string `json:"zone_id"`
+	Status      string
+	Type        string   `json:"type"`
+	TTL         int      `json:"ttl"`
+	Records     []string `json:"records"`
+	Description string   `json:"description,omitempty"`
 }
 
 // Init 初始化
@@ -101,7 +102,7 @@ func (hw *Huaweicloud) addUpdateDomainRecords(recordType string) {
 		find := false
 		for _, record := range records.Recordsets {
 			// 名称相同才更新。华为云默认是模糊搜索
-			if record.Name == domain.String()+"." {
+			if record.Name == domain.GetFQDN() {
 				// 更新
 				hw.modify(record, domain, ipAddr)
 				find = true
@@ -119,6 +120,9 @@ func (hw *Huaweicloud) addUpdateDomainRecords(recordType string) {
 
 // 创建
 func (hw *Huaweicloud) create(domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	zone, err := hw.getZones(domain)
 	if err != nil {
 		util.Log("查询域名信息发生异常! %s", err)
@@ -141,10 +145,11 @@ func (hw *Huaweicloud) create(domain *config.Domain, recordType string, ipAddr s
 	}
 
 	record := &HuaweicloudRecordsets{
-		Type:    recordType,
-		Name:    domain.String() + ".",
-		Records: []string{ipAddr},
-		TTL:     hw.TTL,
+		Type:        recordType,
+		Name:        domain.GetFQDN(),
+		Records:     []string{ipAddr},
+		TTL:         hw.TTL,
+		Description: domain.Comment,
 	}
 	var result HuaweicloudRecordsets
 	err = hw.request(
@@ -174,13 +179,16 @@ func (hw *Huaweicloud) modify(record HuaweicloudRecordsets, domain *config.Domai
 
 	// 相同不修改
 	if len(record.Records) > 0 && record.Records[0] == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 		return
 	}
 
 	var request map[string]interface{} = make(map[string]interface{})
 	request["records"] = []string{ipAddr}
 	request["ttl"] = hw.TTL
+	if domain.Comment != "" {
+		request["description"] = domain.Comment
+	}
 
 	var result HuaweicloudRecordsets
 
@@ -243,7 +251,7 @@ func (hw *Huaweicloud) request(method string, url string, data interface{}, resu
 
 	req.Header.Add("content-type", "application/json")
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(hw.DNS)
 	resp, err := client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
@@ -1,6 +1,8 @@
 package dns
 
 import (
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jeessy2/ddns-go/v6/config"
@@ -14,6 +16,24 @@ type DNS interface {
 	AddUpdateDomainRecords() (domains config.Domains)
 }
 
+// PTRUpdater 支持更新PTR记录的DNS服务商
+type PTRUpdater interface {
+	// 将IP的反向解析指向域名
+	UpdatePTR(domain *config.Domain, ipAddr string) error
+}
+
+// RecordDeleter 支持删除记录的DNS服务商
+type RecordDeleter interface {
+	// 删除域名的记录, 返回删除的数量
+	DeleteRecords(domain *config.Domain, recordType string) (int, error)
+}
+
+// StaleRecordDeleter 支持托管区域的DNS服务商
+type StaleRecordDeleter interface {
+	// 删除由 ddns-go 创建但已不在 domains 中的记录
+	DeleteStaleRecords(domains *config.Domains)
+}
+
 var (
 	Addresses = []string{
 		alidnsEndpoint,
@@ -27,80 +47,540 @@ var (
 		tencentCloudEndPoint,
 		dynadotEndpoint,
 		dynv6Endpoint,
+		mythicBeastsEndpoint,
+		loopiaEndpoint,
+		dnsExitEndpoint,
+		spaceshipEndpoint,
+		njallaEndpoint,
+		dnsMadeEasyEndpoint,
+		constellixEndpoint,
+		zoneEditEndpoint,
 	}
 
 	Ipcache = [][2]util.IpCache{}
+
+	// noIPHandled 本次未获取到IP是否已处理, 获取到IP后重置
+	noIPHandled = [][2]bool{}
+
+	// internalIpcache 内网配置的IP缓存
+	internalIpcache   = map[[2]int]*[2]util.IpCache{}
+	internalIpcacheMu sync.Mutex
+
+	// runMu 同时只运行一次更新, 以免并发修改缓存等全局状态
+	runMu sync.Mutex
+	// runPending 运行中又触发了更新, 运行结束后再更新一次全部配置
+	runPending atomic.Bool
 )
 
+// detectedIP 获取到的IP及结果来源
+type detectedIP struct {
+	addr, provenance string
+}
+
+// detected 每个配置最近一次获取到的IPv4/IPv6, 下标与配置相同
+// 更新时 Ipcache 会被修改, 状态等接口只读取每个配置更新完成后的副本
+var detected struct {
+	sync.RWMutex
+	ips [][2]detectedIP
+}
+
+// publishDetected 记录第 i 个配置更新完成后的IP, n 为配置数量, 数量变化时清空
+func publishDetected(n int, i int, cache *[2]util.IpCache) {
+	detected.Lock()
+	defer detected.Unlock()
+	if len(detected.ips) != n {
+		detected.ips = make([][2]detectedIP, n)
+	}
+	if i >= 0 && i < n {
+		detected.ips[i] = [2]detectedIP{{cache[0].Addr, cache[0].Provenance}, {cache[1].Addr, cache[1].Provenance}}
+	}
+}
+
 // RunTimer 定时运行
 func RunTimer(delay time.Duration) {
-	for {
+	if conf, err := config.GetConfigCached(); err == nil {
+		logIntervals(&conf, delay)
+	}
+	// 开启首次试运行时, 定时更新的首次运行只输出将要进行的更新, -once、API及保存时的更新不受影响
+	if conf, err := config.GetConfigCached(); err == nil && conf.FirstCycleDryRun && !conf.Paused && !conf.InMaintenanceWindow(time.Now()) {
+		dryRun(&conf)
+	} else {
 		RunOnce()
-		time.Sleep(delay)
 	}
+	// 首次运行后发送启动通知, 以便包含获取到的IP
+	if conf, err := config.GetConfigCached(); err == nil {
+		ipv4Addr, ipv6Addr := LastIpAddr()
+		config.ExecLifecycleWebhook(&conf, config.Started, ipv4Addr, ipv6Addr)
+	}
+	runLoop(delay)
+}
+
+// ExecShutdownWebhook 发送停止通知
+func ExecShutdownWebhook() {
+	if conf, err := config.GetConfigCached(); err == nil {
+		ipv4Addr, ipv6Addr := LastIpAddr()
+		config.ExecLifecycleWebhook(&conf, config.Stopped, ipv4Addr, ipv6Addr)
+	}
+}
+
+// LastIpAddr 获得最近一次获取到的IPv4/IPv6地址
+func LastIpAddr() (ipv4Addr string, ipv6Addr string) {
+	detected.RLock()
+	defer detected.RUnlock()
+	for _, c := range detected.ips {
+		if ipv4Addr == "" {
+			ipv4Addr = c[0].addr
+		}
+		if ipv6Addr == "" {
+			ipv6Addr = c[1].addr
+		}
+	}
+	return
+}
+
+// DetectedIpAddr 获得第 i 个配置最近一次获取到的IPv4/IPv6地址
+func DetectedIpAddr(i int) (ipv4Addr string, ipv6Addr string) {
+	detected.RLock()
+	defer detected.RUnlock()
+	if i >= 0 && i < len(detected.ips) {
+		return detected.ips[i][0].addr, detected.ips[i][1].addr
+	}
+	return
+}
+
+// DetectedIpProvenance 获得第 i 个配置最近一次 IPv4/IPv6 结果的来源
+func DetectedIpProvenance(i int) (ipv4 string, ipv6 string) {
+	detected.RLock()
+	defer detected.RUnlock()
+	if i >= 0 && i < len(detected.ips) {
+		return detected.ips[i][0].provenance, detected.ips[i][1].provenance
+	}
+	return
+}
+
+// updatePTR 更新成功且开启了PTR的域名, 同时更新反向解析
+func updatePTR(dnsSelected DNS, dnsName string, domains *config.Domains) {
+	update := func(ipAddr string, domainArr []*config.Domain) {
+		for _, domain := range domainArr {
+			if !domain.UpdatePTR || domain.UpdateStatus != config.UpdatedSuccess {
+				continue
+			}
+			ptrUpdater, ok := dnsSelected.(PTRUpdater)
+			if !ok {
+				util.Log("更新PTR记录 %s 失败! 异常信息: %s", domain, util.LogStr("%s 不支持更新PTR记录", dnsName))
+				continue
+			}
+			if err := ptrUpdater.UpdatePTR(domain, ipAddr); err != nil {
+				util.Log("更新PTR记录 %s 失败! 异常信息: %s", domain, err)
+				continue
+			}
+			util.Log("更新PTR记录 %s 成功! IP: %s", domain, ipAddr)
+		}
+	}
+	update(domains.Ipv4Addr, domains.Ipv4Domains)
+	update(domains.Ipv6Addr, domains.Ipv6Domains)
+}
+
+// handleNoIP 未获取到IP时按配置的方式处理, 默认保留记录
+func handleNoIP(dnsSelected DNS, dc *config.DnsConfig, domains *config.Domains, conf *config.Config, handled *[2]bool) {
+	handle := func(enable bool, recordType string, ipAddr string, domainArr []*config.Domain, cache *util.IpCache, handled *bool) {
+		if !enable || len(domainArr) == 0 {
+			return
+		}
+		if ipAddr != "" {
+			*handled = false
+			return
+		}
+		if *handled {
+			return
+		}
+
+		switch dc.NoIPAction {
+		case config.NoIPClear:
+			// 连续3次未获取到IP才删除, 防止偶尔的网络连接失败
+			if cache.TimesFailedIP < 3 {
+				return
+			}
+			*handled = true
+			deleter, ok := dnsSelected.(RecordDeleter)
+			if !ok {
+				util.Log("%s 不支持删除记录", dc.DNS.Name)
+				return
+			}
+			for _, domain := range domainArr {
+				count, err := deleter.DeleteRecords(domain, recordType)
+				if err != nil {
+					util.Log("删除域名解析 %s 失败! 异常信息: %s", domain, err)
+					continue
+				}
+				// 记录已删除, IP恢复后即使与之前相同也需重新创建
+				delete(cache.Written, domain.String())
+				if count > 0 {
+					util.Log("未获取到IP, 已删除域名解析 %s(%s)", domain, recordType)
+				}
+			}
+		case config.NoIPNotify:
+			*handled = true
+			config.ExecNoIPWebhook(domains, conf, recordType)
+		}
+	}
+	handle(dc.Ipv4.Enable, "A", domains.Ipv4Addr, domains.Ipv4Domains, domains.Ipv4Cache, &handled[0])
+	handle(dc.Ipv6.Enable, "AAAA", domains.Ipv6Addr, domains.Ipv6Domains, domains.Ipv6Cache, &handled[1])
+}
+
+// newDNS 根据服务商名称创建新的实例
+// 每个配置使用独立的实例, 相同服务商的多个账号不会共用凭证和状态
+func newDNS(name string) DNS {
+	switch name {
+	case "alidns":
+		return &Alidns{}
+	case "tencentcloud":
+		return &TencentCloud{}
+	case "trafficroute":
+		return &TrafficRoute{}
+	case "dnspod":
+		return &Dnspod{}
+	case "cloudflare":
+		return &Cloudflare{}
+	case "huaweicloud":
+		return &Huaweicloud{}
+	case "callback":
+		return &Callback{}
+	case "baiducloud":
+		return &BaiduCloud{}
+	case "porkbun":
+		return &Porkbun{}
+	case "godaddy":
+		return &GoDaddyDNS{}
+	case "namecheap":
+		return &NameCheap{}
+	case "namesilo":
+		return &NameSilo{}
+	case "vercel":
+		return &Vercel{}
+	case "dynadot":
+		return &Dynadot{}
+	case "dynv6":
+		return &Dynv6{}
+	case "dyndns2":
+		return &DynDNS2{}
+	case "mythicbeasts":
+		return &MythicBeasts{}
+	case "loopia":
+		return &Loopia{}
+	case "dnsexit":
+		return &DNSExit{}
+	case "spaceship":
+		return &Spaceship{}
+	case "njalla":
+		return &Njalla{}
+	case "dnsmadeeasy":
+		return &DNSMadeEasy{}
+	case "constellix":
+		return &Constellix{}
+	case "zoneedit":
+		return &ZoneEdit{}
+	default:
+		return &Alidns{}
+	}
+}
+
+// detectInMaintenance 维护时段内只获取IP不更新, 结束后检测到变化时正常更新
+func detectInMaintenance(conf *config.Config) {
+	for _, dc := range conf.DnsConf {
+		ipv4Addr, ipv6Addr := "", ""
+		ipv4Domains, ipv6Domains := dc.ParseDomains()
+		if dc.Ipv4.Enable && len(ipv4Domains) > 0 {
+			ipv4Addr = dc.GetIpv4Addr()
+		}
+		if dc.Ipv6.Enable && len(ipv6Domains) > 0 {
+			ipv6Addr = dc.GetIpv6Addr()
+		}
+		util.Log("维护时段 %s 内, 跳过更新. IPv4: %s, IPv6: %s", conf.MaintenanceWindow, ipv4Addr, ipv6Addr)
+	}
+}
+
+// getInternalIpcache 获得第 i 个配置拆分出的第 j 个内网配置的IP缓存
+func getInternalIpcache(i int, j int) *[2]util.IpCache {
+	internalIpcacheMu.Lock()
+	defer internalIpcacheMu.Unlock()
+	cache, ok := internalIpcache[[2]int{i, j}]
+	if !ok {
+		cache = &[2]util.IpCache{}
+		internalIpcache[[2]int{i, j}] = cache
+	}
+	return cache
+}
+
+// runDnsConf 更新单个配置, handled 为 nil 时不处理未获取到IP的情况
+func runDnsConf(dc *config.DnsConfig, conf *config.Config, cache *[2]util.IpCache, handled *[2]bool) (dnsSelected DNS, domains config.Domains) {
+	// 每个配置可单独设置缓存次数
+	cache[0].CustomTimes = dc.CacheTimes
+	cache[1].CustomTimes = dc.CacheTimes
+	// IP需连续相同N次后才更新
+	cache[0].StableTimes = dc.StableTimes
+	cache[1].StableTimes = dc.StableTimes
+
+	dnsSelected = newDNS(dc.DNS.Name)
+	dnsSelected.Init(dc, &cache[0], &cache[1])
+	domains = dnsSelected.AddUpdateDomainRecords()
+	observeDomains(dc.DNS.Name, &domains)
+	domains.RecordWritten()
+	updatePTR(dnsSelected, dc.DNS.Name, &domains)
+	verifyPropagation(dc, &domains)
+	if handled != nil {
+		handleNoIP(dnsSelected, dc, &domains, conf, handled)
+	}
+	// webhook
+	config.ExecDomainWebhook(&domains)
+	v4Status, v6Status := config.ExecWebhook(&domains, conf)
+	// IPv4/IPv6 相互独立, 只重置失败的cache, 保留结果来源用于诊断, 保留已更新的域名以便只重试失败的域名
+	if v4Status == config.UpdatedFailed {
+		cache[0] = util.IpCache{Provenance: cache[0].Provenance, Written: cache[0].Written}
+	}
+	if v6Status == config.UpdatedFailed {
+		cache[1] = util.IpCache{Provenance: cache[1].Provenance, Written: cache[1].Written}
+	}
+	return
 }
 
-// RunOnce RunOnce
+// runDnsConfIndex 更新第 i 个配置, 包括拆分出的内网配置, 更新结果由 add 汇总
+// force 为 true 时不使用缓存, 与DNS服务商比对, partial 为 true 时只更新了部分域名
+func runDnsConfIndex(i int, dc *config.DnsConfig, conf *config.Config, force bool, partial bool, add func(domains *config.Domains)) {
+	// 内外网分别解析时拆分为多个配置, 第一个为外网配置
+	var dnsSelected DNS
+	for j, c := range dc.SplitHorizon() {
+		if (j > 0 || partial) && len(c.Ipv4.Domains) == 0 && len(c.Ipv6.Domains) == 0 {
+			continue
+		}
+		cache, handled := &Ipcache[i], &noIPHandled[i]
+		if j > 0 {
+			cache, handled = getInternalIpcache(i, j), nil
+		}
+		if force {
+			// 与达到缓存次数相同, 保留地址及已更新的域名
+			cache[0].Times, cache[1].Times = 0, 0
+			cache[0].DomainTimes, cache[1].DomainTimes = nil, nil
+		}
+		selected, domains := runDnsConf(&c, conf, cache, handled)
+		if j == 0 {
+			dnsSelected = selected
+		}
+		if partial {
+			// 其他域名未与DNS服务商比对, 下次运行时不使用缓存
+			cache[0].Times, cache[1].Times = 0, 0
+		}
+		add(&domains)
+	}
+
+	if dc.ManagedZone && !partial {
+		if deleter, ok := dnsSelected.(StaleRecordDeleter); ok {
+			// 保留全部配置中的域名, 以免多个配置共用区域时相互删除
+			keep := configuredDomains(conf)
+			deleter.DeleteStaleRecords(&keep)
+		} else {
+			util.Log("%s 不支持托管区域", dc.DNS.Name)
+		}
+	}
+}
+
+// dryRun 试运行, 只获取IP并输出将要进行的更新
+func dryRun(conf *config.Config) {
+	defer markDone()
+	start := time.Now()
+	util.Log("首次运行为试运行, 只获取IP并输出将要进行的更新, 下次运行时开始更新")
+	for _, dc := range conf.DnsConf {
+		for _, c := range dc.SplitHorizon() {
+			c.LogDryRun()
+		}
+	}
+	setLastRun(start, ResultDryRun)
+}
+
+// configuredDomains 全部配置中的域名, 包括拆分出的内网配置
+func configuredDomains(conf *config.Config) (domains config.Domains) {
+	for _, dc := range conf.DnsConf {
+		for _, c := range dc.SplitHorizon() {
+			ipv4Domains, ipv6Domains := c.ParseDomains()
+			domains.Ipv4Domains = append(domains.Ipv4Domains, ipv4Domains...)
+			domains.Ipv6Domains = append(domains.Ipv6Domains, ipv6Domains...)
+		}
+	}
+	return
+}
+
+// RunOnce 更新全部配置, 并重新计算定时更新的下次运行时间
 func RunOnce() {
+	runConfigs(nil, false, "")
+	reschedule()
+}
+
+// reschedule 通知定时更新重新计算下次运行时间
+func reschedule() {
+	select {
+	case rescheduleCh <- struct{}{}:
+	default:
+	}
+}
+
+// DomainResult 单个域名的更新结果
+type DomainResult struct {
+	Domain     string `json:"domain"`
+	RecordType string `json:"recordType"`
+	IP         string `json:"ip"`
+	// updated、unchanged、failed, 未获取到IP、等待IP稳定等没有结果时为 skipped
+	Result string `json:"result"`
+}
+
+// RunIndex 只更新第 i 个配置, 不使用缓存, 与DNS服务商比对, 返回本次运行的结果及每个域名的结果
+// 配置不存在时返回 false, 重置缓存(如刚保存配置)时与定时更新相同, 同时更新全部配置
+func RunIndex(i int) (result string, domains []DomainResult, ok bool) {
+	conf, err := config.GetConfigCached()
+	if err != nil || i < 0 || i >= len(conf.DnsConf) {
+		return "", nil, false
+	}
+	due := make([]bool, len(conf.DnsConf))
+	due[i] = true
+	results, ran := runConfigs(due, true, "")
+	reschedule()
+	if !ran {
+		return ResultQueued, nil, true
+	}
+
+	add := func(recordType string, ipAddr string, domainArr []*config.Domain) {
+		for _, domain := range domainArr {
+			r := domainResultName(domain)
+			if r == "" {
+				r = "skipped"
+			}
+			domains = append(domains, DomainResult{Domain: domain.String(), RecordType: recordType, IP: ipAddr, Result: r})
+		}
+	}
+	if i < len(results) {
+		for _, d := range results[i] {
+			add("A", d.Ipv4Addr, d.Ipv4Domains)
+			add("AAAA", d.Ipv6Addr, d.Ipv6Domains)
+		}
+	}
+	return GetStatus().LastResult, domains, true
+}
+
+// RunTag 只更新带有标签 tag 的域名, 与DNS服务商比对, 与定时更新共用锁及IP缓存
+// 只更新部分域名, 因此不删除托管区域中的记录, 也不影响定时更新的下次运行时间
+func RunTag(tag string) {
 	conf, err := config.GetConfigCached()
 	if err != nil {
 		return
 	}
+	due := make([]bool, len(conf.DnsConf))
+	found := false
+	for i, dc := range conf.DnsConf {
+		due[i] = dc.FilterTag(tag)
+		found = found || due[i]
+	}
+	if !found {
+		util.Log("没有带有标签 %s 的域名", tag)
+		return
+	}
+	runConfigs(due, true, tag)
+}
+
+// runConfigs 同时只运行一次 updateConfigs, 上一次更新尚未完成时返回 false, 并在其完成后更新全部配置
+func runConfigs(due []bool, force bool, tag string) (results [][]*config.Domains, ran bool) {
+	if runMu.TryLock() {
+		results, ran = updateConfigs(due, force, tag), true
+		runMu.Unlock()
+	} else {
+		util.Log("上一次更新尚未完成, 将在完成后更新")
+		runPending.Store(true)
+	}
+	// 解锁后再检查, 以免遗漏运行结束前触发的更新
+	for runPending.Load() && runMu.TryLock() {
+		if runPending.Swap(false) {
+			updateConfigs(nil, false, "")
+		}
+		runMu.Unlock()
+	}
+	return
+}
+
+// updateConfigs 更新 due 中为 true 的配置, due 为 nil 时更新全部配置, force 为 true 时不使用缓存
+// tag 不为空时只更新带有该标签的域名
+// 返回每个配置的更新结果, 下标与配置相同, 暂停等未更新时为 nil
+func updateConfigs(due []bool, force bool, tag string) (results [][]*config.Domains) {
+	// 供看门狗判断更新是否停滞
+	defer markDone()
+	start := time.Now()
+	conf, err := config.GetConfigCached()
+	if err != nil {
+		return
+	}
+	// 重置缓存时更新全部配置, 只更新带有标签的域名时不影响下次运行时间
+	if tag == "" {
+		if util.ForceCompareGlobal || len(Ipcache) != len(conf.DnsConf) {
+			due = nil
+		}
+		due = markRun(len(conf.DnsConf), due, start)
+	}
+	if conf.Paused {
+		util.Log("已暂停所有更新, 跳过本次更新")
+		setLastRun(start, ResultPaused)
+		return
+	}
+	if conf.InMaintenanceWindow(start) {
+		detectInMaintenance(&conf)
+		setLastRun(start, ResultMaintenance)
+		return
+	}
 	if util.ForceCompareGlobal || len(Ipcache) != len(conf.DnsConf) {
 		Ipcache = [][2]util.IpCache{}
+		noIPHandled = [][2]bool{}
 		for range conf.DnsConf {
 			Ipcache = append(Ipcache, [2]util.IpCache{{}, {}})
+			noIPHandled = append(noIPHandled, [2]bool{})
 		}
+		internalIpcache = map[[2]int]*[2]util.IpCache{}
+		publishDetected(0, -1, nil)
 	}
 
+	// 获取IP方式相同的配置只获取一次IP
+	config.BeginSharedDetect()
+	defer config.EndSharedDetect()
+
+	// 最多同时更新 concurrency 个配置, 为1时按顺序更新
+	var summary cycleSummary
+	var mu sync.Mutex
+	results = make([][]*config.Domains, len(conf.DnsConf))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
 	for i, dc := range conf.DnsConf {
-		var dnsSelected DNS
-		switch dc.DNS.Name {
-		case "alidns":
-			dnsSelected = &Alidns{}
-		case "tencentcloud":
-			dnsSelected = &TencentCloud{}
-		case "trafficroute":
-			dnsSelected = &TrafficRoute{}
-		case "dnspod":
-			dnsSelected = &Dnspod{}
-		case "cloudflare":
-			dnsSelected = &Cloudflare{}
-		case "huaweicloud":
-			dnsSelected = &Huaweicloud{}
-		case "callback":
-			dnsSelected = &Callback{}
-		case "baiducloud":
-			dnsSelected = &BaiduCloud{}
-		case "porkbun":
-			dnsSelected = &Porkbun{}
-		case "godaddy":
-			dnsSelected = &GoDaddyDNS{}
-		case "namecheap":
-			dnsSelected = &NameCheap{}
-		case "namesilo":
-			dnsSelected = &NameSilo{}
-		case "vercel":
-			dnsSelected = &Vercel{}
-		case "dynadot":
-			dnsSelected = &Dynadot{}
-		case "dynv6":
-			dnsSelected = &Dynv6{}
-		default:
-			dnsSelected = &Alidns{}
-		}
-		dnsSelected.Init(&dc, &Ipcache[i][0], &Ipcache[i][1])
-		domains := dnsSelected.AddUpdateDomainRecords()
-		// webhook
-		v4Status, v6Status := config.ExecWebhook(&domains, &conf)
-		// 重置单个cache
-		if v4Status == config.UpdatedFailed {
-			Ipcache[i][0] = util.IpCache{}
-		}
-		if v6Status == config.UpdatedFailed {
-			Ipcache[i][1] = util.IpCache{}
-		}
-	}
-
-	util.ForceCompareGlobal = false
+		if due != nil && !due[i] {
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if tag != "" {
+				dc.FilterTag(tag)
+			}
+			runDnsConfIndex(i, &dc, &conf, force, tag != "", func(domains *config.Domains) {
+				mu.Lock()
+				defer mu.Unlock()
+				summary.add(domains)
+				results[i] = append(results[i], domains)
+			})
+			publishDetected(len(conf.DnsConf), i, &Ipcache[i])
+		}()
+	}
+	wg.Wait()
+
+	summary.log()
+	// 只更新了部分域名时, 仍在下次定时更新时重置缓存
+	if tag == "" {
+		util.ForceCompareGlobal = false
+	}
+	setLastRunSummary(start, &summary)
+	return
 }
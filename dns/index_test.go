@@ -0,0 +1,176 @@
+package dns
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestNewDNSMultipleAccounts 测试相同服务商的两个账号: 限流互不影响, 频率限制按服务商共用
+func TestNewDNSMultipleAccounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "Bearer token-account-1" && r.URL.Path == "/limited" {
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"success":true}`))
+	}))
+	defer server.Close()
+
+	confs := []config.DnsConfig{{}, {}}
+	confs[0].DNS = config.DNS{Name: "cloudflare", Secret: "token-account-1"}
+	confs[1].DNS = config.DNS{Name: "cloudflare", Secret: "token-account-2"}
+	caches := [][2]util.IpCache{{}, {}}
+
+	var providers []*Cloudflare
+	for i := range confs {
+		dnsSelected := newDNS(confs[i].DNS.Name)
+		dnsSelected.Init(&confs[i], &caches[i][0], &caches[i][1])
+		cf, ok := dnsSelected.(*Cloudflare)
+		if !ok {
+			t.Fatalf("期待 *Cloudflare, 得到 %T", dnsSelected)
+		}
+		providers = append(providers, cf)
+	}
+	defer func() {
+		rateLimited.Lock()
+		delete(rateLimited.until, rateLimitKey{strings.TrimPrefix(server.URL, "http://"), rateLimitAccount(confs[0].DNS)})
+		rateLimited.Unlock()
+	}()
+
+	// 第一个账号被限流后不再请求, 第二个账号不受影响
+	var status CloudflareStatus
+	providers[0].request("GET", server.URL+"/limited", nil, &status)
+	if err := providers[0].request("GET", server.URL, nil, &status); err == nil {
+		t.Errorf("期待第一个账号被限流")
+	}
+	if err := providers[1].request("GET", server.URL, nil, &status); err != nil || !status.Success {
+		t.Errorf("期待第二个账号不受限流影响, 得到 %v", err)
+	}
+
+	// 频率限制按服务商共用, 第一个账号用完令牌后第二个账号需等待
+	bucket := getLimiter("cloudflare")
+	if bucket == nil {
+		t.Fatalf("期待 Cloudflare 有频率限制")
+	}
+	for bucket.reserve(time.Now()) <= 0 {
+	}
+	start := time.Now()
+	if err := providers[1].request("GET", server.URL, nil, &status); err != nil {
+		t.Fatalf("请求失败: %s", err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second/time.Duration(providerRateLimits["cloudflare"]) {
+		t.Errorf("期待两个账号共用频率限制, 等待了 %s", elapsed)
+	}
+}
+
+// TestRunConfigsOverlap 测试上一次更新尚未完成时不同时更新, 并在完成后再次更新
+func TestRunConfigsOverlap(t *testing.T) {
+	runMu.Lock()
+	if _, ran := runConfigs(nil, false, ""); ran || !runPending.Load() {
+		t.Errorf("期待运行中不同时更新且等待完成后更新, 得到 %v %v", ran, runPending.Load())
+	}
+	runMu.Unlock()
+
+	if _, ran := runConfigs(nil, false, ""); !ran || runPending.Load() {
+		t.Errorf("期待更新并处理等待中的更新, 得到 %v %v", ran, runPending.Load())
+	}
+}
+
+// TestDetectedIpAddrConcurrent 测试同时更新多个配置时读取获取到的IP, 需使用 -race 运行
+func TestDetectedIpAddrConcurrent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	t.Setenv(util.ConfigFilePathENV, t.TempDir()+"/ddns_go_config.yaml")
+	conf := config.Config{}
+	for range 4 {
+		dc := config.DnsConfig{}
+		dc.DNS = config.DNS{Name: "callback", ID: server.URL + "?ip=#{ip}"}
+		dc.Ipv4.Enable = true
+		dc.Ipv4.GetType = "cmd"
+		dc.Ipv4.Cmd = "echo 192.0.2.1"
+		dc.Ipv4.Domains = []string{"www.example.com"}
+		conf.DnsConf = append(conf.DnsConf, dc)
+	}
+	if err := conf.SaveConfig(); err != nil {
+		t.Fatalf("保存失败: %s", err)
+	}
+	defer (&config.Config{}).SaveConfig()
+	SetConcurrency(2)
+	defer SetConcurrency(1)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 3 {
+			util.ForceCompareGlobal = true
+			updateConfigs(nil, false, "")
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			if ipv4, _ := DetectedIpAddr(3); ipv4 != "192.0.2.1" {
+				t.Errorf("期待 192.0.2.1, 得到 %s", ipv4)
+			}
+			return
+		default:
+			for i := range 5 {
+				DetectedIpAddr(i)
+				DetectedIpProvenance(i)
+			}
+			LastIpAddr()
+		}
+	}
+}
+
+// fakeDeleter 记录删除的域名
+type fakeDeleter struct {
+	deleted []string
+}
+
+func (f *fakeDeleter) Init(*config.DnsConfig, *util.IpCache, *util.IpCache) {}
+
+func (f *fakeDeleter) AddUpdateDomainRecords() config.Domains { return config.Domains{} }
+
+func (f *fakeDeleter) DeleteRecords(domain *config.Domain, recordType string) (int, error) {
+	f.deleted = append(f.deleted, domain.String())
+	return 1, nil
+}
+
+// TestHandleNoIPClearWritten 测试未获取到IP删除记录后, IP恢复为之前的IP时只更新变化的域名仍会重新创建记录
+func TestHandleNoIPClearWritten(t *testing.T) {
+	dc := &config.DnsConfig{NoIPAction: config.NoIPClear, OnlyChanged: true}
+	dc.Ipv4.Enable = true
+	dc.Ipv4.Domains = []string{"www.example.com"}
+	cache := &util.IpCache{Written: map[string]string{"www.example.com": "192.0.2.1"}, TimesFailedIP: 3}
+
+	// 连续未获取到IP, 删除记录
+	domains := config.Domains{Ipv4Cache: cache, Ipv6Cache: &util.IpCache{}, OnlyChanged: true}
+	domains.Ipv4Domains, domains.Ipv6Domains = dc.ParseDomains()
+	deleter := &fakeDeleter{}
+	handleNoIP(deleter, dc, &domains, &config.Config{}, &[2]bool{})
+	if len(deleter.deleted) != 1 {
+		t.Fatalf("期待删除 www.example.com, 得到 %v", deleter.deleted)
+	}
+	if _, ok := cache.Written["www.example.com"]; ok {
+		t.Error("期待删除记录后清除已更新的IP")
+	}
+
+	// 失败后重置缓存, IP恢复为之前的IP
+	*cache = util.IpCache{Written: cache.Written}
+	domains.Ipv4Addr = "192.0.2.1"
+	if ipAddr, list := domains.GetNewIpResult("A"); ipAddr != "192.0.2.1" || len(list) != 1 {
+		t.Errorf("期待重新创建 www.example.com, 得到 %q %v", ipAddr, list)
+	}
+}
@@ -0,0 +1,307 @@
+package dns
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const (
+	loopiaEndpoint = "https://api.loopia.se/RPCSERV"
+	// loopiaOK Loopia 接口成功时的返回值, 失败时如 AUTH_ERROR、BAD_INDATA、RATE_LIMITED
+	loopiaOK = "OK"
+)
+
+// Loopia Loopia XML-RPC API
+// https://www.loopia.com/api/
+type Loopia struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+}
+
+// LoopiaRecord 解析记录
+type LoopiaRecord struct {
+	Type     string
+	TTL      int
+	Priority int
+	Rdata    string
+	RecordID int
+}
+
+// Init 初始化
+func (loopia *Loopia) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	loopia.Domains.Ipv4Cache = ipv4cache
+	loopia.Domains.Ipv6Cache = ipv6cache
+	loopia.DNS = dnsConf.DNS
+	loopia.Domains.GetNewIp(dnsConf)
+	if dnsConf.TTL == "" {
+		// 默认600s
+		loopia.TTL = 600
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			loopia.TTL = 600
+		} else {
+			loopia.TTL = ttl
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (loopia *Loopia) AddUpdateDomainRecords() config.Domains {
+	loopia.addUpdateDomainRecords("A")
+	loopia.addUpdateDomainRecords("AAAA")
+	return loopia.Domains
+}
+
+func (loopia *Loopia) addUpdateDomainRecords(recordType string) {
+	ipAddr, domains := loopia.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	for _, domain := range domains {
+		records, err := loopia.getZoneRecords(domain)
+		if err != nil {
+			util.Log("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		var find *LoopiaRecord
+		for i := range records {
+			if records[i].Type == recordType {
+				find = &records[i]
+				break
+			}
+		}
+
+		if find == nil {
+			// 子域名没有任何记录时可能不存在, 先添加子域名
+			loopia.create(domain, recordType, ipAddr, len(records) == 0)
+			continue
+		}
+		if find.Rdata == ipAddr {
+			setUpdateResult(domain, ipAddr, resultUnchanged, nil)
+			continue
+		}
+		loopia.modify(domain, *find, ipAddr)
+	}
+}
+
+// create 创建新的解析
+func (loopia *Loopia) create(domain *config.Domain, recordType string, ipAddr string, addSubdomain bool) {
+	if skipCreate(domain) {
+		return
+	}
+	var err error
+	if addSubdomain && domain.SubDomain != "" {
+		err = loopia.callOK("addSubdomain", domain.DomainName, domain.GetSubDomain())
+	}
+	if err == nil {
+		record := LoopiaRecord{Type: recordType, TTL: loopia.TTL, Rdata: ipAddr}
+		err = loopia.callOK("addZoneRecord", domain.DomainName, domain.GetSubDomain(), record)
+	}
+
+	if err != nil {
+		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// modify 更新解析
+func (loopia *Loopia) modify(domain *config.Domain, record LoopiaRecord, ipAddr string) {
+	record.TTL = loopia.TTL
+	record.Rdata = ipAddr
+
+	err := loopia.callOK("updateZoneRecord", domain.DomainName, domain.GetSubDomain(), record)
+	if err != nil {
+		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// getZoneRecords 获得子域名的全部记录, 根域名的子域名为 @
+func (loopia *Loopia) getZoneRecords(domain *config.Domain) (records []LoopiaRecord, err error) {
+	result, err := loopia.call("getZoneRecords", domain.DomainName, domain.GetSubDomain())
+	if err != nil {
+		return
+	}
+	// 失败时返回字符串, 如 AUTH_ERROR
+	if result.Array == nil {
+		if s := result.asString(); s != "" {
+			return nil, errors.New(s)
+		}
+		return
+	}
+	for _, v := range result.Array {
+		var record LoopiaRecord
+		for _, m := range v.Struct {
+			switch m.Name {
+			case "type":
+				record.Type = m.Value.asString()
+			case "ttl":
+				record.TTL = m.Value.asInt()
+			case "priority":
+				record.Priority = m.Value.asInt()
+			case "rdata":
+				record.Rdata = m.Value.asString()
+			case "record_id":
+				record.RecordID = m.Value.asInt()
+			}
+		}
+		records = append(records, record)
+	}
+	return
+}
+
+// callOK 调用接口, 返回值不为 OK 时返回异常
+func (loopia *Loopia) callOK(method string, params ...interface{}) error {
+	result, err := loopia.call(method, params...)
+	if err != nil {
+		return err
+	}
+	if s := result.asString(); s != loopiaOK {
+		return errors.New(s)
+	}
+	return nil
+}
+
+// call 统一请求接口, 前两个参数为 API 用户名和密码
+func (loopia *Loopia) call(method string, params ...interface{}) (result loopiaValue, err error) {
+	var body bytes.Buffer
+	body.WriteString(xml.Header)
+	body.WriteString("<methodCall><methodName>" + method + "</methodName><params>")
+	for _, p := range append([]interface{}{loopia.DNS.ID, loopia.DNS.Secret}, params...) {
+		body.WriteString("<param>")
+		writeLoopiaValue(&body, p)
+		body.WriteString("</param>")
+	}
+	body.WriteString("</params></methodCall>")
+
+	endpoint := loopiaEndpoint
+	if e := strings.TrimSpace(loopia.DNS.Endpoint); e != "" {
+		endpoint = e
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, &body)
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "text/xml")
+
+	client := createHTTPClient(loopia.DNS)
+	resp, err := client.Do(req)
+	data, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		return
+	}
+
+	var response struct {
+		Params []loopiaValue `xml:"params>param>value"`
+		Fault  *loopiaValue  `xml:"fault>value"`
+	}
+	if err = xml.Unmarshal(data, &response); err != nil {
+		return
+	}
+	if response.Fault != nil {
+		var code, msg string
+		for _, m := range response.Fault.Struct {
+			switch m.Name {
+			case "faultCode":
+				code = strconv.Itoa(m.Value.asInt())
+			case "faultString":
+				msg = m.Value.asString()
+			}
+		}
+		return result, fmt.Errorf("%s (%s)", msg, code)
+	}
+	if len(response.Params) == 0 {
+		return result, errors.New(util.LogStr("返回内容: %s ,返回状态码: %d", string(data), resp.StatusCode))
+	}
+	return response.Params[0], nil
+}
+
+// writeLoopiaValue 写入 XML-RPC 的 value
+func writeLoopiaValue(body *bytes.Buffer, v interface{}) {
+	body.WriteString("<value>")
+	switch v := v.(type) {
+	case int:
+		body.WriteString("<int>" + strconv.Itoa(v) + "</int>")
+	case LoopiaRecord:
+		body.WriteString("<struct>")
+		for _, m := range []struct {
+			name  string
+			value interface{}
+		}{
+			{"type", v.Type},
+			{"ttl", v.TTL},
+			{"priority", v.Priority},
+			{"rdata", v.Rdata},
+			{"record_id", v.RecordID},
+		} {
+			// 新增记录时没有 record_id
+			if m.name == "record_id" && v.RecordID == 0 {
+				continue
+			}
+			body.WriteString("<member><name>" + m.name + "</name>")
+			writeLoopiaValue(body, m.value)
+			body.WriteString("</member>")
+		}
+		body.WriteString("</struct>")
+	default:
+		body.WriteString("<string>")
+		xml.EscapeText(body, []byte(fmt.Sprint(v)))
+		body.WriteString("</string>")
+	}
+	body.WriteString("</value>")
+}
+
+// loopiaValue XML-RPC 的 value, 未指定类型时为字符串
+type loopiaValue struct {
+	Text   string         `xml:",chardata"`
+	String *string        `xml:"string"`
+	Int    *string        `xml:"int"`
+	I4     *string        `xml:"i4"`
+	Array  []loopiaValue  `xml:"array>data>value"`
+	Struct []loopiaMember `xml:"struct>member"`
+}
+
+// loopiaMember XML-RPC 的 struct member
+type loopiaMember struct {
+	Name  string      `xml:"name"`
+	Value loopiaValue `xml:"value"`
+}
+
+func (v loopiaValue) asString() string {
+	if v.String != nil {
+		return strings.TrimSpace(*v.String)
+	}
+	return strings.TrimSpace(v.Text)
+}
+
+func (v loopiaValue) asInt() int {
+	s := v.Int
+	if s == nil {
+		s = v.I4
+	}
+	if s == nil {
+		return 0
+	}
+	i, _ := strconv.Atoi(strings.TrimSpace(*s))
+	return i
+}
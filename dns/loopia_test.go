@@ -0,0 +1,73 @@
+package dns
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestLoopia 测试 Loopia 的 XML-RPC 请求及返回值
+func TestLoopia(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		calls = append(calls, string(body))
+		switch {
+		case strings.Contains(string(body), "<methodName>getZoneRecords</methodName>") &&
+			strings.Contains(string(body), "<string>www</string>"):
+			w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><array><data>
+				<value><struct>
+					<member><name>type</name><value><string>A</string></value></member>
+					<member><name>ttl</name><value><int>3600</int></value></member>
+					<member><name>priority</name><value><int>0</int></value></member>
+					<member><name>rdata</name><value><string>1.1.1.1</string></value></member>
+					<member><name>record_id</name><value><int>42</int></value></member>
+				</struct></value>
+			</data></array></value></param></params></methodResponse>`))
+		case strings.Contains(string(body), "<methodName>getZoneRecords</methodName>"):
+			w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value>AUTH_ERROR</value></param></params></methodResponse>`))
+		default:
+			w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><string>OK</string></value></param></params></methodResponse>`))
+		}
+	}))
+	defer server.Close()
+
+	dc := &config.DnsConfig{TTL: "300"}
+	dc.DNS = config.DNS{Name: "loopia", ID: "user@loopiaapi", Secret: "p&ss", Endpoint: server.URL}
+	dc.Ipv4.Enable = true
+	dc.Ipv4.GetType = "push"
+	dc.Ipv4.Domains = []string{"www.example.com", "denied.example.com"}
+
+	loopia := &Loopia{}
+	loopia.Init(dc, &util.IpCache{}, &util.IpCache{})
+	loopia.Domains.Ipv4Addr = "2.2.2.2"
+	domains := loopia.AddUpdateDomainRecords()
+
+	if domains.Ipv4Domains[0].UpdateStatus != config.UpdatedSuccess {
+		t.Errorf("期待 www.example.com 更新成功, 得到 %s", domains.Ipv4Domains[0].UpdateStatus)
+	}
+	if domains.Ipv4Domains[1].UpdateStatus != config.UpdatedFailed {
+		t.Errorf("期待 denied.example.com 更新失败, 得到 %s", domains.Ipv4Domains[1].UpdateStatus)
+	}
+	if len(calls) != 3 {
+		t.Fatalf("期待 3 次请求, 得到 %d 次", len(calls))
+	}
+	update := calls[1]
+	for _, want := range []string{
+		"<methodName>updateZoneRecord</methodName>",
+		"<string>user@loopiaapi</string>", "<string>p&amp;ss</string>",
+		"<string>example.com</string>", "<string>www</string>",
+		"<name>ttl</name><value><int>300</int></value>",
+		"<name>rdata</name><value><string>2.2.2.2</string></value>",
+		"<name>record_id</name><value><int>42</int></value>",
+	} {
+		if !strings.Contains(update, want) {
+			t.Errorf("期待请求包含 %s, 得到 %s", want, update)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package dns
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// 请求DNS服务商接口的错误类型
+const (
+	// errorAuth 认证失败, 401/403
+	errorAuth = "auth"
+	// errorRateLimit 被限流, 429
+	errorRateLimit = "rate_limit"
+	// errorNetwork 网络异常, 未收到响应
+	errorNetwork = "network"
+	// errorServer 服务商异常, 5xx
+	errorServer = "server"
+	// errorClient 其它请求错误, 4xx
+	errorClient = "client"
+)
+
+// latencyBuckets 接口耗时直方图的上限(秒)
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// providerMetrics 单个服务商的接口耗时及错误数
+type providerMetrics struct {
+	// buckets 耗时不超过对应上限的请求数, 与 latencyBuckets 对应
+	buckets []uint64
+	count   uint64
+	sum     float64
+	errors  map[string]uint64
+}
+
+// domainKey 域名更新结果的标签
+type domainKey struct {
+	provider string
+	domain   string
+	result   string
+}
+
+var metrics = struct {
+	sync.Mutex
+	providers map[string]*providerMetrics
+	domains   map[domainKey]uint64
+}{providers: map[string]*providerMetrics{}, domains: map[domainKey]uint64{}}
+
+// observeRequest 记录一次请求的耗时及错误类型, errType 为空时没有错误
+func observeRequest(provider string, elapsed time.Duration, errType string) {
+	metrics.Lock()
+	defer metrics.Unlock()
+	m, ok := metrics.providers[provider]
+	if !ok {
+		m = &providerMetrics{buckets: make([]uint64, len(latencyBuckets)), errors: map[string]uint64{}}
+		metrics.providers[provider] = m
+	}
+	seconds := elapsed.Seconds()
+	for i, le := range latencyBuckets {
+		if seconds <= le {
+			m.buckets[i]++
+		}
+	}
+	m.count++
+	m.sum += seconds
+	if errType != "" {
+		m.errors[errType]++
+	}
+}
+
+// classifyResponse 根据响应状态码获得错误类型, 成功时为空
+func classifyResponse(statusCode int) string {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return errorAuth
+	case statusCode == http.StatusTooManyRequests:
+		return errorRateLimit
+	case statusCode >= 500:
+		return errorServer
+	case statusCode >= 400:
+		return errorClient
+	}
+	return ""
+}
+
+// metricsTransport 记录实际请求服务商接口的耗时及错误, 不包括限流的等待时间
+type metricsTransport struct {
+	base     http.RoundTripper
+	provider string
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		observeRequest(t.provider, time.Since(start), errorNetwork)
+		return resp, err
+	}
+	observeRequest(t.provider, time.Since(start), classifyResponse(resp.StatusCode))
+	return resp, nil
+}
+
+// observeDomains 记录域名的更新结果, 未获取到IP或使用缓存时不记录
+func observeDomains(provider string, domains *config.Domains) {
+	metrics.Lock()
+	defer metrics.Unlock()
+	for _, domain := range append(slices.Clone(domains.Ipv4Domains), domains.Ipv6Domains...) {
+		result := domainResultName(domain)
+		if result == "" {
+			continue
+		}
+		metrics.domains[domainKey{provider: provider, domain: domain.String(), result: result}]++
+	}
+}
+
+// domainResultName 域名更新状态的英文名称, 未获取到IP或使用缓存时为空
+func domainResultName(domain *config.Domain) string {
+	switch domain.UpdateStatus {
+	case config.UpdatedSuccess:
+		return "updated"
+	case config.UpdatedNothing:
+		return "unchanged"
+	case config.UpdatedFailed:
+		return "failed"
+	}
+	return ""
+}
+
+// WriteMetrics 以 Prometheus 文本格式输出服务商接口的耗时、错误数及域名的更新结果
+func WriteMetrics(w io.Writer) {
+	metrics.Lock()
+	defer metrics.Unlock()
+
+	providers := make([]string, 0, len(metrics.providers))
+	for name := range metrics.providers {
+		providers = append(providers, name)
+	}
+	slices.Sort(providers)
+
+	fmt.Fprintln(w, "# HELP ddns_go_provider_request_duration_seconds Latency of DNS provider API requests.")
+	fmt.Fprintln(w, "# TYPE ddns_go_provider_request_duration_seconds histogram")
+	for _, name := range providers {
+		m := metrics.providers[name]
+		for i, le := range latencyBuckets {
+			fmt.Fprintf(w, "ddns_go_provider_request_duration_seconds_bucket{provider=%q,le=\"%g\"} %d\n", name, le, m.buckets[i])
+		}
+		fmt.Fprintf(w, "ddns_go_provider_request_duration_seconds_bucket{provider=%q,le=\"+Inf\"} %d\n", name, m.count)
+		fmt.Fprintf(w, "ddns_go_provider_request_duration_seconds_sum{provider=%q} %g\n", name, m.sum)
+		fmt.Fprintf(w, "ddns_go_provider_request_duration_seconds_count{provider=%q} %d\n", name, m.count)
+	}
+
+	fmt.Fprintln(w, "# HELP ddns_go_provider_request_errors_total DNS provider API request errors by category.")
+	fmt.Fprintln(w, "# TYPE ddns_go_provider_request_errors_total counter")
+	for _, name := range providers {
+		m := metrics.providers[name]
+		for _, category := range []string{errorAuth, errorRateLimit, errorNetwork, errorServer, errorClient} {
+			fmt.Fprintf(w, "ddns_go_provider_request_errors_total{provider=%q,category=%q} %d\n", name, category, m.errors[category])
+		}
+	}
+
+	keys := make([]domainKey, 0, len(metrics.domains))
+	for key := range metrics.domains {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b domainKey) int {
+		return strings.Compare(a.provider+" "+a.domain+" "+a.result, b.provider+" "+b.domain+" "+b.result)
+	})
+	fmt.Fprintln(w, "# HELP ddns_go_domain_updates_total Results of comparing domains with the DNS provider.")
+	fmt.Fprintln(w, "# TYPE ddns_go_domain_updates_total counter")
+	for _, key := range keys {
+		fmt.Fprintf(w, "ddns_go_domain_updates_total{provider=%q,domain=%q,result=%q} %d\n", key.provider, key.domain, key.result, metrics.domains[key])
+	}
+}
@@ -0,0 +1,61 @@
+package dns
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// TestMetrics 测试按服务商记录接口耗时及错误类型, 按域名记录更新结果
+func TestMetrics(t *testing.T) {
+	metrics.providers = map[string]*providerMetrics{}
+	metrics.domains = map[domainKey]uint64{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/limit":
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	client := &http.Client{Transport: &metricsTransport{base: http.DefaultTransport, provider: "cloudflare"}}
+	for _, path := range []string{"/", "/auth", "/limit"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	server.Close()
+	if _, err := client.Get(server.URL); err == nil {
+		t.Fatal("服务器已关闭, 期待返回异常")
+	}
+
+	observeDomains("cloudflare", &config.Domains{
+		Ipv4Domains: []*config.Domain{{DomainName: "example.com", SubDomain: "www", UpdateStatus: config.UpdatedSuccess}},
+		Ipv6Domains: []*config.Domain{{DomainName: "example.com", SubDomain: "www"}},
+	})
+
+	var buf bytes.Buffer
+	WriteMetrics(&buf)
+	for _, line := range []string{
+		`ddns_go_provider_request_duration_seconds_count{provider="cloudflare"} 4`,
+		`ddns_go_provider_request_errors_total{provider="cloudflare",category="auth"} 1`,
+		`ddns_go_provider_request_errors_total{provider="cloudflare",category="rate_limit"} 1`,
+		`ddns_go_provider_request_errors_total{provider="cloudflare",category="network"} 1`,
+		`ddns_go_provider_request_errors_total{provider="cloudflare",category="server"} 0`,
+		`ddns_go_domain_updates_total{provider="cloudflare",domain="www.example.com",result="updated"} 1`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("期待包含 %s, 得到:\n%s", line, buf.String())
+		}
+	}
+	if strings.Contains(buf.String(), `result="unchanged"`) {
+		t.Error("未比对的域名不应记录")
+	}
+}
@@ -0,0 +1,129 @@
+package dns
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const mythicBeastsEndpoint = "https://api.mythic-beasts.com/dns/v2"
+
+// MythicBeasts Mythic Beasts DNS API v2
+// https://www.mythic-beasts.com/support/api/dnsv2
+type MythicBeasts struct {
+	DNS     config.DNS
+	Domains config.Domains
+}
+
+// MythicBeastsRecord 记录
+type MythicBeastsRecord struct {
+	Data string `json:"data"`
+}
+
+// MythicBeastsRecords 查询及更新记录的请求体
+type MythicBeastsRecords struct {
+	Records []MythicBeastsRecord `json:"records"`
+}
+
+// MythicBeastsResp 更新记录的结果
+type MythicBeastsResp struct {
+	Message string `json:"message"`
+	Error   string `json:"error"`
+}
+
+// Init 初始化
+func (mb *MythicBeasts) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	mb.Domains.Ipv4Cache = ipv4cache
+	mb.Domains.Ipv6Cache = ipv6cache
+	mb.DNS = dnsConf.DNS
+	mb.Domains.GetNewIp(dnsConf)
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (mb *MythicBeasts) AddUpdateDomainRecords() config.Domains {
+	mb.addUpdateDomainRecords("A")
+	mb.addUpdateDomainRecords("AAAA")
+	return mb.Domains
+}
+
+func (mb *MythicBeasts) addUpdateDomainRecords(recordType string) {
+	ipAddr, domains := mb.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	for _, domain := range domains {
+		// 记录与获取到的IP一致时不更新, 查询失败时仍尝试更新
+		var records MythicBeastsRecords
+		err := mb.request(http.MethodGet, mb.recordURL(domain, recordType), nil, &records)
+		if err == nil && len(records.Records) == 1 && records.Records[0].Data == ipAddr {
+			setUpdateResult(domain, ipAddr, resultUnchanged, nil)
+			continue
+		}
+		mb.modify(domain, recordType, ipAddr)
+	}
+}
+
+// modify 替换记录为获取到的IP, 不使用动态DNS接口, 以免记录被设为请求的来源IP
+func (mb *MythicBeasts) modify(domain *config.Domain, recordType string, ipAddr string) {
+	body := MythicBeastsRecords{Records: []MythicBeastsRecord{{Data: ipAddr}}}
+
+	var result MythicBeastsResp
+	err := mb.request(http.MethodPut, mb.recordURL(domain, recordType), body, &result)
+	if err == nil && result.Error != "" {
+		err = errors.New(result.Error)
+	}
+	if err != nil {
+		setUpdateResult(domain, ipAddr, resultFailed, err)
+		return
+	}
+	setUpdateResult(domain, ipAddr, resultUpdated, nil)
+}
+
+// recordURL 记录的地址, 如 /zones/example.com/records/www/A
+func (mb *MythicBeasts) recordURL(domain *config.Domain, recordType string) string {
+	endpoint := mythicBeastsEndpoint
+	if e := strings.TrimSpace(mb.DNS.Endpoint); e != "" {
+		endpoint = strings.TrimSuffix(e, "/")
+	}
+	return endpoint + "/zones/" + url.PathEscape(domain.DomainName) +
+		"/records/" + url.PathEscape(domain.GetSubDomain()) + "/" + recordType
+}
+
+// request 统一请求接口, 使用 HTTP Basic 认证
+func (mb *MythicBeasts) request(method string, url string, data interface{}, result interface{}) error {
+	var body io.Reader = http.NoBody
+	if data != nil {
+		byt, _ := json.Marshal(data)
+		body = bytes.NewReader(byt)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(mb.DNS.ID, mb.DNS.Secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := createHTTPClient(mb.DNS)
+	resp, err := client.Do(req)
+	byt, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		var mbErr MythicBeastsResp
+		if resp != nil && json.Unmarshal(byt, &mbErr) == nil && mbErr.Error != "" {
+			return errors.New(mbErr.Error)
+		}
+		return err
+	}
+	if result != nil && len(byt) > 0 {
+		return json.Unmarshal(byt, result)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package dns
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestMythicBeasts 测试 Mythic Beasts 使用获取到的IP替换记录
+func TestMythicBeasts(t *testing.T) {
+	puts := map[string]MythicBeastsRecords{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "key" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			data := "1.1.1.1"
+			if r.URL.Path == "/zones/example.com/records/@/A" {
+				data = "2.2.2.2"
+			}
+			json.NewEncoder(w).Encode(MythicBeastsRecords{Records: []MythicBeastsRecord{{Data: data}}})
+		case http.MethodPut:
+			if r.URL.Path == "/zones/denied.com/records/www/A" {
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error":"API key does not have permission"}`))
+				return
+			}
+			var body MythicBeastsRecords
+			json.NewDecoder(r.Body).Decode(&body)
+			puts[r.URL.Path] = body
+			w.Write([]byte(`{"message":"1 record added, 1 record removed"}`))
+		}
+	}))
+	defer server.Close()
+
+	dc := &config.DnsConfig{}
+	dc.DNS = config.DNS{Name: "mythicbeasts", ID: "key", Secret: "secret", Endpoint: server.URL}
+	dc.Ipv4.Enable = true
+	dc.Ipv4.GetType = "push"
+	dc.Ipv4.Domains = []string{"www.example.com", "example.com", "www.denied.com"}
+
+	mb := &MythicBeasts{}
+	mb.Init(dc, &util.IpCache{}, &util.IpCache{})
+	mb.Domains.Ipv4Addr = "2.2.2.2"
+	domains := mb.AddUpdateDomainRecords()
+
+	want := []string{config.UpdatedSuccess, string(config.UpdatedNothing), config.UpdatedFailed}
+	for i, domain := range domains.Ipv4Domains {
+		if string(domain.UpdateStatus) != want[i] {
+			t.Errorf("%s 期待 %s, 得到 %s", domain, want[i], domain.UpdateStatus)
+		}
+	}
+	if len(puts) != 1 {
+		t.Fatalf("期待 1 次更新, 得到 %d 次", len(puts))
+	}
+	body := puts["/zones/example.com/records/www/A"]
+	if len(body.Records) != 1 || body.Records[0].Data != "2.2.2.2" {
+		t.Errorf("期待记录更新为 2.2.2.2, 得到 %+v", body)
+	}
+}
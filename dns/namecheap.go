@@ -109,7 +109,7 @@ func (nc *NameCheap) request(result *NameCheapResp, ipAddr string, domain *confi
 		return
 	}
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(nc.DNS)
 	resp, err := client.Do(req)
 	if err != nil {
 		return
@@ -105,11 +105,14 @@ func (ns *NameSilo) addUpdateDomainRecords(recordType string) {
 		var isAdd bool
 		var recordID string
 		if record == nil {
+			if skipCreate(domain) {
+				continue
+			}
 			isAdd = true
 		} else {
 			recordID = record.RecordID
 			if record.Value == ipAddr {
-				util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+				setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 				continue
 			}
 		}
@@ -179,7 +182,7 @@ func (ns *NameSilo) request(ipAddr string, domain *config.Domain, recordID, reco
 		return
 	}
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(ns.DNS)
 	resp, err := client.Do(req)
 	if err != nil {
 		return
@@ -0,0 +1,185 @@
+package dns
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const njallaEndpoint = "https://njal.la/api/1/"
+
+// njallaTTLs Njalla 支持的TTL
+var njallaTTLs = []int{60, 300, 900, 3600, 10800, 21600, 86400}
+
+// Njalla Njalla JSON-RPC API
+// https://njal.la/api/
+type Njalla struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+}
+
+// NjallaRecord 记录实体
+type NjallaRecord struct {
+	ID      interface{} `json:"id"`
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Content string      `json:"content"`
+	TTL     int         `json:"ttl"`
+}
+
+// NjallaResp 返回结果, 失败时 error 不为空
+type NjallaResp struct {
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Init 初始化
+func (nj *Njalla) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	nj.Domains.Ipv4Cache = ipv4cache
+	nj.Domains.Ipv6Cache = ipv6cache
+	nj.DNS = dnsConf.DNS
+	nj.Domains.GetNewIp(dnsConf)
+	if dnsConf.TTL != "" {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err == nil && slices.Contains(njallaTTLs, ttl) {
+			nj.TTL = ttl
+		} else {
+			// 不支持的TTL使用服务商的默认值
+			util.Log("Njalla 不支持TTL %s, 将使用默认值", dnsConf.TTL)
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (nj *Njalla) AddUpdateDomainRecords() config.Domains {
+	nj.addUpdateDomainRecords("A")
+	nj.addUpdateDomainRecords("AAAA")
+	return nj.Domains
+}
+
+func (nj *Njalla) addUpdateDomainRecords(recordType string) {
+	ipAddr, domains := nj.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	for _, domain := range domains {
+		var result struct {
+			Records []NjallaRecord `json:"records"`
+		}
+		err := nj.request("list-records", map[string]interface{}{"domain": domain.DomainName}, &result)
+		if err != nil {
+			util.Log("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		// 记录名称相对于域名, 根域名为 @
+		name := domain.GetSubDomain()
+		var find *NjallaRecord
+		for i, r := range result.Records {
+			if r.Type == recordType && strings.EqualFold(r.Name, name) {
+				find = &result.Records[i]
+				break
+			}
+		}
+
+		if find == nil {
+			nj.create(domain, recordType, name, ipAddr)
+			continue
+		}
+		if find.Content == ipAddr {
+			setUpdateResult(domain, ipAddr, resultUnchanged, nil)
+			continue
+		}
+		nj.modify(domain, find, ipAddr)
+	}
+}
+
+// create 创建新的解析
+func (nj *Njalla) create(domain *config.Domain, recordType string, name string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
+	params := map[string]interface{}{
+		"domain":  domain.DomainName,
+		"type":    recordType,
+		"name":    name,
+		"content": ipAddr,
+	}
+	if nj.TTL > 0 {
+		params["ttl"] = nj.TTL
+	}
+
+	err := nj.request("add-record", params, nil)
+	if err != nil {
+		util.Log("新增域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+	util.Log("新增域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// modify 更新解析
+func (nj *Njalla) modify(domain *config.Domain, record *NjallaRecord, ipAddr string) {
+	params := map[string]interface{}{
+		"domain":  domain.DomainName,
+		"id":      record.ID,
+		"content": ipAddr,
+	}
+	if nj.TTL > 0 {
+		params["ttl"] = nj.TTL
+	}
+
+	err := nj.request("edit-record", params, nil)
+	if err != nil {
+		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+	util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// request 统一请求接口, 返回 RPC 的异常信息
+func (nj *Njalla) request(method string, params map[string]interface{}, result interface{}) error {
+	byt, _ := json.Marshal(map[string]interface{}{"method": method, "params": params})
+	endpoint := njallaEndpoint
+	if e := strings.TrimSpace(nj.DNS.Endpoint); e != "" {
+		endpoint = e
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(byt))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Njalla "+nj.DNS.Secret)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	var resp NjallaResp
+	client := createHTTPClient(nj.DNS)
+	httpResp, err := client.Do(req)
+	if err = util.GetHTTPResponse(httpResp, err, &resp); err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return errors.New(resp.Error.Message + " (" + strconv.Itoa(resp.Error.Code) + ")")
+	}
+	if result != nil && len(resp.Result) > 0 {
+		return json.Unmarshal(resp.Result, result)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package dns
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestNjalla 测试 Njalla 的记录名称、新增及更新请求, 以及 RPC 异常
+func TestNjalla(t *testing.T) {
+	type call struct {
+		Method string                 `json:"method"`
+		Params map[string]interface{} `json:"params"`
+	}
+	var calls []call
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Njalla token" {
+			t.Errorf("期待 Authorization 为 Njalla token, 得到 %s", got)
+		}
+		var c call
+		json.NewDecoder(r.Body).Decode(&c)
+		calls = append(calls, c)
+		switch {
+		case c.Method == "list-records" && c.Params["domain"] == "example.com":
+			w.Write([]byte(`{"result":{"records":[
+				{"id":1,"name":"@","type":"A","content":"1.1.1.1","ttl":3600},
+				{"id":"2","name":"WWW","type":"A","content":"2.2.2.2","ttl":3600},
+				{"id":3,"name":"new","type":"AAAA","content":"::1","ttl":3600}
+			]}}`))
+		case c.Method == "list-records":
+			w.Write([]byte(`{"error":{"code":404,"message":"Domain not found"}}`))
+		case c.Method == "add-record" && c.Params["name"] == "denied":
+			w.Write([]byte(`{"error":{"code":403,"message":"Permission denied"}}`))
+		default:
+			w.Write([]byte(`{"result":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	dc := &config.DnsConfig{TTL: "300"}
+	dc.DNS = config.DNS{Name: "njalla", Secret: "token", Endpoint: server.URL}
+	dc.Ipv4.Enable = true
+	dc.Ipv4.GetType = "push"
+	dc.Ipv4.Domains = []string{"example.com", "www.example.com", "new.example.com", "denied.example.com", "missing.org"}
+
+	nj := &Njalla{}
+	nj.Init(dc, &util.IpCache{}, &util.IpCache{})
+	nj.Domains.Ipv4Addr = "2.2.2.2"
+	domains := nj.AddUpdateDomainRecords()
+
+	for i, want := range []string{config.UpdatedSuccess, string(config.UpdatedNothing), config.UpdatedSuccess, config.UpdatedFailed, config.UpdatedFailed} {
+		if got := domains.Ipv4Domains[i]; string(got.UpdateStatus) != want {
+			t.Errorf("期待 %s 的状态为 %q, 得到 %q", got, want, got.UpdateStatus)
+		}
+	}
+
+	var writes []call
+	for _, c := range calls {
+		if c.Method != "list-records" {
+			writes = append(writes, c)
+		}
+	}
+	if len(writes) != 3 {
+		t.Fatalf("期待 3 次修改请求, 得到 %+v", writes)
+	}
+	// 根域名使用已有记录的 ID 更新
+	if c := writes[0]; c.Method != "edit-record" || c.Params["id"] != float64(1) || c.Params["content"] != "2.2.2.2" || c.Params["ttl"] != float64(300) {
+		t.Errorf("期待更新根域名的记录 1, 得到 %+v", c)
+	}
+	// 同名的 AAAA 记录不影响新增 A 记录
+	if c := writes[1]; c.Method != "add-record" || c.Params["domain"] != "example.com" || c.Params["name"] != "new" || c.Params["type"] != "A" || c.Params["content"] != "2.2.2.2" {
+		t.Errorf("期待新增 new 的 A 记录, 得到 %+v", c)
+	}
+	if c := writes[2]; c.Method != "add-record" || c.Params["name"] != "denied" {
+		t.Errorf("期待新增 denied 的记录, 得到 %+v", c)
+	}
+
+	// RPC 异常返回 error 中的信息
+	err := nj.request("list-records", map[string]interface{}{"domain": "missing.org"}, nil)
+	if err == nil || err.Error() != "Domain not found (404)" {
+		t.Errorf("期待返回 RPC 异常, 得到 %v", err)
+	}
+}
+
+// TestNjallaNames 测试根域名的记录名称为 @
+func TestNjallaNames(t *testing.T) {
+	var names []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var c struct {
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&c)
+		if c.Method == "add-record" {
+			names = append(names, c.Params["name"])
+		}
+		w.Write([]byte(`{"result":{"records":[]}}`))
+	}))
+	defer server.Close()
+
+	dc := &config.DnsConfig{}
+	dc.DNS = config.DNS{Name: "njalla", Secret: "token", Endpoint: server.URL}
+	dc.Ipv6.Enable = true
+	dc.Ipv6.GetType = "push"
+	dc.Ipv6.Domains = []string{"example.com", "a.b.example.com", "www:example.co.uk"}
+
+	nj := &Njalla{}
+	nj.Init(dc, &util.IpCache{}, &util.IpCache{})
+	nj.Domains.Ipv6Addr = "2001:db8::1"
+	nj.AddUpdateDomainRecords()
+
+	want := []interface{}{"@", "a.b", "www"}
+	if len(names) != len(want) {
+		t.Fatalf("期待记录名称 %v, 得到 %v", want, names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("期待记录名称 %v, 得到 %v", want, names)
+			break
+		}
+	}
+}
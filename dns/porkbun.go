@@ -107,6 +107,9 @@ func (pb *Porkbun) addUpdateDomainRecords(recordType string) {
 
 // 创建
 func (pb *Porkbun) create(domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	var response PorkbunResponse
 
 	err := pb.request(
@@ -146,7 +149,7 @@ func (pb *Porkbun) modify(record *PorkbunDomainQueryResponse, domain *config.Dom
 
 	// 相同不修改
 	if len(record.Records) > 0 && *record.Records[0].Content == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 		return
 	}
 
@@ -198,7 +201,7 @@ func (pb *Porkbun) request(url string, data interface{}, result interface{}) (er
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(pb.DNSConfig)
 	resp, err := client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
@@ -0,0 +1,88 @@
+package dns
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// pushgatewayLabel 分组标签的路径, 值为空或包含 / 时需 base64 编码
+func pushgatewayLabel(name string, value string) string {
+	if value == "" {
+		return name + "@base64/="
+	}
+	if strings.Contains(value, "/") {
+		return name + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+	return name + "/" + url.PathEscape(value)
+}
+
+// pushgatewayURL 返回按 job/instance 分组推送指标的地址
+func pushgatewayURL(gateway string, job string, instance string) (string, error) {
+	u, err := url.Parse(gateway)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.New(util.LogStr("Pushgateway 地址 %s 不正确", gateway))
+	}
+	if job == "" {
+		return "", errors.New(util.LogStr("Pushgateway 的 job 不能为空"))
+	}
+	return strings.TrimSuffix(gateway, "/") + "/metrics/" + pushgatewayLabel("job", job) + "/" + pushgatewayLabel("instance", instance), nil
+}
+
+// pushMetrics 使用 PUT 推送完整的指标, 替换该分组之前的指标
+func pushMetrics(client *http.Client, target string) error {
+	var buf bytes.Buffer
+	WriteMetrics(&buf)
+	req, err := http.NewRequest(http.MethodPut, target, &buf)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// RunPushgateway 每隔 interval 将 /metrics 的指标推送到 Pushgateway, gateway 为空时不启用
+// 推送失败只记录日志, 下次继续推送
+func RunPushgateway(gateway string, job string, instance string, interval time.Duration) {
+	if gateway == "" {
+		return
+	}
+	target, err := pushgatewayURL(gateway, job, instance)
+	if err != nil {
+		util.Log(err.Error())
+		return
+	}
+	util.Log("每隔 %s 推送指标到 Pushgateway, job: %s, instance: %s", interval, job, instance)
+
+	client := util.CreateHTTPClient()
+	failed := false
+	for {
+		time.Sleep(interval)
+		if err := pushMetrics(client, target); err != nil {
+			util.Log("推送指标到 Pushgateway 失败: %s", err)
+			failed = true
+			continue
+		}
+		if failed {
+			util.Log("推送指标到 Pushgateway 已恢复")
+			failed = false
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package dns
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestPushgatewayURL 测试按 job/instance 分组的推送地址
+func TestPushgatewayURL(t *testing.T) {
+	tests := []struct {
+		gateway, job, instance, want string
+	}{
+		{"http://pushgateway:9091", "ddns-go", "router", "http://pushgateway:9091/metrics/job/ddns-go/instance/router"},
+		{"http://pushgateway:9091/", "ddns go", "", "http://pushgateway:9091/metrics/job/ddns%20go/instance@base64/="},
+		{"https://example.com/push", "ddns-go", "a/b", "https://example.com/push/metrics/job/ddns-go/instance@base64/YS9i"},
+	}
+	for _, tt := range tests {
+		got, err := pushgatewayURL(tt.gateway, tt.job, tt.instance)
+		if err != nil || got != tt.want {
+			t.Errorf("期待 %s, 得到 %s %v", tt.want, got, err)
+		}
+	}
+	for _, gateway := range []string{"pushgateway:9091", "ftp://pushgateway"} {
+		if _, err := pushgatewayURL(gateway, "ddns-go", ""); err == nil {
+			t.Errorf("期待 %s 不正确", gateway)
+		}
+	}
+	if _, err := pushgatewayURL("http://pushgateway:9091", "", ""); err == nil {
+		t.Errorf("期待 job 为空时返回异常")
+	}
+}
+
+// TestPushMetrics 测试使用 PUT 推送 /metrics 的指标, 失败时返回异常
+func TestPushMetrics(t *testing.T) {
+	var method, body string
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		method, body = r.Method, string(b)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	if err := pushMetrics(server.Client(), server.URL+"/metrics/job/ddns-go/instance/router"); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPut || !strings.Contains(body, "# TYPE ddns_go_domain_updates_total counter") {
+		t.Errorf("期待使用 PUT 推送指标, 得到 %s %s", method, body)
+	}
+
+	status = http.StatusBadRequest
+	if err := pushMetrics(server.Client(), server.URL); err == nil {
+		t.Errorf("期待返回 400 时推送失败")
+	}
+}
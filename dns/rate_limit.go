@@ -0,0 +1,105 @@
+package dns
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// providerRateLimits 服务商公布的接口频率限制(每秒请求数), 与 -rateLimit 同时设置时使用较小的值
+var providerRateLimits = map[string]float64{
+	// 每分钟60次 https://developer.godaddy.com/getstarted
+	"godaddy": 1,
+	// 每5分钟1200次 https://developers.cloudflare.com/fundamentals/api/reference/limits/
+	"cloudflare": 4,
+}
+
+var (
+	// rateLimit 每种服务商每秒最多的请求数, 为0时只使用服务商公布的限制
+	rateLimit float64
+	// concurrency 每次运行同时更新的配置数
+	concurrency = 1
+
+	limiters = struct {
+		sync.Mutex
+		buckets map[string]*tokenBucket
+	}{buckets: map[string]*tokenBucket{}}
+)
+
+// SetRateLimit 设置每种服务商每秒最多的请求数
+func SetRateLimit(rps float64) {
+	limiters.Lock()
+	defer limiters.Unlock()
+	rateLimit = max(rps, 0)
+	limiters.buckets = map[string]*tokenBucket{}
+}
+
+// SetConcurrency 设置每次运行同时更新的配置数, 小于1时为1
+func SetConcurrency(n int) {
+	concurrency = max(n, 1)
+}
+
+// getLimiter 获得服务商的令牌桶, 同一种服务商的多个配置共用, 不限制时返回 nil
+func getLimiter(name string) *tokenBucket {
+	limiters.Lock()
+	defer limiters.Unlock()
+
+	rate := rateLimit
+	if limit, ok := providerRateLimits[name]; ok && (rate == 0 || limit < rate) {
+		rate = limit
+	}
+	if rate == 0 {
+		return nil
+	}
+	bucket, ok := limiters.buckets[name]
+	if !ok {
+		bucket = &tokenBucket{rate: rate, burst: math.Max(1, math.Floor(rate))}
+		limiters.buckets[name] = bucket
+	}
+	return bucket
+}
+
+// tokenBucket 令牌桶, 每秒补充 rate 个令牌, 最多 burst 个
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// reserve 取出一个令牌, 返回需等待的时间, 令牌为负数时表示已预约的请求
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.last.IsZero() {
+		b.tokens = b.burst
+	} else {
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// rateLimitTransport 按令牌桶限制请求频率, 令牌不足时等待
+type rateLimitTransport struct {
+	base   http.RoundTripper
+	bucket *tokenBucket
+}
+
+func (t *rateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if wait := t.bucket.reserve(time.Now()); wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	return t.base.RoundTrip(req)
+}
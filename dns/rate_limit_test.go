@@ -0,0 +1,46 @@
+package dns
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTokenBucket 测试令牌用完后按速率等待
+func TestTokenBucket(t *testing.T) {
+	b := &tokenBucket{rate: 2, burst: 2}
+	now := time.Now()
+
+	for i, expected := range []time.Duration{0, 0, 500 * time.Millisecond, time.Second} {
+		if got := b.reserve(now); got != expected {
+			t.Errorf("第 %d 次期待等待 %s, 得到 %s", i+1, expected, got)
+		}
+	}
+	// 1.5秒后补充3个令牌, 抵消已预约的2个
+	if got := b.reserve(now.Add(1500 * time.Millisecond)); got != 0 {
+		t.Errorf("期待无需等待, 得到 %s", got)
+	}
+}
+
+// TestGetLimiter 测试使用 -rateLimit 与服务商公布的限制中较小的值
+func TestGetLimiter(t *testing.T) {
+	defer SetRateLimit(0)
+
+	SetRateLimit(0)
+	if getLimiter("alidns") != nil {
+		t.Error("未设置限制时期待不限制")
+	}
+	if b := getLimiter("godaddy"); b == nil || b.rate != 1 {
+		t.Errorf("GoDaddy 期待每秒1次, 得到 %+v", b)
+	}
+
+	SetRateLimit(2)
+	if b := getLimiter("alidns"); b == nil || b.rate != 2 {
+		t.Errorf("期待每秒2次, 得到 %+v", b)
+	}
+	if b := getLimiter("cloudflare"); b == nil || b.rate != 2 {
+		t.Errorf("Cloudflare 期待每秒2次, 得到 %+v", b)
+	}
+	if getLimiter("alidns") != getLimiter("alidns") {
+		t.Error("同一种服务商期待共用令牌桶")
+	}
+}
@@ -0,0 +1,43 @@
+package dns
+
+import (
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// updateResult 服务商的更新结果, 各服务商统一按此记录状态及日志
+type updateResult int
+
+const (
+	// resultUpdated 已更新
+	resultUpdated updateResult = iota
+	// resultUnchanged 未改变, 包括记录已是当前IP及服务商返回未变化, 如 DynDNS2 的 nochg
+	resultUnchanged
+	// resultFailed 更新失败
+	resultFailed
+)
+
+// setUpdateResult 记录域名的更新结果, 未改变时不计为已更新, 也不会触发Webhook及通知
+func setUpdateResult(domain *config.Domain, ipAddr string, result updateResult, err error) {
+	switch result {
+	case resultUpdated:
+		util.Log("更新域名解析 %s 成功! IP: %s", domain, ipAddr)
+		domain.UpdateStatus = config.UpdatedSuccess
+	case resultUnchanged:
+		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		domain.UpdateStatus = config.UpdatedNothing
+	default:
+		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+	}
+}
+
+// skipCreate 域名开启只更新已有记录时, 记录不存在则输出日志并计为失败, 返回 true 时不新增记录
+func skipCreate(domain *config.Domain) bool {
+	if !domain.UpdateOnly {
+		return false
+	}
+	util.Log("域名 %s 的记录不存在, 已开启只更新已有记录, 不会新增", domain)
+	domain.UpdateStatus = config.UpdatedFailed
+	return true
+}
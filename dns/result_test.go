@@ -0,0 +1,57 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// TestSetUpdateResult 测试未改变时不计为已更新
+func TestSetUpdateResult(t *testing.T) {
+	tests := []struct {
+		result   updateResult
+		expected string
+	}{
+		{resultUpdated, config.UpdatedSuccess},
+		{resultUnchanged, string(config.UpdatedNothing)},
+		{resultFailed, config.UpdatedFailed},
+	}
+	for _, tt := range tests {
+		domain := &config.Domain{DomainName: "example.com"}
+		setUpdateResult(domain, "1.2.3.4", tt.result, nil)
+		if string(domain.UpdateStatus) != tt.expected {
+			t.Errorf("%d 期待 %s, 得到 %s", tt.result, tt.expected, domain.UpdateStatus)
+		}
+	}
+}
+
+// TestParseDynDNS2Status 测试解析 DynDNS2 的返回值, nochg 为未改变
+func TestParseDynDNS2Status(t *testing.T) {
+	tests := []struct {
+		status   string
+		expected updateResult
+	}{
+		{"good 1.2.3.4", resultUpdated},
+		{"nochg 1.2.3.4", resultUnchanged},
+		{"badauth", resultFailed},
+		{"", resultFailed},
+	}
+	for _, tt := range tests {
+		result, err := parseDynDNS2Status(tt.status)
+		if result != tt.expected || (err != nil) != (tt.expected == resultFailed) {
+			t.Errorf("%q 期待 %d, 得到 %d %v", tt.status, tt.expected, result, err)
+		}
+	}
+}
+
+// TestSkipCreate 测试开启只更新已有记录时不新增且计为失败
+func TestSkipCreate(t *testing.T) {
+	domain := &config.Domain{DomainName: "example.com"}
+	if skipCreate(domain) || domain.UpdateStatus != "" {
+		t.Errorf("期待默认新增记录, 得到 %s", domain.UpdateStatus)
+	}
+	domain.UpdateOnly = true
+	if !skipCreate(domain) || string(domain.UpdateStatus) != config.UpdatedFailed {
+		t.Errorf("期待不新增且更新失败, 得到 %s", domain.UpdateStatus)
+	}
+}
@@ -0,0 +1,95 @@
+package dns
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// rescheduleCh 手动更新或修改配置后通知定时更新重新计算下次运行时间
+var rescheduleCh = make(chan struct{}, 1)
+
+// schedule 每个配置上次运行的时间, 下标与配置相同
+var schedule struct {
+	sync.Mutex
+	lastRun []time.Time
+}
+
+// getInterval 获得配置的更新间隔, 未设置时使用全局的 -f
+func getInterval(dc *config.DnsConfig, delay time.Duration) time.Duration {
+	if dc.Interval > 0 {
+		return time.Duration(dc.Interval) * time.Second
+	}
+	return delay
+}
+
+// maxInterval 获得全部配置中最长的更新间隔, 没有配置时为 delay
+func maxInterval(conf *config.Config, delay time.Duration) time.Duration {
+	longest := delay
+	if len(conf.DnsConf) > 0 {
+		longest = 0
+	}
+	for i := range conf.DnsConf {
+		longest = max(longest, getInterval(&conf.DnsConf[i], delay))
+	}
+	return longest
+}
+
+// logIntervals 输出每个配置的更新间隔
+func logIntervals(conf *config.Config, delay time.Duration) {
+	for i := range conf.DnsConf {
+		dc := &conf.DnsConf[i]
+		domains := append(append([]string{}, dc.Ipv4.Domains...), dc.Ipv6.Domains...)
+		util.Log("第 %s 个配置每 %s 更新一次, 域名: %s", util.Ordinal(i+1, conf.Lang), getInterval(dc, delay), strings.Join(domains, ", "))
+	}
+}
+
+// markRun 记录本次运行的配置, due 为 nil 或配置数量变化时为全部配置
+func markRun(n int, due []bool, now time.Time) []bool {
+	schedule.Lock()
+	defer schedule.Unlock()
+	if len(schedule.lastRun) != n || len(due) != n {
+		schedule.lastRun = make([]time.Time, n)
+		due = nil
+	}
+	for i := range schedule.lastRun {
+		if due == nil || due[i] {
+			schedule.lastRun[i] = now
+		}
+	}
+	return due
+}
+
+// nextSchedule 获得最近的下次运行时间, 没有配置时为 delay 之后
+func nextSchedule(conf *config.Config, delay time.Duration, now time.Time) time.Time {
+	schedule.Lock()
+	defer schedule.Unlock()
+	if len(schedule.lastRun) != len(conf.DnsConf) || len(conf.DnsConf) == 0 {
+		return now.Add(delay)
+	}
+	var next time.Time
+	for i := range conf.DnsConf {
+		t := schedule.lastRun[i].Add(getInterval(&conf.DnsConf[i], delay))
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+	return next
+}
+
+// dueConfigs 获得已到运行时间的配置, 配置数量变化时返回 nil, 即全部运行
+func dueConfigs(conf *config.Config, delay time.Duration, now time.Time) []bool {
+	schedule.Lock()
+	defer schedule.Unlock()
+	if len(schedule.lastRun) != len(conf.DnsConf) {
+		return nil
+	}
+	due := make([]bool, len(conf.DnsConf))
+	for i := range conf.DnsConf {
+		due[i] = !schedule.lastRun[i].Add(getInterval(&conf.DnsConf[i], delay)).After(now)
+	}
+	return due
+}
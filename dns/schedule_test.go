@@ -0,0 +1,59 @@
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// TestSchedule 测试按每个配置的更新间隔计算下次运行时间及到期的配置
+func TestSchedule(t *testing.T) {
+	defer markRun(0, nil, time.Time{})
+
+	delay := 5 * time.Minute
+	conf := &config.Config{DnsConf: []config.DnsConfig{{Interval: 30}, {}}}
+	start := time.Now()
+
+	// 配置数量变化时全部运行
+	if due := dueConfigs(conf, delay, start); due != nil {
+		t.Fatalf("期待全部运行, 得到 %v", due)
+	}
+	markRun(len(conf.DnsConf), nil, start)
+
+	if next := nextSchedule(conf, delay, start); !next.Equal(start.Add(30 * time.Second)) {
+		t.Errorf("期待30秒后运行, 得到 %s", next.Sub(start))
+	}
+
+	now := start.Add(30 * time.Second)
+	due := dueConfigs(conf, delay, now)
+	if len(due) != 2 || !due[0] || due[1] {
+		t.Fatalf("期待只运行第1个配置, 得到 %v", due)
+	}
+	markRun(len(conf.DnsConf), due, now)
+
+	// 第1个配置60秒时再次到期, 第2个配置5分钟时到期
+	if next := nextSchedule(conf, delay, now); !next.Equal(start.Add(time.Minute)) {
+		t.Errorf("期待60秒后运行, 得到 %s", next.Sub(start))
+	}
+	due = dueConfigs(conf, delay, start.Add(delay))
+	if !due[0] || !due[1] {
+		t.Errorf("期待全部到期, 得到 %v", due)
+	}
+}
+
+// TestMaxInterval 测试看门狗使用最长的更新间隔
+func TestMaxInterval(t *testing.T) {
+	delay := 5 * time.Minute
+	if got := maxInterval(&config.Config{}, delay); got != delay {
+		t.Errorf("没有配置时期待 %s, 得到 %s", delay, got)
+	}
+	conf := &config.Config{DnsConf: []config.DnsConfig{{Interval: 1800}, {}}}
+	if got := maxInterval(conf, delay); got != 30*time.Minute {
+		t.Errorf("期待 30m0s, 得到 %s", got)
+	}
+	conf = &config.Config{DnsConf: []config.DnsConfig{{Interval: 30}}}
+	if got := maxInterval(conf, delay); got != 30*time.Second {
+		t.Errorf("期待 30s, 得到 %s", got)
+	}
+}
@@ -0,0 +1,193 @@
+package dns
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const spaceshipEndpoint = "https://spaceship.dev/api/v1/dns/records/"
+
+// Spaceship Spaceship DNS API
+// https://docs.spaceship.dev/#tag/DNS-records
+type Spaceship struct {
+	DNS     config.DNS
+	Domains config.Domains
+	TTL     int
+}
+
+// SpaceshipRecord 记录实体
+type SpaceshipRecord struct {
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	TTL     int    `json:"ttl,omitempty"`
+}
+
+// SpaceshipRecordsResp 记录列表
+type SpaceshipRecordsResp struct {
+	Items []SpaceshipRecord `json:"items"`
+	Total int               `json:"total"`
+}
+
+// SpaceshipError 异常信息, 如 {"detail":"...","data":[{"field":"ttl","details":"..."}]}
+type SpaceshipError struct {
+	Detail string                 `json:"detail"`
+	Data   []SpaceshipErrorDetail `json:"data"`
+}
+
+// SpaceshipErrorDetail 字段的异常信息
+type SpaceshipErrorDetail struct {
+	Field   string `json:"field"`
+	Details string `json:"details"`
+}
+
+// Init 初始化
+func (ss *Spaceship) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	ss.Domains.Ipv4Cache = ipv4cache
+	ss.Domains.Ipv6Cache = ipv6cache
+	ss.DNS = dnsConf.DNS
+	ss.Domains.GetNewIp(dnsConf)
+	if dnsConf.TTL == "" {
+		// 默认600s
+		ss.TTL = 600
+	} else {
+		ttl, err := strconv.Atoi(dnsConf.TTL)
+		if err != nil {
+			ss.TTL = 600
+		} else {
+			ss.TTL = ttl
+		}
+	}
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (ss *Spaceship) AddUpdateDomainRecords() config.Domains {
+	ss.addUpdateDomainRecords("A")
+	ss.addUpdateDomainRecords("AAAA")
+	return ss.Domains
+}
+
+func (ss *Spaceship) addUpdateDomainRecords(recordType string) {
+	ipAddr, domains := ss.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	for _, domain := range domains {
+		zone, name := getSpaceshipZoneName(domain)
+
+		var records SpaceshipRecordsResp
+		err := ss.request(http.MethodGet, spaceshipEndpoint+url.PathEscape(zone)+"?take=500&skip=0", nil, &records)
+		if err != nil {
+			util.Log("查询域名信息发生异常! %s", err)
+			domain.UpdateStatus = config.UpdatedFailed
+			return
+		}
+
+		var old []SpaceshipRecord
+		for _, r := range records.Items {
+			if r.Type == recordType && strings.EqualFold(r.Name, name) {
+				old = append(old, r)
+			}
+		}
+		if len(old) == 1 && old[0].Address == ipAddr {
+			setUpdateResult(domain, ipAddr, resultUnchanged, nil)
+			continue
+		}
+		if len(old) == 0 && skipCreate(domain) {
+			continue
+		}
+		ss.upsert(domain, zone, SpaceshipRecord{Type: recordType, Name: name, Address: ipAddr, TTL: ss.TTL}, old)
+	}
+}
+
+// upsert 添加新的记录后删除旧的记录, 同类型同名称的记录按值区分
+func (ss *Spaceship) upsert(domain *config.Domain, zone string, record SpaceshipRecord, old []SpaceshipRecord) {
+	body := map[string]interface{}{"force": true, "items": []SpaceshipRecord{record}}
+	err := ss.request(http.MethodPut, spaceshipEndpoint+url.PathEscape(zone), body, nil)
+	if err != nil {
+		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	var stale []SpaceshipRecord
+	for _, r := range old {
+		if r.Address != record.Address {
+			stale = append(stale, SpaceshipRecord{Type: r.Type, Name: r.Name, Address: r.Address})
+		}
+	}
+	if len(stale) > 0 {
+		if err := ss.request(http.MethodDelete, spaceshipEndpoint+url.PathEscape(zone), stale, nil); err != nil {
+			util.Log("删除域名解析 %s 失败! 异常信息: %s", domain, err)
+		}
+	}
+
+	util.Log("更新域名解析 %s 成功! IP: %s", domain, record.Address)
+	domain.UpdateStatus = config.UpdatedSuccess
+}
+
+// getSpaceshipZoneName 获得域名所在的区域及相对区域的名称, 根域名为 @
+// 与 Cloudflare 相同, 多级子域名可通过参数 zone 指定
+func getSpaceshipZoneName(domain *config.Domain) (zone string, name string) {
+	zone = getCloudflareZoneName(domain)
+	name = strings.TrimSuffix(domain.ToASCII(), "."+zone)
+	if name == zone {
+		name = "@"
+	}
+	return
+}
+
+// request 统一请求接口, 失败时返回可读的异常信息
+func (ss *Spaceship) request(method string, url string, data interface{}, result interface{}) error {
+	var body io.Reader = http.NoBody
+	if data != nil {
+		byt, _ := json.Marshal(data)
+		body = bytes.NewReader(byt)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-API-Key", ss.DNS.ID)
+	req.Header.Set("X-API-Secret", ss.DNS.Secret)
+	req.Header.Set("Content-Type", "application/json")
+
+	client := createHTTPClient(ss.DNS)
+	resp, err := client.Do(req)
+	byt, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		var spaceshipErr SpaceshipError
+		if resp != nil && json.Unmarshal(byt, &spaceshipErr) == nil && spaceshipErr.Detail != "" {
+			return spaceshipErr.toError(resp.StatusCode)
+		}
+		return err
+	}
+	if result != nil && len(byt) > 0 {
+		return json.Unmarshal(byt, result)
+	}
+	return nil
+}
+
+// toError 转为可读的异常信息, 如 Validation failed: ttl must be between 60 and 3600 (422)
+func (e SpaceshipError) toError(statusCode int) error {
+	msg := e.Detail
+	var details []string
+	for _, d := range e.Data {
+		details = append(details, strings.TrimSpace(d.Field+" "+d.Details))
+	}
+	if len(details) > 0 {
+		msg += ": " + strings.Join(details, ", ")
+	}
+	return fmt.Errorf("%s (%d)", msg, statusCode)
+}
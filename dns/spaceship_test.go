@@ -0,0 +1,35 @@
+package dns
+
+import (
+	"testing"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// TestGetSpaceshipZoneName 测试域名所在的区域及相对区域的名称
+func TestGetSpaceshipZoneName(t *testing.T) {
+	tests := []struct {
+		domain config.Domain
+		zone   string
+		name   string
+	}{
+		{config.Domain{DomainName: "example.com"}, "example.com", "@"},
+		{config.Domain{DomainName: "example.com", SubDomain: "www"}, "example.com", "www"},
+		{config.Domain{DomainName: "b.example.com", SubDomain: "a", CustomParams: "zone=example.com"}, "example.com", "a.b"},
+	}
+	for _, tt := range tests {
+		zone, name := getSpaceshipZoneName(&tt.domain)
+		if zone != tt.zone || name != tt.name {
+			t.Errorf("%s 期待 %s %s, 得到 %s %s", tt.domain, tt.zone, tt.name, zone, name)
+		}
+	}
+}
+
+// TestSpaceshipError 测试异常信息
+func TestSpaceshipError(t *testing.T) {
+	e := SpaceshipError{Detail: "Validation failed", Data: []SpaceshipErrorDetail{{"ttl", "must be between 60 and 3600"}}}
+	want := "Validation failed: ttl must be between 60 and 3600 (422)"
+	if got := e.toError(422).Error(); got != want {
+		t.Errorf("期待 %s, 得到 %s", want, got)
+	}
+}
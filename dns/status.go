@@ -0,0 +1,192 @@
+package dns
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// 最近一次运行的结果
+const (
+	ResultSuccess = "success"
+	ResultFailed  = "failed"
+	ResultNothing = "nothing"
+	// 部分失败, 如 IPv4 更新成功而 IPv6 更新失败
+	ResultPartial = "partial"
+	ResultPaused  = "paused"
+	// 维护时段内
+	ResultMaintenance = "maintenance"
+	// 首次运行为试运行
+	ResultDryRun = "dryrun"
+	// 上一次更新尚未完成, 将在完成后更新全部配置, 仅用于 /api/update/{index} 的返回
+	ResultQueued = "queued"
+	// 多个配置的结果来源不同
+	ProvenanceMixed = "mixed"
+)
+
+// Status 运行状态
+type Status struct {
+	LastRun    time.Time // 最近一次运行时间
+	LastResult string    // 最近一次运行结果
+	// 最近一次运行 IPv4/IPv6 各自的结果, 暂停等未更新时为空
+	LastIpv4Result string
+	LastIpv6Result string
+	// 最近一次运行 IPv4/IPv6 的结果来源, 多个配置来源不同时为 mixed
+	LastIpv4Provenance string
+	LastIpv6Provenance string
+	NextRun            time.Time // 下次运行时间
+}
+
+var status struct {
+	sync.Mutex
+	Status
+}
+
+// GetStatus 获得运行状态
+func GetStatus() Status {
+	status.Lock()
+	defer status.Unlock()
+	return status.Status
+}
+
+// setLastRun 记录最近一次运行
+func setLastRun(start time.Time, result string) {
+	status.Lock()
+	defer status.Unlock()
+	status.LastRun = start
+	status.LastResult = result
+	status.LastIpv4Result = ""
+	status.LastIpv6Result = ""
+	status.LastIpv4Provenance = ""
+	status.LastIpv6Provenance = ""
+}
+
+// setLastRunSummary 按汇总记录最近一次运行, IPv4/IPv6 的结果分别记录
+func setLastRunSummary(start time.Time, s *cycleSummary) {
+	status.Lock()
+	defer status.Unlock()
+	status.LastRun = start
+	status.LastResult = s.result()
+	status.LastIpv4Result = s.ipv4Result.result()
+	status.LastIpv6Result = s.ipv6Result.result()
+	status.LastIpv4Provenance = provenance(s.ipv4Provenance)
+	status.LastIpv6Provenance = provenance(s.ipv6Provenance)
+}
+
+// setNextRun 记录下次运行时间
+func setNextRun(next time.Time) {
+	status.Lock()
+	defer status.Unlock()
+	status.NextRun = next
+}
+
+// resultTally 更新成功及失败的域名数量
+type resultTally struct {
+	changed, failed int
+	// noIP 未能获取到IP的配置数量, 计为失败
+	noIP int
+}
+
+// result 有成功也有失败时为部分失败, 失败不会掩盖成功的结果
+func (t resultTally) result() string {
+	failed := t.failed > 0 || t.noIP > 0
+	switch {
+	case t.changed > 0 && failed:
+		return ResultPartial
+	case failed:
+		return ResultFailed
+	case t.changed > 0:
+		return ResultSuccess
+	default:
+		return ResultNothing
+	}
+}
+
+// cycleSummary 单次运行的汇总
+type cycleSummary struct {
+	total, changed, unchanged, failed int
+	ipv4, ipv6                        []string
+	// IPv4/IPv6 分别汇总
+	ipv4Result, ipv6Result resultTally
+	// IPv4/IPv6 的结果来源, 见 util.IPFresh 等
+	ipv4Provenance, ipv6Provenance []string
+}
+
+// add 汇总单个配置的更新结果
+func (s *cycleSummary) add(domains *config.Domains) {
+	count := func(ds []*config.Domain, tally *resultTally) {
+		for _, d := range ds {
+			s.total++
+			switch d.UpdateStatus {
+			case config.UpdatedSuccess:
+				s.changed++
+				tally.changed++
+			case config.UpdatedFailed:
+				s.failed++
+				tally.failed++
+			default:
+				s.unchanged++
+			}
+		}
+	}
+	count(domains.Ipv4Domains, &s.ipv4Result)
+	count(domains.Ipv6Domains, &s.ipv6Result)
+	if domains.Ipv4NoIP {
+		s.ipv4Result.noIP++
+	}
+	if domains.Ipv6NoIP {
+		s.ipv6Result.noIP++
+	}
+
+	addProvenance := func(list *[]string, cache *util.IpCache, ds []*config.Domain) {
+		if cache != nil && cache.Provenance != "" && len(ds) > 0 && !slices.Contains(*list, cache.Provenance) {
+			*list = append(*list, cache.Provenance)
+		}
+	}
+	addProvenance(&s.ipv4Provenance, domains.Ipv4Cache, domains.Ipv4Domains)
+	addProvenance(&s.ipv6Provenance, domains.Ipv6Cache, domains.Ipv6Domains)
+
+	if domains.Ipv4Addr != "" && !slices.Contains(s.ipv4, domains.Ipv4Addr) {
+		s.ipv4 = append(s.ipv4, domains.Ipv4Addr)
+	}
+	if domains.Ipv6Addr != "" && !slices.Contains(s.ipv6, domains.Ipv6Addr) {
+		s.ipv6 = append(s.ipv6, domains.Ipv6Addr)
+	}
+}
+
+// result 本次运行的结果
+func (s *cycleSummary) result() string {
+	return resultTally{changed: s.changed, failed: s.failed, noIP: s.ipv4Result.noIP + s.ipv6Result.noIP}.result()
+}
+
+// provenance 汇总结果来源, 来源不同时为 mixed
+func provenance(list []string) string {
+	switch len(list) {
+	case 0:
+		return ""
+	case 1:
+		return list[0]
+	default:
+		return ProvenanceMixed
+	}
+}
+
+// log 输出汇总日志, 部分失败时分别输出 IPv4/IPv6 的结果
+func (s *cycleSummary) log() {
+	// 附加结果来源, 如 ipv4=1.1.1.1(cached)
+	addrs := func(list []string, provenance []string) string {
+		if len(provenance) == 0 {
+			return strings.Join(list, ",")
+		}
+		return strings.Join(list, ",") + "(" + strings.Join(provenance, ",") + ")"
+	}
+	util.Log("本次运行完成: %d 个域名, %d 个已更新, %d 个未改变, %d 个失败, ipv4=%s, ipv6=%s",
+		s.total, s.changed, s.unchanged, s.failed, addrs(s.ipv4, s.ipv4Provenance), addrs(s.ipv6, s.ipv6Provenance))
+	if s.result() == ResultPartial {
+		util.Log("部分域名更新失败, IPv4: %s, IPv6: %s", s.ipv4Result.result(), s.ipv6Result.result())
+	}
+}
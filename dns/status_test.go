@@ -0,0 +1,116 @@
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// TestCycleSummary 测试单次运行的汇总
+func TestCycleSummary(t *testing.T) {
+	var s cycleSummary
+	s.add(&config.Domains{
+		Ipv4Addr: "1.1.1.1",
+		Ipv4Domains: []*config.Domain{
+			{UpdateStatus: config.UpdatedSuccess},
+			{UpdateStatus: config.UpdatedNothing},
+			{},
+		},
+		Ipv6Addr:    "2001:db8::1",
+		Ipv6Domains: []*config.Domain{{UpdateStatus: config.UpdatedFailed}},
+	})
+	s.add(&config.Domains{
+		Ipv4Addr:    "1.1.1.1",
+		Ipv4Domains: []*config.Domain{{UpdateStatus: config.UpdatedSuccess}},
+	})
+
+	if s.total != 5 || s.changed != 2 || s.unchanged != 2 || s.failed != 1 {
+		t.Errorf("汇总不正确: %+v", s)
+	}
+	if len(s.ipv4) != 1 || len(s.ipv6) != 1 {
+		t.Errorf("期待IP去重, 得到 ipv4=%v, ipv6=%v", s.ipv4, s.ipv6)
+	}
+	// IPv6 失败不会掩盖 IPv4 的成功
+	if s.result() != ResultPartial || s.ipv4Result.result() != ResultSuccess || s.ipv6Result.result() != ResultFailed {
+		t.Errorf("期待 partial/success/failed, 得到 %s/%s/%s", s.result(), s.ipv4Result.result(), s.ipv6Result.result())
+	}
+}
+
+// TestResultTally 测试运行结果
+func TestResultTally(t *testing.T) {
+	data := []struct {
+		tally    resultTally
+		expected string
+	}{
+		{resultTally{}, ResultNothing},
+		{resultTally{changed: 1}, ResultSuccess},
+		{resultTally{failed: 1}, ResultFailed},
+		{resultTally{changed: 1, failed: 1}, ResultPartial},
+		{resultTally{noIP: 1}, ResultFailed},
+		{resultTally{changed: 1, noIP: 1}, ResultPartial},
+	}
+	for _, d := range data {
+		if got := d.tally.result(); got != d.expected {
+			t.Errorf("%+v 期待 %s, 得到 %s", d.tally, d.expected, got)
+		}
+	}
+}
+
+// TestCycleSummaryProvenance 测试汇总结果来源, 多个配置来源不同时为 mixed
+func TestCycleSummaryProvenance(t *testing.T) {
+	var s cycleSummary
+	s.add(&config.Domains{
+		Ipv4Addr:    "1.1.1.1",
+		Ipv4Cache:   &util.IpCache{Provenance: util.IPCached},
+		Ipv4Domains: []*config.Domain{{}},
+		Ipv6Cache:   &util.IpCache{Provenance: util.IPFresh},
+	})
+	s.add(&config.Domains{
+		Ipv4Addr:    "1.1.1.1",
+		Ipv4Cache:   &util.IpCache{Provenance: util.IPCached},
+		Ipv4Domains: []*config.Domain{{}},
+	})
+	if p := provenance(s.ipv4Provenance); p != util.IPCached {
+		t.Errorf("期待 %s, 得到 %s", util.IPCached, p)
+	}
+	// 没有域名时不计入
+	if p := provenance(s.ipv6Provenance); p != "" {
+		t.Errorf("期待为空, 得到 %s", p)
+	}
+
+	s.add(&config.Domains{
+		Ipv4Cache:   &util.IpCache{Provenance: util.IPFresh},
+		Ipv4Domains: []*config.Domain{{}},
+	})
+	if p := provenance(s.ipv4Provenance); p != ProvenanceMixed {
+		t.Errorf("期待 %s, 得到 %s", ProvenanceMixed, p)
+	}
+}
+
+// TestCycleSummaryNoIP 测试启用并填写了域名但未能获取到IP时本次运行计为失败, -once 以失败退出
+func TestCycleSummaryNoIP(t *testing.T) {
+	dc := &config.DnsConfig{}
+	dc.Ipv4.Enable = true
+	dc.Ipv4.GetType = "cmd"
+	dc.Ipv4.Domains = []string{"example.com"}
+	domains := config.Domains{Ipv4Cache: &util.IpCache{}, Ipv6Cache: &util.IpCache{}}
+	domains.GetNewIp(dc)
+	if !domains.Ipv4NoIP || domains.Ipv4Domains[0].UpdateStatus == config.UpdatedFailed {
+		t.Fatalf("期待未能获取IP且第一次不标记域名失败, 得到 %v %s", domains.Ipv4NoIP, domains.Ipv4Domains[0].UpdateStatus)
+	}
+
+	var s cycleSummary
+	s.add(&domains)
+	setLastRunSummary(time.Now(), &s)
+	if got := GetStatus().LastResult; got != ResultFailed {
+		t.Errorf("期待 %s, 得到 %s", ResultFailed, got)
+	}
+
+	// 其他配置更新成功时为部分失败
+	s.add(&config.Domains{Ipv6Addr: "2001:db8::1", Ipv6Domains: []*config.Domain{{UpdateStatus: config.UpdatedSuccess}}})
+	if s.result() != ResultPartial || s.ipv4Result.result() != ResultFailed {
+		t.Errorf("期待 partial/failed, 得到 %s/%s", s.result(), s.ipv4Result.result())
+	}
+}
@@ -38,6 +38,10 @@ type TencentCloudRecord struct {
 	RecordId int `json:"RecordId,omitempty"`
 	// DescribeRecordList 不需要 TTL
 	TTL int `json:"TTL,omitempty"`
+	// 备注, DescribeRecordList 不需要 Remark
+	Remark string `json:"Remark,omitempty"`
+	// 权重, 未设置时为 null
+	Weight *int `json:"Weight,omitempty"`
 }
 
 // TencentCloudRecordListsResp 获取域名的解析记录列表返回结果
@@ -127,6 +131,9 @@ func (tc *TencentCloud) addUpdateDomainRecords(recordType string) {
 // create 添加记录
 // CreateRecord https://cloud.tencent.com/document/api/1427/56180
 func (tc *TencentCloud) create(domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	record := &TencentCloudRecord{
 		Domain:     domain.DomainName,
 		SubDomain:  domain.GetSubDomain(),
@@ -134,6 +141,10 @@ func (tc *TencentCloud) create(domain *config.Domain, recordType string, ipAddr
 		RecordLine: tc.getRecordLine(domain),
 		Value:      ipAddr,
 		TTL:        tc.TTL,
+		Remark:     domain.Comment,
+	}
+	if weight, ok := domain.GetWeight(); ok {
+		record.Weight = &weight
 	}
 
 	var status TencentCloudStatus
@@ -161,9 +172,10 @@ func (tc *TencentCloud) create(domain *config.Domain, recordType string, ipAddr
 // modify 修改记录
 // ModifyRecord https://cloud.tencent.com/document/api/1427/56157
 func (tc *TencentCloud) modify(record TencentCloudRecord, domain *config.Domain, recordType string, ipAddr string) {
-	// 相同不修改
-	if record.Value == ipAddr {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+	// IP及权重相同不修改
+	weight, hasWeight := domain.GetWeight()
+	if record.Value == ipAddr && (!hasWeight || (record.Weight != nil && *record.Weight == weight)) {
+		setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 		return
 	}
 	var status TencentCloudStatus
@@ -173,6 +185,12 @@ func (tc *TencentCloud) modify(record TencentCloudRecord, domain *config.Domain,
 	record.RecordLine = tc.getRecordLine(domain)
 	record.Value = ipAddr
 	record.TTL = tc.TTL
+	if domain.Comment != "" {
+		record.Remark = domain.Comment
+	}
+	if hasWeight {
+		record.Weight = &weight
+	}
 	err := tc.request(
 		"ModifyRecord",
 		record,
@@ -240,7 +258,7 @@ func (tc *TencentCloud) request(action string, data interface{}, result interfac
 
 	util.TencentCloudSigner(tc.DNS.ID, tc.DNS.Secret, req, action, string(jsonStr))
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(tc.DNS)
 	resp, err := client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
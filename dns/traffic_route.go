@@ -177,6 +177,9 @@ func (tr *TrafficRoute) addUpdateDomainRecords(recordType string) {
 // create 添加记录
 // CreateRecord https://www.volcengine.com/docs/6758/155104
 func (tr *TrafficRoute) create(zoneID int, domain *config.Domain, recordType string, ipAddr string) {
+	if skipCreate(domain) {
+		return
+	}
 	record := &TrafficRouteMeta{
 		ZID:   zoneID,
 		Host:  domain.GetSubDomain(),
@@ -213,7 +216,7 @@ func (tr *TrafficRoute) create(zoneID int, domain *config.Domain, recordType str
 func (tr *TrafficRoute) modify(record TrafficRouteMeta, zoneID int, domain *config.Domain, recordType string, ipAddr string) {
 	// 相同不修改
 	if (record.Value == ipAddr) && (record.Host == domain.GetSubDomain()) {
-		util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
+		setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 		return
 	}
 	var status TrafficRouteStatus
@@ -287,7 +290,7 @@ func (tr *TrafficRoute) request(method string, action string, data interface{},
 		return err
 	}
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(tr.DNS)
 	resp, err := client.Do(req)
 	err = util.GetHTTPResponse(resp, err, result)
 
@@ -89,11 +89,13 @@ func (v *Vercel) addUpdateDomainRecords(recordType string) {
 		}
 
 		if targetRecord == nil {
+			if skipCreate(domain) {
+				continue
+			}
 			err = v.createRecord(domain, recordType, ipAddr)
 		} else {
 			if strings.ToLower(targetRecord.Value) == ipAddr {
-				util.Log("你的IP %s 没有变化, 域名 %s", ipAddr, domain)
-				domain.UpdateStatus = config.UpdatedNothing
+				setUpdateResult(domain, ipAddr, resultUnchanged, nil)
 				continue
 			} else {
 				err = v.updateRecord(targetRecord, recordType, ipAddr)
@@ -161,7 +163,7 @@ func (v *Vercel) request(method, api string, data, result interface{}) (err erro
 	req.Header.Set("Authorization", "Bearer "+v.DNS.Secret)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := util.CreateHTTPClient()
+	client := createHTTPClient(v.DNS)
 	resp, err := client.Do(req)
 	if err != nil {
 		return err
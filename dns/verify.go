@@ -0,0 +1,86 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// 验证记录是否生效的超时时间及重试间隔
+var (
+	verifyTimeout  = 30 * time.Second
+	verifyInterval = 5 * time.Second
+)
+
+// verifyTarget 待验证的域名
+type verifyTarget struct {
+	domain  *config.Domain
+	network string
+	ipAddr  string
+	answer  []string
+}
+
+// verifyPropagation 使用配置的DNS服务器查询更新成功的域名, 超时后仍未解析到新IP则输出日志
+// 只输出日志, 不修改更新状态
+func verifyPropagation(dc *config.DnsConfig, domains *config.Domains) {
+	if dc.VerifyDNS == "" {
+		return
+	}
+
+	var pending []*verifyTarget
+	for _, domain := range domains.Ipv4Domains {
+		if domain.UpdateStatus == config.UpdatedSuccess {
+			pending = append(pending, &verifyTarget{domain: domain, network: "ip4", ipAddr: domains.Ipv4Addr})
+		}
+	}
+	for _, domain := range domains.Ipv6Domains {
+		if domain.UpdateStatus == config.UpdatedSuccess {
+			pending = append(pending, &verifyTarget{domain: domain, network: "ip6", ipAddr: domains.Ipv6Addr})
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	resolver := util.NewResolver(dc.VerifyDNS)
+	deadline := time.Now().Add(verifyTimeout)
+	for {
+		pending = slices.DeleteFunc(pending, func(target *verifyTarget) bool {
+			target.answer = lookupVerifyTarget(resolver, target)
+			if slices.Contains(target.answer, target.ipAddr) {
+				util.Log("域名 %s 已生效, 解析到 %s", target.domain, target.ipAddr)
+				return true
+			}
+			return false
+		})
+		if len(pending) == 0 || time.Now().Add(verifyInterval).After(deadline) {
+			break
+		}
+		time.Sleep(verifyInterval)
+	}
+
+	for _, target := range pending {
+		util.Log("域名 %s 在 %s 内未生效, DNS服务器 %s 返回: %s", target.domain, verifyTimeout, dc.VerifyDNS, strings.Join(target.answer, ","))
+	}
+}
+
+// lookupVerifyTarget 查询域名的 A/AAAA 记录
+func lookupVerifyTarget(resolver *net.Resolver, target *verifyTarget) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), verifyInterval)
+	defer cancel()
+
+	addrs, err := resolver.LookupNetIP(ctx, target.network, target.domain.ToASCII())
+	if err != nil {
+		return []string{err.Error()}
+	}
+	answer := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		answer = append(answer, addr.Unmap().String())
+	}
+	return answer
+}
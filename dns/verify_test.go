@@ -0,0 +1,75 @@
+package dns
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// startVerifyDNSServer 启动返回固定 A 记录的 UDP DNS 服务器, 返回地址
+func startVerifyDNSServer(t *testing.T, a [4]byte) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var query dnsmessage.Message
+			if query.Unpack(buf[:n]) != nil || len(query.Questions) != 1 {
+				continue
+			}
+			resp := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: query.ID, Response: true, RCode: dnsmessage.RCodeSuccess},
+				Questions: query.Questions,
+			}
+			q := query.Questions[0]
+			if q.Type == dnsmessage.TypeA {
+				resp.Answers = []dnsmessage.Resource{{
+					Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: q.Class, TTL: 60},
+					Body:   &dnsmessage.AResource{A: a},
+				}}
+			}
+			packed, _ := resp.Pack()
+			conn.WriteTo(packed, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+// TestLookupVerifyTarget 测试验证记录时查询指定的DNS服务器
+func TestLookupVerifyTarget(t *testing.T) {
+	oldTimeout, oldInterval := verifyTimeout, verifyInterval
+	verifyTimeout, verifyInterval = 200*time.Millisecond, 100*time.Millisecond
+	defer func() { verifyTimeout, verifyInterval = oldTimeout, oldInterval }()
+
+	addr := startVerifyDNSServer(t, [4]byte{192, 0, 2, 1})
+	dc := &config.DnsConfig{VerifyDNS: addr}
+	resolver := util.NewResolver(dc.VerifyDNS)
+
+	target := &verifyTarget{
+		domain:  &config.Domain{DomainName: "example.com", SubDomain: "www"},
+		network: "ip4",
+		ipAddr:  "192.0.2.1",
+	}
+	if answer := lookupVerifyTarget(resolver, target); len(answer) != 1 || answer[0] != "192.0.2.1" {
+		t.Errorf("期待 192.0.2.1, 得到 %v", answer)
+	}
+
+	// 未生效时等待超时后返回, 不修改更新状态
+	domain := &config.Domain{DomainName: "example.com", SubDomain: "www", UpdateStatus: config.UpdatedSuccess}
+	verifyPropagation(dc, &config.Domains{Ipv4Addr: "192.0.2.2", Ipv4Domains: []*config.Domain{domain}})
+	if domain.UpdateStatus != config.UpdatedSuccess {
+		t.Errorf("期待更新状态不变, 得到 %s", domain.UpdateStatus)
+	}
+}
@@ -0,0 +1,91 @@
+package dns
+
+import (
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// 看门狗发现更新停滞后的处理方式
+const (
+	// WatchdogLog 仅记录日志
+	WatchdogLog = "log"
+	// WatchdogRestart 重新启动定时更新
+	WatchdogRestart = "restart"
+	// WatchdogExit 退出程序, 由 systemd/docker 等重启
+	WatchdogExit = "exit"
+)
+
+// lastDone 最近一次完成更新的时间, 单位纳秒
+var lastDone atomic.Int64
+
+// loopGeneration 定时更新的代数, 重新启动后旧的循环自动退出
+var loopGeneration atomic.Int64
+
+// markDone 记录完成一次更新
+func markDone() {
+	lastDone.Store(time.Now().UnixNano())
+}
+
+// runLoop 按每个配置的更新间隔定时更新, 未设置时每隔 delay 更新一次, 直到被重新启动
+func runLoop(delay time.Duration) {
+	generation := loopGeneration.Add(1)
+	for {
+		conf, _ := config.GetConfigCached()
+		next := nextSchedule(&conf, delay, time.Now())
+		setNextRun(next)
+
+		timer := time.NewTimer(time.Until(next))
+		rescheduled := false
+		select {
+		case <-timer.C:
+		case <-rescheduleCh:
+			timer.Stop()
+			rescheduled = true
+		}
+		if loopGeneration.Load() != generation {
+			return
+		}
+		if rescheduled {
+			continue
+		}
+		conf, _ = config.GetConfigCached()
+		runConfigs(dueConfigs(&conf, delay, time.Now()), false, "")
+	}
+}
+
+// RunWatchdog 超过 times 倍的更新间隔仍未完成更新时按 action 处理, times 为0时不启用
+// 配置单独设置了更新间隔时, 使用最长的更新间隔, 以免误判
+func RunWatchdog(delay time.Duration, times int, action string) {
+	if times <= 0 {
+		return
+	}
+	markDone()
+	for {
+		time.Sleep(delay)
+		conf, _ := config.GetConfigCached()
+		threshold := maxInterval(&conf, delay) * time.Duration(times)
+		elapsed := time.Since(time.Unix(0, lastDone.Load()))
+		if elapsed <= threshold {
+			continue
+		}
+
+		util.Log("[看门狗] 已超过 %s 未完成更新, 更新可能已停滞!", elapsed.Round(time.Second))
+		switch action {
+		case WatchdogRestart:
+			util.Log("[看门狗] 重新启动定时更新")
+			markDone()
+			// 停滞的更新仍在运行时不同时更新, 在其完成后再更新
+			go func() {
+				RunOnce()
+				runLoop(delay)
+			}()
+		case WatchdogExit:
+			util.Log("[看门狗] 退出程序")
+			os.Exit(1)
+		}
+	}
+}
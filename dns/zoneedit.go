@@ -0,0 +1,142 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+const zoneEditEndpoint = "https://dynamic.zoneedit.com/auth/dynamic.html"
+
+// zoneEditCodeDuplicate 与上次更新的IP相同
+const zoneEditCodeDuplicate = "707"
+
+var (
+	// zoneEditResultReg 匹配返回值, 如 <SUCCESS CODE="200" TEXT="Update succeeded." ZONE="example.com">
+	zoneEditResultReg = regexp.MustCompile(`<(SUCCESS|ERROR)\b([^>]*)>`)
+	// zoneEditAttrReg 匹配返回值的属性, 如 CODE="200"
+	zoneEditAttrReg = regexp.MustCompile(`(\w+)="([^"]*)"`)
+)
+
+// ZoneEdit ZoneEdit 动态DNS
+// https://support.zoneedit.com/en/knowledgebase/article/dynamic-dns
+type ZoneEdit struct {
+	DNS      config.DNS
+	Domains  config.Domains
+	lastIpv4 string
+	lastIpv6 string
+}
+
+// ZoneEditResult 返回值
+type ZoneEditResult struct {
+	Success bool
+	Code    string
+	Text    string
+}
+
+// Init 初始化
+func (ze *ZoneEdit) Init(dnsConf *config.DnsConfig, ipv4cache *util.IpCache, ipv6cache *util.IpCache) {
+	ze.Domains.Ipv4Cache = ipv4cache
+	ze.Domains.Ipv6Cache = ipv6cache
+	ze.lastIpv4 = ipv4cache.Addr
+	ze.lastIpv6 = ipv6cache.Addr
+
+	ze.DNS = dnsConf.DNS
+	ze.Domains.GetNewIp(dnsConf)
+}
+
+// AddUpdateDomainRecords 添加或更新IPv4/IPv6记录
+func (ze *ZoneEdit) AddUpdateDomainRecords() config.Domains {
+	ze.addUpdateDomainRecords("A")
+	ze.addUpdateDomainRecords("AAAA")
+	return ze.Domains
+}
+
+func (ze *ZoneEdit) addUpdateDomainRecords(recordType string) {
+	ipAddr, domains := ze.Domains.GetNewIpResult(recordType)
+
+	if ipAddr == "" {
+		return
+	}
+
+	// 无法查询记录, 且频繁提交相同的IP会被拒绝
+	if recordType == "A" {
+		if ze.lastIpv4 == ipAddr {
+			util.Log("你的IPv4未变化, 未触发 %s 请求", "ZoneEdit")
+			return
+		}
+	} else {
+		if ze.lastIpv6 == ipAddr {
+			util.Log("你的IPv6未变化, 未触发 %s 请求", "ZoneEdit")
+			return
+		}
+	}
+
+	for _, domain := range domains {
+		ze.modify(domain, ipAddr)
+	}
+}
+
+// 修改
+func (ze *ZoneEdit) modify(domain *config.Domain, ipAddr string) {
+	result, err := ze.request(domain, ipAddr)
+	if err != nil {
+		util.Log("更新域名解析 %s 失败! 异常信息: %s", domain, err)
+		domain.UpdateStatus = config.UpdatedFailed
+		return
+	}
+
+	switch {
+	case result.Success:
+		setUpdateResult(domain, ipAddr, resultUpdated, nil)
+	case result.Code == zoneEditCodeDuplicate:
+		setUpdateResult(domain, ipAddr, resultUnchanged, nil)
+	default:
+		setUpdateResult(domain, ipAddr, resultFailed, fmt.Errorf("%s (%s)", result.Text, result.Code))
+	}
+}
+
+// request 统一请求接口, 用户名及动态DNS令牌使用 HTTP Basic 认证
+func (ze *ZoneEdit) request(domain *config.Domain, ipAddr string) (result ZoneEditResult, err error) {
+	query := url.Values{}
+	query.Set("host", domain.ToASCII())
+	query.Set("dnsto", ipAddr)
+
+	req, err := http.NewRequest(http.MethodGet, zoneEditEndpoint+"?"+query.Encode(), http.NoBody)
+	if err != nil {
+		return
+	}
+	req.SetBasicAuth(ze.DNS.ID, ze.DNS.Secret)
+
+	client := createHTTPClient(ze.DNS)
+	resp, err := client.Do(req)
+	body, err := util.GetHTTPResponseOrg(resp, err)
+	if err != nil {
+		return
+	}
+	return parseZoneEditResult(string(body))
+}
+
+// parseZoneEditResult 解析返回值, 如 <ERROR CODE="707" TEXT="Duplicate updates for the same host/ip">
+func parseZoneEditResult(body string) (result ZoneEditResult, err error) {
+	m := zoneEditResultReg.FindStringSubmatch(body)
+	if m == nil {
+		return result, errors.New(util.LogStr("返回内容: %s", strings.TrimSpace(body)))
+	}
+	result.Success = m[1] == "SUCCESS"
+	for _, attr := range zoneEditAttrReg.FindAllStringSubmatch(m[2], -1) {
+		switch strings.ToUpper(attr[1]) {
+		case "CODE":
+			result.Code = attr[2]
+		case "TEXT":
+			result.Text = attr[2]
+		}
+	}
+	return
+}
@@ -0,0 +1,24 @@
+package dns
+
+import "testing"
+
+// TestParseZoneEditResult 测试解析 ZoneEdit 的返回值
+func TestParseZoneEditResult(t *testing.T) {
+	tests := []struct {
+		body    string
+		success bool
+		code    string
+		text    string
+		err     bool
+	}{
+		{`<SUCCESS CODE="200" TEXT="Update succeeded." ZONE="example.com" HOST="www.example.com" IP="1.2.3.4">`, true, "200", "Update succeeded.", false},
+		{`<ERROR CODE="707" TEXT="Duplicate updates for the same host/ip, adjust client settings" ZONE="example.com" HOST="www.example.com">`, false, "707", "Duplicate updates for the same host/ip, adjust client settings", false},
+		{"<html>Unauthorized</html>", false, "", "", true},
+	}
+	for _, tt := range tests {
+		result, err := parseZoneEditResult(tt.body)
+		if (err != nil) != tt.err || result.Success != tt.success || result.Code != tt.code || result.Text != tt.text {
+			t.Errorf("解析 %s 期待 %v %s %s %v, 得到 %+v %v", tt.body, tt.success, tt.code, tt.text, tt.err, result, err)
+		}
+	}
+}
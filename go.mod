@@ -4,12 +4,21 @@ go 1.23.6
 
 require (
 	github.com/kardianos/service v1.2.2
+	github.com/quic-go/quic-go v0.54.1
 	github.com/wagslane/go-password-validator v0.3.0
 	golang.org/x/crypto v0.36.0
 	golang.org/x/net v0.37.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
+require (
+	github.com/quic-go/qpack v0.5.1 // indirect
+	go.uber.org/mock v0.5.0 // indirect
+	golang.org/x/mod v0.18.0 // indirect
+	golang.org/x/sync v0.12.0 // indirect
+	golang.org/x/tools v0.22.0 // indirect
+)
+
 require (
 	golang.org/x/sys v0.31.0 // indirect
 	golang.org/x/text v0.23.0
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"crypto/tls"
 	"embed"
 	"errors"
 	"flag"
@@ -10,9 +11,13 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/jeessy2/ddns-go/v6/config"
@@ -21,6 +26,8 @@ import (
 	"github.com/jeessy2/ddns-go/v6/util/update"
 	"github.com/jeessy2/ddns-go/v6/web"
 	"github.com/kardianos/service"
+	"github.com/quic-go/quic-go/http3"
+	"gopkg.in/yaml.v3"
 )
 
 // ddns-go 版本
@@ -30,8 +37,52 @@ var versionFlag = flag.Bool("v", false, "ddns-go version")
 // 更新 ddns-go
 var updateFlag = flag.Bool("u", false, "Upgrade ddns-go to the latest version")
 
-// 监听地址
-var listen = flag.String("l", ":9876", "Listen address")
+// 监听地址, 可多次指定或以逗号分隔
+var listen = &listenAddrs{addrs: []string{":9876"}}
+
+func init() {
+	flag.Var(listen, "l", "Listen address, can be repeated or comma-separated, example: -l :9876 -l 127.0.0.1:9877")
+}
+
+// listenAddrs 多个监听地址, 指定后替换默认值
+type listenAddrs struct {
+	addrs []string
+	set   bool
+}
+
+func (l *listenAddrs) String() string {
+	return strings.Join(l.addrs, ",")
+}
+
+func (l *listenAddrs) Set(value string) error {
+	if !l.set {
+		l.addrs = nil
+		l.set = true
+	}
+	for _, addr := range strings.Split(value, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			l.addrs = append(l.addrs, addr)
+		}
+	}
+	return nil
+}
+
+// web服务, 所有监听地址共用
+var webServer = &http.Server{}
+
+// HTTPS 证书, 同时指定时启用 HTTPS 及 HTTP/2
+var tlsCert = flag.String("tlsCert", "", "TLS certificate file, enables HTTPS and HTTP/2 together with -tlsKey")
+var tlsKey = flag.String("tlsKey", "", "TLS private key file")
+
+// 同时在监听地址的UDP端口提供 HTTP/3(QUIC), 需启用 HTTPS
+var enableHTTP3 = flag.Bool("http3", false, "Also serve HTTP/3 (QUIC) on the UDP port of each listen address, requires -tlsCert/-tlsKey or -tlsAutocert")
+
+// HTTP/3 服务, 每个监听地址一个
+var http3Servers []*http3.Server
+
+// 自动申请 Let's Encrypt 证书的域名及缓存目录, 同时指定 -tlsCert 时申请失败使用该证书
+var tlsAutocert = flag.String("tlsAutocert", "", "Obtain and renew certificates from Let's Encrypt for these hostnames (comma separated) using the HTTP-01 challenge on the listen port")
+var tlsAutocertDir = flag.String("tlsAutocertDir", "", "Autocert cache directory, defaults to autocert next to the config file")
 
 // 更新频率(秒)
 var every = flag.Int("f", 300, "Update frequency(seconds)")
@@ -48,15 +99,93 @@ var configFilePath = flag.String("c", util.GetConfigFilePathDefault(), "Custom c
 // Web 服务
 var noWebService = flag.Bool("noweb", false, "No web service")
 
+// 不自动打开浏览器
+var noBrowser = flag.Bool("nobrowser", false, "Do not open the browser automatically when there is no config")
+
+// 打印二维码
+var showQR = flag.Bool("qr", false, "Print a QR code of the web UI address at startup for mobile access")
+
 // 跳过验证证书
 var skipVerify = flag.Bool("skipVerify", false, "Skip certificate verification")
 
+// 自定义的CA证书, 用于企业代理等自签名证书的环境
+var caCert = flag.String("caCert", "", "Trust a custom CA certificate (file or inline PEM) in addition to the system roots")
+
 // 自定义 DNS 服务器
-var customDNS = flag.String("dns", "", "Custom DNS server address, example: 8.8.8.8")
+var customDNS = flag.String("dns", "", "Custom DNS server address, example: 8.8.8.8, tls://1.1.1.1 (DoT), https://1.1.1.1/dns-query (DoH)")
+
+// 获取IP接口的超时时间
+var ipURLTimeout = flag.Int("ipTimeout", 10, "Timeout for each IP API (seconds)")
+
+// 等待网络连接
+var waitInternet = flag.Int("waitInternet", 0, "Max seconds to wait for the network at startup before continuing anyway, 0 to wait indefinitely")
+var skipWaitInternet = flag.Bool("skipWaitInternet", false, "Do not wait for the network at startup")
+
+// 首次更新前的等待时间
+var startDelay = flag.Int("startDelay", 0, "Seconds to wait before the first update after startup, so the network can settle")
+
+// 看门狗
+var watchdog = flag.Int("watchdog", 0, "Warn when no update completes within N times the update frequency, 0 to disable")
+var watchdogAction = flag.String("watchdogAction", dns.WatchdogLog, "Watchdog action (log|restart|exit)")
+
+// 推送指标到 Pushgateway
+var pushgateway = flag.String("pushgateway", "", "Periodically push the /metrics metrics to this Pushgateway URL, example: http://pushgateway:9091")
+var pushgatewayJob = flag.String("pushgatewayJob", "ddns-go", "Pushgateway job label")
+var pushgatewayInstance = flag.String("pushgatewayInstance", "", "Pushgateway instance label, defaults to the hostname")
+var pushgatewayInterval = flag.Int("pushgatewayInterval", 0, "Pushgateway push interval (seconds), 0 to use the update frequency")
+
+// 日志条数
+var logLines = flag.Int("logLines", 50, "Number of log lines kept in memory for the web UI (max 10000)")
+
+// 调试日志
+var debug = flag.Bool("debug", false, "Log DNS provider requests and responses (secrets redacted)")
+
+// HTTP 连接复用
+var httpMaxIdleConns = flag.Int("httpMaxIdleConns", 100, "Max idle HTTP connections")
+var httpIdleTimeout = flag.Int("httpIdleTimeout", 90, "Idle HTTP connection timeout (seconds)")
+var httpKeepAlive = flag.Int("httpKeepAlive", 30, "TCP keep-alive interval (seconds)")
+
+// 请求频率及并发数
+var rateLimit = flag.Float64("rateLimit", 0, "Max requests per second to each DNS provider type, 0 for no limit (published provider limits still apply)")
+var concurrency = flag.Int("concurrency", 1, "Number of DNS configs updated concurrently in each run")
+
+// 获取远程配置时的请求头
+var configHeader = flag.String("configHeader", "", "Request header when -c is a URL, example: \"Authorization: Bearer token\"")
+
+// 打印配置
+var printConfig = flag.Bool("printconfig", false, "Print the effective configuration and exit")
+
+// 只使用环境变量中的配置
+var envConfig = flag.Bool("envConfig", false, "Load the configuration only from DDNS_GO_* environment variables, no config file is read or written")
+
+// 校验配置
+var checkConfig = flag.Bool("check", false, "Validate the configuration and exit (exit code 0 valid, 1 invalid)")
+
+// 配置不正确时启动失败
+var strict = flag.Bool("strict", false, "Exit with a non-zero code at startup if the configuration is missing or invalid (same validation as -check)")
+
+// 导出为环境变量
+var exportEnv = flag.Bool("exportEnv", false, "Print the configuration as environment variables and exit")
+
+// 打印配置时隐藏密钥
+var redact = flag.Bool("redact", true, "Redact secrets when using -printconfig or -exportEnv")
+
+// 导入 ddclient 配置
+var importDdclient = flag.String("importDdclient", "", "Convert a ddclient.conf file and append it to the config file (-c), then exit")
 
 // 重置密码
 var newPassword = flag.String("resetPassword", "", "Reset password to the one entered")
 
+// 只运行一次
+var once = flag.Bool("once", false, "Run one update and exit (exit code 0 unchanged, 10 changed, 1 error)")
+
+// -once 的退出码
+const (
+	exitUnchanged = 0
+	exitFailed    = 1
+	exitChanged   = 10
+)
+
 //go:embed static
 var staticEmbeddedFiles embed.FS
 
@@ -81,16 +210,113 @@ func main() {
 	if runtime.GOOS == "android" {
 		util.FixTimezone()
 	}
-	// 检查监听地址
-	if _, err := net.ResolveTCPAddr("tcp", *listen); err != nil {
-		log.Fatalf("Parse listen address failed! Exception: %s", err)
+	// 检查并规范监听地址
+	if len(listen.addrs) == 0 {
+		log.Fatal("Parse listen address failed! Exception: empty listen address")
+	}
+	for i, l := range listen.addrs {
+		addr, err := util.NormalizeListenAddr(l)
+		if err != nil {
+			log.Fatalf("Parse listen address failed! Exception: %s", err)
+		}
+		listen.addrs[i] = addr
 	}
 	// 设置版本号
-	os.Setenv(web.VersionEnv, version)
+	os.Setenv(util.VersionENV, version)
+	// 设置跳过证书验证
+	if *skipVerify {
+		util.SetInsecureSkipVerify()
+	}
+	// 设置自定义的CA证书
+	if *caCert != "" {
+		if err := util.SetCACert(*caCert); err != nil {
+			log.Fatalf("Load CA certificate failed! Exception: %s", err)
+		}
+	}
+	// 设置调试日志
+	util.SetDebug(*debug)
+	// 设置内存中的日志条数
+	web.SetMaxLogs(*logLines)
+	// 设置HTTP连接复用
+	util.SetHTTPTransport(*httpMaxIdleConns, time.Duration(*httpIdleTimeout)*time.Second, time.Duration(*httpKeepAlive)*time.Second)
+	// 设置请求频率及并发数
+	dns.SetRateLimit(*rateLimit)
+	dns.SetConcurrency(*concurrency)
+	// 设置自定义DNS
+	if *customDNS != "" {
+		util.SetDNS(*customDNS)
+	}
+	// 只使用环境变量中的配置
+	config.SetEnvOnly(*envConfig)
 	// 设置配置文件路径
-	if *configFilePath != "" {
-		absPath, _ := filepath.Abs(*configFilePath)
-		os.Setenv(util.ConfigFilePathENV, absPath)
+	if config.IsConfigURL(*configFilePath) {
+		// 从远程获取配置, 失败时使用本地缓存
+		cachePath, err := config.FetchRemoteConfig(*configFilePath, *configHeader)
+		if err != nil {
+			util.Log("获取远程配置失败, 将使用本地缓存: %s", cachePath)
+			util.Log("异常信息: %s", err)
+		}
+		os.Setenv(util.ConfigFilePathENV, cachePath)
+	} else if *configFilePath != "" {
+		// 支持以逗号分隔的多个配置文件
+		var absPaths []string
+		for _, path := range strings.Split(*configFilePath, ",") {
+			absPath, _ := filepath.Abs(strings.TrimSpace(path))
+			absPaths = append(absPaths, absPath)
+		}
+		os.Setenv(util.ConfigFilePathENV, strings.Join(absPaths, ","))
+	}
+	// 打印配置
+	if *printConfig {
+		// 日志输出到 stderr, 以免混入配置
+		log.SetOutput(os.Stderr)
+		conf, err := config.GetConfigCached()
+		if err != nil {
+			util.Log("配置文件 %s 不存在, 可通过-c指定配置文件", *configFilePath)
+			return
+		}
+		// 只读, 不加密保存密码
+		conf.CompatibleDnsConf()
+		if *redact {
+			conf = conf.Redacted()
+		}
+		byt, err := yaml.Marshal(conf)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(string(byt))
+		return
+	}
+	// 校验配置
+	if *checkConfig {
+		if !validateConfig() {
+			os.Exit(1)
+		}
+		conf, _ := config.GetConfigCached()
+		for _, w := range conf.DuplicateDomainWarnings() {
+			util.Log("%s", w)
+		}
+		util.Log("配置正确")
+		return
+	}
+	// 导出为环境变量
+	if *exportEnv {
+		log.SetOutput(os.Stderr)
+		conf, err := config.GetConfigCached()
+		if err != nil {
+			util.Log("配置文件 %s 不存在, 可通过-c指定配置文件", *configFilePath)
+			return
+		}
+		if *redact {
+			conf = conf.Redacted()
+		}
+		fmt.Println(strings.Join(conf.ToEnv(), "\n"))
+		return
+	}
+	// 导入 ddclient 配置
+	if *importDdclient != "" {
+		importDdclientConfig(*importDdclient)
+		return
 	}
 	// 重置密码
 	if *newPassword != "" {
@@ -102,15 +328,19 @@ func main() {
 		}
 		return
 	}
-	// 设置跳过证书验证
-	if *skipVerify {
-		util.SetInsecureSkipVerify()
+	os.Setenv(util.IPCacheTimesENV, strconv.Itoa(*ipCacheTimes))
+	// 设置获取IP接口的超时时间
+	util.SetIPURLTimeout(time.Duration(*ipURLTimeout) * time.Second)
+	// 配置不正确时直接退出, 不启动web服务
+	// 安装/卸载/重启服务时不校验, 新安装时可能还没有配置文件, 由服务启动时校验
+	if *strict && !slices.Contains([]string{"install", "uninstall", "restart"}, *serviceType) && !validateConfig() {
+		util.Log("配置不正确, 使用 -strict 时退出")
+		os.Exit(1)
 	}
-	// 设置自定义DNS
-	if *customDNS != "" {
-		util.SetDNS(*customDNS)
+	// 只运行一次, 供 cron 等调用
+	if *once {
+		os.Exit(runOnce())
 	}
-	os.Setenv(util.IPCacheTimesENV, strconv.Itoa(*ipCacheTimes))
 	switch *serviceType {
 	case "install":
 		installService()
@@ -120,6 +350,7 @@ func main() {
 		restartService()
 	default:
 		if util.IsRunInDocker() {
+			handleExitSignal()
 			run()
 		} else {
 			s := getService()
@@ -135,18 +366,64 @@ func main() {
 				default:
 					util.Log("可使用 sudo ./ddns-go -s install 安装服务运行")
 				}
+				handleExitSignal()
 				run()
 			}
 		}
 	}
 }
 
+// validateConfig 校验配置并输出错误, 配置文件不存在或不正确时返回 false
+func validateConfig() bool {
+	conf, err := config.GetConfigCached()
+	if err != nil {
+		util.Log("配置文件 %s 不存在, 可通过-c指定配置文件", *configFilePath)
+		return false
+	}
+	errs := conf.Validate()
+	for _, e := range errs {
+		util.Log("%s", e.Message)
+	}
+	return len(errs) == 0
+}
+
+// runOnce 更新一次并返回退出码
+func runOnce() int {
+	conf, err := config.GetConfigCached()
+	if err != nil {
+		util.Log("配置文件 %s 不存在, 可通过-c指定配置文件", *configFilePath)
+		return exitFailed
+	}
+	conf.CompatibleConfig()
+	util.InitLogLang(conf.Lang)
+	util.InitBackupDNS(*customDNS, conf.Lang)
+
+	dns.RunOnce()
+
+	switch dns.GetStatus().LastResult {
+	case dns.ResultSuccess:
+		return exitChanged
+	case dns.ResultNothing, dns.ResultPaused, dns.ResultMaintenance:
+		return exitUnchanged
+	default:
+		return exitFailed
+	}
+}
+
 func run() {
 	// 兼容之前的配置文件
 	conf, _ := config.GetConfigCached()
 	conf.CompatibleConfig()
 	// 初始化语言
 	util.InitLogLang(conf.Lang)
+	// 校验自定义图标
+	if err := config.CheckFavicon(conf.Favicon); err != nil {
+		util.Log("自定义图标不可用, 将使用内置图标. %s", err)
+	}
+	// 提示重复的域名
+	for _, w := range conf.DuplicateDomainWarnings() {
+		util.Log("%s", w)
+	}
 
 	if !*noWebService {
 		go func() {
@@ -164,7 +441,21 @@ func run() {
 	util.InitBackupDNS(*customDNS, conf.Lang)
 
 	// 等待网络连接
-	util.WaitInternet(dns.Addresses)
+	if !*skipWaitInternet {
+		util.WaitInternet(dns.Addresses, time.Duration(*waitInternet)*time.Second)
+	}
+
+	// 首次更新前等待网络稳定
+	if *startDelay > 0 {
+		util.Log("首次更新前等待 %s", time.Duration(*startDelay)*time.Second)
+		time.Sleep(time.Duration(*startDelay) * time.Second)
+	}
+
+	// 看门狗
+	go dns.RunWatchdog(time.Duration(*every)*time.Second, *watchdog, *watchdogAction)
+
+	// 推送指标到 Pushgateway
+	go dns.RunPushgateway(*pushgateway, *pushgatewayJob, pushgatewayInstanceLabel(), pushgatewayIntervalDuration())
 
 	// 定时运行
 	dns.RunTimer(time.Duration(*every) * time.Second)
@@ -175,6 +466,11 @@ func staticFsFunc(writer http.ResponseWriter, request *http.Request) {
 }
 
 func faviconFsFunc(writer http.ResponseWriter, request *http.Request) {
+	// 优先使用自定义图标
+	if conf, _ := config.GetConfigCached(); conf.Favicon != "" && config.CheckFavicon(conf.Favicon) == nil {
+		http.ServeFile(writer, request, conf.Favicon)
+		return
+	}
 	http.FileServer(http.FS(faviconEmbeddedFile)).ServeHTTP(writer, request)
 }
 
@@ -184,25 +480,154 @@ func runWebServer() error {
 	http.HandleFunc("/favicon.ico", web.AuthAssert(faviconFsFunc))
 	http.HandleFunc("/login", web.AuthAssert(web.Login))
 	http.HandleFunc("/loginFunc", web.AuthAssert(web.LoginFunc))
+	http.HandleFunc("/ip", web.AuthAssert(web.IP))
 
-	http.HandleFunc("/", web.Auth(web.Writing))
+	http.HandleFunc("/", web.AuthReadOnly(web.Writing))
 	http.HandleFunc("/save", web.Auth(web.Save))
-	http.HandleFunc("/logs", web.Auth(web.Logs))
+	http.HandleFunc("/logs", web.AuthReadOnly(web.Logs))
+	http.HandleFunc("/logs/stream", web.AuthReadOnly(web.LogsStream))
+	http.HandleFunc("/api/update", web.AuthAPI(web.APIUpdate))
+	http.HandleFunc("/api/update/{index}", web.AuthAPI(web.APIUpdateIndex))
+	http.HandleFunc("/api/pause", web.AuthAPI(web.Pause))
+	http.HandleFunc("/api/credentials", web.AuthAPI(web.APICredentials))
+	http.HandleFunc("/api/config/export", web.AuthAPIReadOnly(web.ConfigExport))
+	http.HandleFunc("/api/config/import", web.AuthAPI(web.ConfigImport))
+	http.HandleFunc("/api/status", web.AuthAPIReadOnly(web.Status))
+	http.HandleFunc("/metrics", web.AuthAPIReadOnly(web.Metrics))
 	http.HandleFunc("/clearLog", web.Auth(web.ClearLog))
 	http.HandleFunc("/webhookTest", web.Auth(web.WebhookTest))
-	http.HandleFunc("/logout", web.Auth(web.Logout))
+	http.HandleFunc("/exportEnv", web.Auth(web.ExportEnv))
+	http.HandleFunc("/diagnostics", web.AuthReadOnly(web.Diagnostics))
+	http.HandleFunc("/logout", web.AuthReadOnly(web.Logout))
+
+	// 启动前检查证书, 以免每个监听地址都出错
+	useTLS := *tlsCert != "" || *tlsKey != ""
+	var cert *tls.Certificate
+	if useTLS {
+		c, err := tls.LoadX509KeyPair(*tlsCert, *tlsKey)
+		if err != nil {
+			return errors.New(util.LogStr("加载HTTPS证书失败: %s", err))
+		}
+		cert = &c
+	}
+	var ac *web.Autocert
+	if hosts := autocertHosts(); len(hosts) > 0 {
+		dir := *tlsAutocertDir
+		if dir == "" {
+			dir = filepath.Join(filepath.Dir(util.GetConfigFilePath()), "autocert")
+		}
+		util.Log("自动申请 %s 的证书, 缓存目录: %s", strings.Join(hosts, ", "), dir)
+		ac = web.NewAutocert(hosts, dir, cert)
+		webServer.Handler = ac.Handler(http.DefaultServeMux)
+	}
 
-	util.Log("监听 %s", *listen)
+	// HTTP/3 使用与 HTTPS 相同的证书
+	var h3TLS *tls.Config
+	if *enableHTTP3 {
+		switch {
+		case ac != nil:
+			h3TLS = ac.TLSConfig()
+		case cert != nil:
+			h3TLS = &tls.Config{Certificates: []tls.Certificate{*cert}}
+		default:
+			return errors.New(util.LogStr("使用 -http3 时需同时指定 -tlsCert -tlsKey 或 -tlsAutocert"))
+		}
+	}
 
-	l, err := net.Listen("tcp", *listen)
-	if err != nil {
-		return errors.New(util.LogStr("监听端口发生异常, 请检查端口是否被占用! %s", err))
+	var listeners []net.Listener
+	var packetConns []net.PacketConn
+	closeAll := func() {
+		for _, opened := range listeners {
+			opened.Close()
+		}
+		for _, opened := range packetConns {
+			opened.Close()
+		}
+	}
+	for _, addr := range listen.addrs {
+		util.Log("监听 %s", addr)
+		l, err := net.Listen("tcp", addr)
+		if err != nil {
+			closeAll()
+			return errors.New(util.LogStr("监听端口发生异常, 请检查端口是否被占用! %s", err))
+		}
+		listeners = append(listeners, l)
+		if h3TLS == nil {
+			continue
+		}
+		util.Log("监听 %s (HTTP/3)", addr)
+		pc, err := net.ListenPacket("udp", addr)
+		if err != nil {
+			closeAll()
+			return errors.New(util.LogStr("监听端口发生异常, 请检查端口是否被占用! %s", err))
+		}
+		packetConns = append(packetConns, pc)
+	}
+
+	// 告知浏览器可使用 HTTP/3, 端口与本次连接的端口相同
+	handler := webServer.Handler
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+	if h3TLS != nil {
+		webServer.Handler = altSvcHandler(handler)
 	}
 
 	// 没有配置, 自动打开浏览器
 	autoOpenExplorer()
 
-	return http.Serve(l, nil)
+	if *showQR {
+		printQRCode()
+	}
+
+	// 任一监听地址出错时返回, 停止时关闭所有监听
+	errCh := make(chan error, len(listeners)+len(packetConns))
+	for _, pc := range packetConns {
+		h3 := &http3.Server{Handler: handler, TLSConfig: http3.ConfigureTLSConfig(h3TLS)}
+		http3Servers = append(http3Servers, h3)
+		go func() {
+			errCh <- h3.Serve(pc)
+		}()
+	}
+	for _, l := range listeners {
+		go func() {
+			if ac != nil {
+				// 同一端口处理 HTTPS 及 HTTP-01 验证, Serve 对TLS连接自动协商 HTTP/2
+				errCh <- webServer.Serve(ac.Listener(l))
+				return
+			}
+			if useTLS {
+				// ServeTLS 自动协商 HTTP/2
+				errCh <- webServer.ServeTLS(l, *tlsCert, *tlsKey)
+				return
+			}
+			errCh <- webServer.Serve(l)
+		}()
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// altSvcHandler 在响应头中添加 Alt-Svc, 浏览器之后使用 HTTP/3 连接相同端口
+func altSvcHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+			if _, port, err := net.SplitHostPort(addr.String()); err == nil {
+				w.Header().Set("Alt-Svc", `h3=":`+port+`"; ma=86400`)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// closeWebServer 关闭web服务, 包括 HTTP/3 服务
+func closeWebServer() {
+	webServer.Close()
+	for _, h3 := range http3Servers {
+		h3.Close()
+	}
 }
 
 type program struct{}
@@ -217,9 +642,23 @@ func (p *program) run() {
 }
 func (p *program) Stop(s service.Service) error {
 	// Stop should not block. Return with a few seconds.
+	dns.ExecShutdownWebhook()
+	closeWebServer()
 	return nil
 }
 
+// 非服务方式运行时, 收到退出信号后发送停止通知
+func handleExitSignal() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		dns.ExecShutdownWebhook()
+		closeWebServer()
+		os.Exit(0)
+	}()
+}
+
 func getService() service.Service {
 	options := make(service.KeyValue)
 	var depends []string
@@ -241,7 +680,7 @@ func getService() service.Service {
 		Name:         "ddns-go",
 		DisplayName:  "ddns-go",
 		Description:  "Simple and easy to use DDNS. Automatically update domain name resolution to public IP (Support Aliyun, Tencent Cloud, Dnspod, Cloudflare, Callback, Huawei Cloud, Baidu Cloud, Porkbun, GoDaddy...)",
-		Arguments:    []string{"-l", *listen, "-f", strconv.Itoa(*every), "-cacheTimes", strconv.Itoa(*ipCacheTimes), "-c", *configFilePath},
+		Arguments:    []string{"-l", listen.String(), "-f", strconv.Itoa(*every), "-cacheTimes", strconv.Itoa(*ipCacheTimes), "-c", *configFilePath},
 		Dependencies: depends,
 		Option:       options,
 	}
@@ -254,10 +693,104 @@ func getService() service.Service {
 		svcConfig.Arguments = append(svcConfig.Arguments, "-skipVerify")
 	}
 
+	if *caCert != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-caCert", *caCert)
+	}
+
+	if *noBrowser {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-nobrowser")
+	}
+
 	if *customDNS != "" {
 		svcConfig.Arguments = append(svcConfig.Arguments, "-dns", *customDNS)
 	}
 
+	if *debug {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-debug")
+	}
+
+	if *strict {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-strict")
+	}
+
+	if *logLines != 50 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-logLines", strconv.Itoa(*logLines))
+	}
+
+	if *watchdog > 0 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-watchdog", strconv.Itoa(*watchdog), "-watchdogAction", *watchdogAction)
+	}
+
+	if *pushgateway != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-pushgateway", *pushgateway, "-pushgatewayJob", *pushgatewayJob)
+		if *pushgatewayInstance != "" {
+			svcConfig.Arguments = append(svcConfig.Arguments, "-pushgatewayInstance", *pushgatewayInstance)
+		}
+		if *pushgatewayInterval > 0 {
+			svcConfig.Arguments = append(svcConfig.Arguments, "-pushgatewayInterval", strconv.Itoa(*pushgatewayInterval))
+		}
+	}
+
+	if *waitInternet > 0 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-waitInternet", strconv.Itoa(*waitInternet))
+	}
+
+	if *skipWaitInternet {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-skipWaitInternet")
+	}
+
+	if *tlsCert != "" || *tlsKey != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-tlsCert", *tlsCert, "-tlsKey", *tlsKey)
+	}
+
+	if *tlsAutocert != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-tlsAutocert", *tlsAutocert)
+	}
+
+	if *enableHTTP3 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-http3")
+	}
+
+	if *tlsAutocertDir != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-tlsAutocertDir", *tlsAutocertDir)
+	}
+
+	if *startDelay > 0 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-startDelay", strconv.Itoa(*startDelay))
+	}
+
+	if *configHeader != "" {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-configHeader", *configHeader)
+	}
+
+	if *ipURLTimeout != 10 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-ipTimeout", strconv.Itoa(*ipURLTimeout))
+	}
+
+	if *httpMaxIdleConns != 100 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-httpMaxIdleConns", strconv.Itoa(*httpMaxIdleConns))
+	}
+
+	if *httpIdleTimeout != 90 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-httpIdleTimeout", strconv.Itoa(*httpIdleTimeout))
+	}
+
+	if *httpKeepAlive != 30 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-httpKeepAlive", strconv.Itoa(*httpKeepAlive))
+	}
+
+	if *rateLimit > 0 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-rateLimit", strconv.FormatFloat(*rateLimit, 'f', -1, 64))
+	}
+
+	if *concurrency != 1 {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-concurrency", strconv.Itoa(*concurrency))
+	}
+
+	if *envConfig {
+		svcConfig.Arguments = append(svcConfig.Arguments, "-envConfig")
+	}
+
 	prg := &program{}
 	s, err := service.New(prg, svcConfig)
 	if err != nil {
@@ -329,29 +862,126 @@ func restartService() {
 	}
 }
 
+// pushgatewayInstanceLabel Pushgateway 的 instance 标签, 未指定时使用主机名
+func pushgatewayInstanceLabel() string {
+	if *pushgatewayInstance != "" {
+		return *pushgatewayInstance
+	}
+	hostname, _ := os.Hostname()
+	return hostname
+}
+
+// pushgatewayIntervalDuration 推送间隔, 未指定时与更新频率相同
+func pushgatewayIntervalDuration() time.Duration {
+	if *pushgatewayInterval > 0 {
+		return time.Duration(*pushgatewayInterval) * time.Second
+	}
+	return time.Duration(*every) * time.Second
+}
+
+// autocertHosts 自动申请证书的域名
+func autocertHosts() (hosts []string) {
+	for _, host := range strings.Split(*tlsAutocert, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return
+}
+
+// webScheme 指定了证书或自动申请证书时为 https
+func webScheme() string {
+	if *tlsCert != "" || *tlsKey != "" || len(autocertHosts()) > 0 {
+		return "https"
+	}
+	return "http"
+}
+
 // 打开浏览器
 func autoOpenExplorer() {
 	_, err := config.GetConfigCached()
 	// 未找到配置文件
 	if err != nil {
-		if util.IsRunInDocker() {
-			// docker中运行, 提示
-			util.Log("Docker中运行, 请在浏览器中打开 http://docker主机IP:9876 进行配置")
+		if container := util.DetectContainer(); container != "" {
+			// 容器中运行, 提示
+			util.Log("在容器(%s)中运行, 请在浏览器中打开 http://主机IP:9876 进行配置", container)
 		} else {
-			// 主机运行, 打开浏览器
-			addr, err := net.ResolveTCPAddr("tcp", *listen)
+			// 主机运行, 打开浏览器, 多个监听地址时使用第一个
+			addr, err := net.ResolveTCPAddr("tcp", listen.addrs[0])
 			if err != nil {
 				return
 			}
-			url := fmt.Sprintf("http://127.0.0.1:%d", addr.Port)
+			url := fmt.Sprintf("%s://127.0.0.1:%d", webScheme(), addr.Port)
 			if addr.IP.IsGlobalUnicast() {
-				url = fmt.Sprintf("http://%s", addr.String())
+				url = fmt.Sprintf("%s://%s", webScheme(), addr.String())
+			}
+			if *noBrowser {
+				util.Log("请在浏览器中打开 %s 进行配置", url)
+				return
 			}
 			go util.OpenExplorer(url)
 		}
 	}
 }
 
+// 导入 ddclient 配置, 追加到已有的配置中
+func importDdclientConfig(path string) {
+	byt, err := os.ReadFile(path)
+	if err != nil {
+		util.Log("读取 ddclient 配置失败: %s", err)
+		return
+	}
+	dnsConfs := config.ImportDdclient(string(byt))
+	if len(dnsConfs) == 0 {
+		util.Log("ddclient 配置中没有可导入的域名")
+		return
+	}
+	conf, err := config.GetConfigCached()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		util.Log("异常信息: %s", err)
+		return
+	}
+	conf.DnsConf = append(conf.DnsConf, dnsConfs...)
+	if err = conf.SaveConfig(); err != nil {
+		return
+	}
+	for _, dc := range dnsConfs {
+		util.Log("已导入 %s, IPv4域名: %s, IPv6域名: %s", dc.DNS.Name, strings.Join(dc.Ipv4.Domains, ","), strings.Join(dc.Ipv6.Domains, ","))
+	}
+}
+
+// 打印网页地址的二维码, 容器中或输出不是终端时跳过
+func printQRCode() {
+	if container := util.DetectContainer(); container != "" {
+		util.Log("在容器(%s)中运行, 不打印二维码", container)
+		return
+	}
+	if stat, err := os.Stdout.Stat(); err != nil || stat.Mode()&os.ModeCharDevice == 0 {
+		util.Log("输出不是终端, 不打印二维码")
+		return
+	}
+	// 使用第一个局域网可访问的监听地址
+	var url string
+	var err error
+	for _, addr := range listen.addrs {
+		if url, err = util.GetLANURL(addr); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		util.Log("无法获取局域网地址, 不打印二维码: %s", err)
+		return
+	}
+	url = strings.Replace(url, "http://", webScheme()+"://", 1)
+	qr, err := util.QRCodeString(url)
+	if err != nil {
+		util.Log("生成二维码失败: %s", err)
+		return
+	}
+	fmt.Print(qr)
+	util.Log("使用手机扫描二维码打开 %s", url)
+}
+
 const sysvScript = `#!/bin/sh /etc/rc.common
 DESCRIPTION="{{.Description}}"
 cmd="{{.Path}}{{range .Arguments}} {{.|cmd}}{{end}}"
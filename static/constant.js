@@ -6,6 +6,8 @@ const DNS_PROVIDERS = {
     },
     idLabel: "AccessKey ID",
     secretLabel: "AccessKey Secret",
+    // 支持 ?weight= 设置记录的权重
+    weight: true,
     helpHtml: {
       "en": "<a target='_blank' href='https://ram.console.aliyun.com/manage/ak?spm=5176.12818093.nav-right.dak.488716d0mHaMgg'>Create AccessKey</a>",
       "zh-cn": "<a target='_blank' href='https://ram.console.aliyun.com/manage/ak?spm=5176.12818093.nav-right.dak.488716d0mHaMgg'>创建 AccessKey</a>",
@@ -18,6 +20,8 @@ const DNS_PROVIDERS = {
     },
     idLabel: "SecretId",
     secretLabel: "SecretKey",
+    // 支持 ?weight= 设置记录的权重
+    weight: true,
     helpHtml: {
       "en": "<a target='_blank' href='https://console.dnspod.cn/account/token/apikey'>Create AccessKey</a>",
       "zh-cn": "<a target='_blank' href='https://console.dnspod.cn/account/token/apikey'>创建腾讯云 API 密钥</a>",
@@ -29,6 +33,8 @@ const DNS_PROVIDERS = {
     },
     idLabel: "ID",
     secretLabel: "Token",
+    // 支持 ?weight= 设置记录的权重
+    weight: true,
     helpHtml: {
       "en": "<a target='_blank' href='https://console.dnspod.cn/account/token/token'>Create Token</a>",
       "zh-cn": "<a target='_blank' href='https://console.dnspod.cn/account/token/token'>创建 DNSPod Token</a>",
@@ -43,7 +49,9 @@ const DNS_PROVIDERS = {
     helpHtml: {
       "en": "<a target='_blank' href='https://dash.cloudflare.com/profile/api-tokens'>Create Token -> Edit Zone DNS (Use template)</a>",
       "zh-cn": "<a target='_blank' href='https://dash.cloudflare.com/profile/api-tokens'>创建令牌 -> 编辑区域 DNS (使用模板)</a>",
-    }
+    },
+    // 返回连接IP的接口
+    ipEcho: "https://cloudflare.com/cdn-cgi/trace",
   },
   huaweicloud: {
     name: {
@@ -63,9 +71,10 @@ const DNS_PROVIDERS = {
     },
     idLabel: "URL",
     secretLabel: "RequestBody",
+    requestOptions: true,
     helpHtml: {
-      "en": "<a target='_blank' href='https://github.com/jeessy2/ddns-go/blob/master/README_EN.md#callback'>Callback</a> Support variables #{ip}, #{domain}, #{recordType}, #{ttl}",
-      "zh-cn": "<a target='_blank' href='https://github.com/jeessy2/ddns-go#callback'>自定义回调</a> 支持的变量 #{ip}, #{domain}, #{recordType}, #{ttl}",
+      "en": "<a target='_blank' href='https://github.com/jeessy2/ddns-go/blob/master/README_EN.md#callback'>Callback</a> Support variables #{ip}, #{domain}, #{recordType}, #{ttl}, or templates {{.IP}}, {{.Domain}}, {{.SubDomain}}, {{.RecordType}}",
+      "zh-cn": "<a target='_blank' href='https://github.com/jeessy2/ddns-go#callback'>自定义回调</a> 支持的变量 #{ip}, #{domain}, #{recordType}, #{ttl}, 或模板 {{.IP}}, {{.Domain}}, {{.SubDomain}}, {{.RecordType}}",
     }
   },
   baiducloud: {
@@ -169,6 +178,115 @@ const DNS_PROVIDERS = {
         "zh-cn": "<a target='_blank' href='https://dynv6.com/keys'>创建令牌</a>",
     }
   },
+  mythicbeasts: {
+    name: {
+      "en": "Mythic Beasts",
+    },
+    idLabel: "Key ID",
+    secretLabel: "Secret",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://www.mythic-beasts.com/customer/api-users'>Create API key</a> with read and write permission for the zone's A/AAAA records",
+      "zh-cn": "<a target='_blank' href='https://www.mythic-beasts.com/customer/api-users'>创建 API 密钥</a>, 需要该区域 A/AAAA 记录的读写权限",
+    }
+  },
+  loopia: {
+    name: {
+      "en": "Loopia",
+    },
+    endpointLabel: "API URL",
+    endpointHelpHtml: {
+      "en": "Optional, defaults to https://api.loopia.se/RPCSERV, such as https://api.loopia.rs/RPCSERV for Loopia RS",
+      "zh-cn": "可选, 默认为 https://api.loopia.se/RPCSERV, Loopia RS 可填写 https://api.loopia.rs/RPCSERV",
+    },
+    idLabel: "API User",
+    secretLabel: "Password",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://customerzone.loopia.com/settings/apiuser/'>Create API user</a> with the <code>getZoneRecords</code>, <code>addZoneRecord</code>, <code>updateZoneRecord</code> and <code>addSubdomain</code> permissions, such as <code>user@loopiaapi</code>",
+      "zh-cn": "<a target='_blank' href='https://customerzone.loopia.com/settings/apiuser/'>创建 API 用户</a>, 需要 <code>getZoneRecords</code>、<code>addZoneRecord</code>、<code>updateZoneRecord</code>、<code>addSubdomain</code> 权限, 如 <code>user@loopiaapi</code>",
+    }
+  },
+  dnsexit: {
+    name: {
+      "en": "DNSExit",
+    },
+    idLabel: "",
+    secretLabel: "API Key",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://dnsexit.com/dns/dns-api/'>Create API Key</a>",
+      "zh-cn": "<a target='_blank' href='https://dnsexit.com/dns/dns-api/'>创建 API Key</a>",
+    }
+  },
+  spaceship: {
+    name: {
+      "en": "Spaceship",
+    },
+    idLabel: "API Key",
+    secretLabel: "API Secret",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://www.spaceship.com/application/api-manager/'>Create API Key</a> with the <code>dnsrecords:read</code> and <code>dnsrecords:write</code> permissions",
+      "zh-cn": "<a target='_blank' href='https://www.spaceship.com/application/api-manager/'>创建 API Key</a>, 需要 <code>dnsrecords:read</code> 和 <code>dnsrecords:write</code> 权限",
+    }
+  },
+  njalla: {
+    name: {
+      "en": "Njalla",
+    },
+    idLabel: "",
+    secretLabel: "Token",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://njal.la/settings/api/'>Create API Token</a>. TTL supports 60, 300, 900, 3600, 10800, 21600, 86400",
+      "zh-cn": "<a target='_blank' href='https://njal.la/settings/api/'>创建 API Token</a>。TTL 支持 60, 300, 900, 3600, 10800, 21600, 86400",
+    }
+  },
+  dnsmadeeasy: {
+    name: {
+      "en": "DNS Made Easy",
+    },
+    idLabel: "API Key",
+    secretLabel: "Secret Key",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://cp.dnsmadeeasy.com/account/info'>Get API Key</a>",
+      "zh-cn": "<a target='_blank' href='https://cp.dnsmadeeasy.com/account/info'>获取 API Key</a>",
+    }
+  },
+  constellix: {
+    name: {
+      "en": "Constellix",
+    },
+    idLabel: "API Key",
+    secretLabel: "Secret Key",
+    helpHtml: {
+      "en": "<a target='_blank' href='https://manage.constellix.com/users'>Get API Key</a>",
+      "zh-cn": "<a target='_blank' href='https://manage.constellix.com/users'>获取 API Key</a>",
+    }
+  },
+  zoneedit: {
+    name: {
+      "en": "ZoneEdit",
+    },
+    idLabel: "Username",
+    secretLabel: "Token",
+    helpHtml: {
+      "en": "Enable <code>Dynamic Authentication</code> in the domain's DNS settings of <a target='_blank' href='https://cp.zoneedit.com/'>ZoneEdit</a> to get the token. Updates with the same IP less than 10 minutes apart are rejected",
+      "zh-cn": "在 <a target='_blank' href='https://cp.zoneedit.com/'>ZoneEdit</a> 的域名 DNS 设置中开启 <code>Dynamic Authentication</code> 获取令牌。10 分钟内相同IP的更新会被拒绝",
+    }
+  },
+  dyndns2: {
+    name: {
+      "en": "DynDNS2",
+    },
+    endpointLabel: "Update URL",
+    endpointHelpHtml: {
+      "en": "Such as: https://dynupdate.no-ip.com/nic/update. The <code>hostname</code> and <code>myip</code> parameters are appended automatically; for services with other parameter names use #{hostname} and #{ip}, such as: https://example.com/update?host=#{hostname}&ip=#{ip}",
+      "zh-cn": "如: https://dynupdate.no-ip.com/nic/update 。将自动追加 <code>hostname</code> 和 <code>myip</code> 参数; 参数名不同的服务商可使用 #{hostname} 和 #{ip}, 如: https://example.com/update?host=#{hostname}&ip=#{ip}",
+    },
+    idLabel: "Username",
+    secretLabel: "Password",
+    helpHtml: {
+      "en": "Generic provider for services using the DynDNS2 protocol, such as No-IP, DynDNS, FreeDNS",
+      "zh-cn": "通用的 DynDNS2 协议, 适用于 No-IP、DynDNS、FreeDNS 等服务商",
+    }
+  },
 };
 
 const SVG_CODE = {
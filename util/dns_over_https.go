@@ -0,0 +1,97 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// dohTimeout DoH请求的默认超时时间
+const dohTimeout = 10 * time.Second
+
+// newDoTResolver 使用 DNS over TLS 的 Resolver, 如 tls://1.1.1.1
+func newDoTResolver(addr string, serverName string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			d := tls.Dialer{Config: clientTLSConfig(serverName)}
+			return d.DialContext(ctx, "tcp", addr)
+		},
+	}
+}
+
+// newDoHResolver 使用 DNS over HTTPS 的 Resolver, 如 https://1.1.1.1/dns-query
+// client 不能使用该 Resolver 解析域名, 否则会循环解析
+func newDoHResolver(endpoint string, client *http.Client) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return &dohConn{endpoint: endpoint, client: client, ctx: ctx}, nil
+		},
+	}
+}
+
+// dohConn 将 Resolver 发出的 TCP 格式的 DNS 消息转为 DoH 请求
+// Resolver 每次写入一个完整的请求(2字节长度+消息), 再读取响应
+type dohConn struct {
+	endpoint string
+	client   *http.Client
+	ctx      context.Context
+	deadline time.Time
+	resp     bytes.Buffer
+}
+
+func (c *dohConn) Write(b []byte) (int, error) {
+	if len(b) < 2 || int(binary.BigEndian.Uint16(b)) != len(b)-2 {
+		return 0, errors.New("doh: invalid dns message")
+	}
+
+	deadline := c.deadline
+	if deadline.IsZero() {
+		deadline = time.Now().Add(dohTimeout)
+	}
+	ctx, cancel := context.WithDeadline(c.ctx, deadline)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(b[2:]))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, errors.New("doh: " + resp.Status)
+	}
+	msg, err := io.ReadAll(io.LimitReader(resp.Body, 65535))
+	if err != nil {
+		return 0, err
+	}
+
+	c.resp.Reset()
+	binary.Write(&c.resp, binary.BigEndian, uint16(len(msg)))
+	c.resp.Write(msg)
+	return len(b), nil
+}
+
+func (c *dohConn) Read(b []byte) (int, error) {
+	return c.resp.Read(b)
+}
+
+func (c *dohConn) Close() error                       { return nil }
+func (c *dohConn) LocalAddr() net.Addr                { return &net.TCPAddr{} }
+func (c *dohConn) RemoteAddr() net.Addr               { return &net.TCPAddr{} }
+func (c *dohConn) SetDeadline(t time.Time) error      { c.deadline = t; return nil }
+func (c *dohConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *dohConn) SetWriteDeadline(t time.Time) error { c.deadline = t; return nil }
@@ -0,0 +1,139 @@
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// testDNSAnswer 返回 A 记录为 192.0.2.1 的响应, 请求不正确时返回 nil
+func testDNSAnswer(body []byte) []byte {
+	var query dnsmessage.Message
+	if err := query.Unpack(body); err != nil || len(query.Questions) != 1 {
+		return nil
+	}
+
+	resp := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: query.ID, Response: true, RCode: dnsmessage.RCodeSuccess},
+		Questions: query.Questions,
+	}
+	q := query.Questions[0]
+	if q.Type == dnsmessage.TypeA {
+		resp.Answers = []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{Name: q.Name, Type: q.Type, Class: q.Class, TTL: 60},
+			Body:   &dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}},
+		}}
+	}
+	packed, _ := resp.Pack()
+	return packed
+}
+
+// newTestDoHServer DoH 服务器
+func newTestDoHServer() *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/dns-message" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		packed := testDNSAnswer(body)
+		if packed == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(packed)
+	}))
+}
+
+// TestDoHResolver 测试通过 DoH 解析域名
+func TestDoHResolver(t *testing.T) {
+	server := newTestDoHServer()
+	defer server.Close()
+
+	resolver := newDoHResolver(server.URL+"/dns-query", server.Client())
+	addrs, err := resolver.LookupHost(context.Background(), "ddns-go.example.")
+	if err != nil {
+		t.Fatalf("解析失败: %s", err)
+	}
+	if len(addrs) != 1 || addrs[0] != "192.0.2.1" {
+		t.Errorf("期待 192.0.2.1, 得到 %v", addrs)
+	}
+}
+
+// TestSetDNSEncrypted 测试设置 DoT/DoH
+func TestSetDNSEncrypted(t *testing.T) {
+	defer func() { dialer.Resolver = nil }()
+
+	for _, dns := range []string{"tls://1.1.1.1", "tls://dns.google:853", "https://1.1.1.1", "https://dns.google/dns-query"} {
+		dialer.Resolver = nil
+		SetDNS(dns)
+		if dialer.Resolver == nil {
+			t.Errorf("%s 未能设置 dialer.Resolver", dns)
+		}
+	}
+}
+
+// TestEncryptedDNSCACert 测试 DoT/DoH 信任自定义的CA证书
+func TestEncryptedDNSCACert(t *testing.T) {
+	server := newTestDoHServer()
+	defer server.Close()
+	saved := []*tls.Config{defaultTransport.TLSClientConfig, noProxyTcp4Transport.TLSClientConfig, noProxyTcp6Transport.TLSClientConfig}
+	defer func() {
+		defaultTransport.TLSClientConfig, noProxyTcp4Transport.TLSClientConfig, noProxyTcp6Transport.TLSClientConfig = saved[0], saved[1], saved[2]
+	}()
+
+	// DoT 使用相同的证书, 每个连接处理一个请求
+	l, err := tls.Listen("tcp", "127.0.0.1:0", server.TLS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				var length [2]byte
+				if _, err := io.ReadFull(conn, length[:]); err != nil {
+					return
+				}
+				body := make([]byte, binary.BigEndian.Uint16(length[:]))
+				if _, err := io.ReadFull(conn, body); err != nil {
+					return
+				}
+				packed := testDNSAnswer(body)
+				conn.Write(binary.BigEndian.AppendUint16(nil, uint16(len(packed))))
+				conn.Write(packed)
+			}()
+		}
+	}()
+
+	servers := []string{server.URL + "/dns-query", "tls://" + l.Addr().String()}
+	for _, dns := range servers {
+		if _, err := NewResolver(dns).LookupHost(context.Background(), "ddns-go.example."); err == nil {
+			t.Errorf("%s 期待未信任CA证书时解析失败", dns)
+		}
+	}
+
+	pemData := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	if err := SetCACert(pemData); err != nil {
+		t.Fatalf("设置CA证书失败: %s", err)
+	}
+	for _, dns := range servers {
+		addrs, err := NewResolver(dns).LookupHost(context.Background(), "ddns-go.example.")
+		if err != nil || len(addrs) != 1 || addrs[0] != "192.0.2.1" {
+			t.Errorf("%s 期待信任CA证书后解析到 192.0.2.1, 得到 %v %v", dns, addrs, err)
+		}
+	}
+}
@@ -1,12 +1,78 @@
 package util
 
-import "os"
+import (
+	"os"
+	"strings"
+	"sync"
+)
 
 // DockerEnvFile Docker容器中包含的文件
 const DockerEnvFile string = "/.dockerenv"
 
-// IsRunInDocker 是否在docker中运行
+// PodmanEnvFile Podman容器中包含的文件
+const PodmanEnvFile string = "/run/.containerenv"
+
+// 容器运行时
+const (
+	ContainerDocker     = "docker"
+	ContainerPodman     = "podman"
+	ContainerKubernetes = "kubernetes"
+	ContainerContainerd = "containerd"
+	ContainerLXC        = "lxc"
+)
+
+// cgroupFile 1号进程的 cgroup, cgroup v1 中包含容器运行时的名称
+const cgroupFile = "/proc/1/cgroup"
+
+// detectedContainer 检测到的容器运行时, 运行期间不会改变
+var detectedContainer = sync.OnceValue(func() string {
+	cgroup, _ := os.ReadFile(cgroupFile)
+	return detectContainer(func(name string) bool {
+		_, err := os.Stat(name)
+		return err == nil
+	}, os.Getenv, string(cgroup))
+})
+
+// DetectContainer 检测运行的容器, 如 docker podman kubernetes containerd lxc, 不在容器中运行时为空
+func DetectContainer() string {
+	return detectedContainer()
+}
+
+// IsRunInDocker 是否在docker等应用容器中运行, LXC 等系统容器中可安装为服务
 func IsRunInDocker() bool {
-	_, err := os.Stat(DockerEnvFile)
-	return err == nil
+	container := DetectContainer()
+	return container != "" && container != ContainerLXC && container != "systemd-nspawn"
+}
+
+// detectContainer 依次根据 /run/.containerenv、/.dockerenv、环境变量及 cgroup 检测容器运行时
+func detectContainer(fileExists func(string) bool, getenv func(string) string, cgroup string) string {
+	if fileExists(PodmanEnvFile) {
+		return ContainerPodman
+	}
+	if fileExists(DockerEnvFile) {
+		return ContainerDocker
+	}
+	// podman、lxc、systemd-nspawn 等会设置环境变量 container
+	if container := getenv("container"); container != "" {
+		if strings.HasPrefix(container, ContainerLXC) {
+			return ContainerLXC
+		}
+		return container
+	}
+	if getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return ContainerKubernetes
+	}
+	switch {
+	case strings.Contains(cgroup, "kubepods"):
+		return ContainerKubernetes
+	case strings.Contains(cgroup, "libpod"):
+		return ContainerPodman
+	case strings.Contains(cgroup, "docker"):
+		return ContainerDocker
+	case strings.Contains(cgroup, "containerd"):
+		return ContainerContainerd
+	case strings.Contains(cgroup, "/lxc"):
+		return ContainerLXC
+	}
+	return ""
 }
@@ -0,0 +1,35 @@
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestDetectContainer 测试检测容器运行时
+func TestDetectContainer(t *testing.T) {
+	data := []struct {
+		files    []string
+		env      map[string]string
+		cgroup   string
+		expected string
+	}{
+		{nil, nil, "0::/", ""},
+		{[]string{DockerEnvFile}, nil, "", ContainerDocker},
+		{[]string{PodmanEnvFile, DockerEnvFile}, nil, "", ContainerPodman},
+		{nil, map[string]string{"container": "podman"}, "", ContainerPodman},
+		{nil, map[string]string{"container": "lxc-libvirt"}, "", ContainerLXC},
+		{nil, map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"}, "", ContainerKubernetes},
+		{nil, nil, "12:pids:/kubepods/besteffort/pod1234/abcd", ContainerKubernetes},
+		{nil, nil, "11:memory:/system.slice/containerd.service/abcd", ContainerContainerd},
+		{nil, nil, "10:cpu:/docker/abcd", ContainerDocker},
+		{nil, nil, "9:cpuset:/lxc/ct100", ContainerLXC},
+	}
+
+	for _, d := range data {
+		fileExists := func(name string) bool { return slices.Contains(d.files, name) }
+		getenv := func(key string) string { return d.env[key] }
+		if got := detectContainer(fileExists, getenv, d.cgroup); got != d.expected {
+			t.Errorf("%v %v %q 期待 %q, 得到 %q", d.files, d.env, d.cgroup, d.expected, got)
+		}
+	}
+}
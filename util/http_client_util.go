@@ -3,8 +3,12 @@ package util
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"net"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 )
 
@@ -26,11 +30,43 @@ var defaultTransport = &http.Transport{
 	ExpectContinueTimeout: 1 * time.Second,
 }
 
+// userAgentTransport 为未设置 User-Agent 的请求添加 User-Agent
+type userAgentTransport struct {
+	userAgent string
+	base      http.RoundTripper
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	userAgent := t.userAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent()
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", userAgent)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// DefaultUserAgent 默认的 User-Agent, 包含 ddns-go 的版本
+func DefaultUserAgent() string {
+	version := os.Getenv(VersionENV)
+	if version == "" {
+		version = "DEV"
+	}
+	return "ddns-go/" + version + " (+https://github.com/jeessy2/ddns-go)"
+}
+
 // CreateHTTPClient Create Default HTTP Client
 func CreateHTTPClient() *http.Client {
+	return CreateHTTPClientWithUserAgent("")
+}
+
+// CreateHTTPClientWithUserAgent 使用自定义的 User-Agent 创建 HTTP Client, 为空使用默认值
+func CreateHTTPClientWithUserAgent(userAgent string) *http.Client {
 	return &http.Client{
 		Timeout:   30 * time.Second,
-		Transport: defaultTransport,
+		Transport: &userAgentTransport{userAgent: userAgent, base: defaultTransport},
 	}
 }
 
@@ -71,21 +107,84 @@ func CreateNoProxyHTTPClient(network string) *http.Client {
 	if network == "tcp6" {
 		return &http.Client{
 			Timeout:   30 * time.Second,
-			Transport: noProxyTcp6Transport,
+			Transport: &userAgentTransport{base: noProxyTcp6Transport},
 		}
 	}
 
 	return &http.Client{
 		Timeout:   30 * time.Second,
-		Transport: noProxyTcp4Transport,
+		Transport: &userAgentTransport{base: noProxyTcp4Transport},
 	}
 }
 
-// SetInsecureSkipVerify 将所有 http.Transport 的 InsecureSkipVerify 设置为 true
-func SetInsecureSkipVerify() {
+// SetHTTPTransport 设置共享连接的复用参数, 为0时保持默认值
+// 不复用连接的 noProxy Transport 不受影响
+func SetHTTPTransport(maxIdleConns int, idleConnTimeout time.Duration, keepAlive time.Duration) {
+	if maxIdleConns > 0 {
+		defaultTransport.MaxIdleConns = maxIdleConns
+	}
+	if idleConnTimeout > 0 {
+		defaultTransport.IdleConnTimeout = idleConnTimeout
+	}
+	if keepAlive > 0 {
+		dialer.KeepAlive = keepAlive
+	}
+}
+
+// tlsConfigs 所有 http.Transport 的 TLS 配置, 没有时创建
+func tlsConfigs() []*tls.Config {
 	transports := []*http.Transport{defaultTransport, noProxyTcp4Transport, noProxyTcp6Transport}
 
+	configs := make([]*tls.Config, 0, len(transports))
 	for _, transport := range transports {
-		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		configs = append(configs, transport.TLSClientConfig)
+	}
+	return configs
+}
+
+// clientTLSConfig 与 http.Transport 相同的 TLS 配置, 包括自定义的CA证书及跳过证书验证, 供 DoT/DoH 使用
+func clientTLSConfig(serverName string) *tls.Config {
+	config := &tls.Config{}
+	if defaultTransport.TLSClientConfig != nil {
+		config = defaultTransport.TLSClientConfig.Clone()
+	}
+	config.ServerName = serverName
+	return config
+}
+
+// SetInsecureSkipVerify 将所有 http.Transport 的 InsecureSkipVerify 设置为 true
+func SetInsecureSkipVerify() {
+	for _, config := range tlsConfigs() {
+		config.InsecureSkipVerify = true
+	}
+}
+
+// SetCACert 所有 http.Transport 在系统的根证书之外信任自定义的CA证书
+// cert 为文件路径或PEM内容, PEM内容中的 \n 视为换行, 便于在命令行及环境变量中使用
+func SetCACert(cert string) error {
+	var pemData []byte
+	if strings.Contains(cert, "-----BEGIN") {
+		pemData = []byte(strings.ReplaceAll(cert, `\n`, "\n"))
+	} else {
+		data, err := os.ReadFile(cert)
+		if err != nil {
+			return errors.New(LogStr("读取CA证书失败: %s", err))
+		}
+		pemData = data
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pemData) {
+		return errors.New(LogStr("CA证书中没有可用的证书"))
+	}
+	for _, config := range tlsConfigs() {
+		config.RootCAs = pool
 	}
+	return nil
 }
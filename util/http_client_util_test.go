@@ -0,0 +1,107 @@
+package util
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestUserAgent 测试默认及自定义的 User-Agent
+func TestUserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("User-Agent")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		userAgent string
+		expected  string
+	}{
+		{"Default", "", DefaultUserAgent()},
+		{"Custom", "custom-agent/1.0", "custom-agent/1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := CreateHTTPClientWithUserAgent(tt.userAgent).Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if got != tt.expected {
+				t.Errorf("Expected %s, but got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestSetHTTPTransport 测试连接复用参数, 为0时保持默认值
+func TestSetHTTPTransport(t *testing.T) {
+	maxIdleConns, idleConnTimeout, keepAlive := defaultTransport.MaxIdleConns, defaultTransport.IdleConnTimeout, dialer.KeepAlive
+	defer SetHTTPTransport(maxIdleConns, idleConnTimeout, keepAlive)
+
+	SetHTTPTransport(0, 0, 0)
+	if defaultTransport.MaxIdleConns != maxIdleConns || defaultTransport.IdleConnTimeout != idleConnTimeout || dialer.KeepAlive != keepAlive {
+		t.Errorf("Expected defaults unchanged")
+	}
+
+	SetHTTPTransport(20, 5*time.Minute, time.Minute)
+	if defaultTransport.MaxIdleConns != 20 {
+		t.Errorf("Expected 20 idle conns, but got %d", defaultTransport.MaxIdleConns)
+	}
+	if defaultTransport.IdleConnTimeout != 5*time.Minute {
+		t.Errorf("Expected 5m idle timeout, but got %s", defaultTransport.IdleConnTimeout)
+	}
+	if dialer.KeepAlive != time.Minute {
+		t.Errorf("Expected 1m keep-alive, but got %s", dialer.KeepAlive)
+	}
+}
+
+// TestSetCACert 测试信任自定义的CA证书, 支持文件路径及PEM内容
+func TestSetCACert(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	saved := []*tls.Config{defaultTransport.TLSClientConfig, noProxyTcp4Transport.TLSClientConfig, noProxyTcp6Transport.TLSClientConfig}
+	defer func() {
+		defaultTransport.TLSClientConfig, noProxyTcp4Transport.TLSClientConfig, noProxyTcp6Transport.TLSClientConfig = saved[0], saved[1], saved[2]
+	}()
+
+	get := func() error {
+		defaultTransport.CloseIdleConnections()
+		resp, err := CreateHTTPClient().Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	if get() == nil {
+		t.Fatal("期待未信任CA证书时请求失败")
+	}
+
+	pemData := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(file, []byte(pemData), 0600)
+	for _, cert := range []string{file, pemData, strings.ReplaceAll(pemData, "\n", `\n`)} {
+		if err := SetCACert(cert); err != nil {
+			t.Fatalf("设置CA证书失败: %s", err)
+		}
+		if err := get(); err != nil {
+			t.Errorf("期待信任CA证书后请求成功, 异常信息: %s", err)
+		}
+	}
+
+	if SetCACert("-----BEGIN CERTIFICATE-----\ninvalid\n-----END CERTIFICATE-----") == nil {
+		t.Error("期待没有可用的证书时返回错误")
+	}
+	if SetCACert(filepath.Join(t.TempDir(), "missing.pem")) == nil {
+		t.Error("期待文件不存在时返回错误")
+	}
+}
@@ -12,25 +12,90 @@ type IpCache struct {
 	Addr          string // 缓存地址
 	Times         int    // 剩余次数
 	TimesFailedIP int    // 获取ip失败的次数
+	CustomTimes   int    // 自定义的缓存次数, 为0时使用全局配置
+	StableTimes   int    // 新IP需连续相同的次数, 为0或1时立即更新
+	PendingAddr   string // 待确认的新地址
+	PendingTimes  int    // 待确认的新地址已连续获取到的次数
+	Provenance    string // 最近一次结果的来源, 见 IPFresh 等
+	// Written 域名上次成功更新的地址, 只更新变化的域名时用于跳过未变化的域名
+	Written map[string]string
+	// DomainTimes 单独设置了缓存次数的域名的剩余次数
+	DomainTimes map[string]int
 }
 
+// 最近一次结果的来源, IP每次都会重新获取, 缓存决定是否与DNS服务商比对
+const (
+	// IPFresh 已与DNS服务商比对
+	IPFresh = "fresh"
+	// IPCached IP未改变, 使用缓存, 未与DNS服务商比对
+	IPCached = "cached"
+	// IPPending IP已变化, 等待连续相同后再更新
+	IPPending = "pending"
+)
+
 var ForceCompareGlobal = true
 
 func (d *IpCache) Check(newAddr string) bool {
 	if newAddr == "" {
+		d.Provenance = ""
 		return true
 	}
 	// 地址改变 或 达到剩余次数
 	if d.Addr != newAddr || d.Times <= 1 {
-		IPCacheTimes, err := strconv.Atoi(os.Getenv(IPCacheTimesENV))
-		if err != nil {
-			IPCacheTimes = 5
-		}
 		d.Addr = newAddr
-		d.Times = IPCacheTimes + 1
+		d.Times = d.getCacheTimes() + 1
+		d.Provenance = IPFresh
 		return true
 	}
 	d.Addr = newAddr
 	d.Times--
+	d.Provenance = IPCached
 	return false
 }
+
+// CheckDomain 单独设置了缓存次数的域名是否需与DNS服务商比对, 地址改变或达到该域名的缓存次数时返回 true
+func (d *IpCache) CheckDomain(name string, times int, changed bool) bool {
+	if d.DomainTimes == nil {
+		d.DomainTimes = map[string]int{}
+	}
+	remaining, ok := d.DomainTimes[name]
+	if changed || !ok || remaining <= 1 {
+		d.DomainTimes[name] = times + 1
+		return true
+	}
+	d.DomainTimes[name] = remaining - 1
+	return false
+}
+
+// Stable 新地址连续 StableTimes 次相同后才返回true, 防止IP频繁变化导致记录抖动
+func (d *IpCache) Stable(newAddr string) bool {
+	if newAddr == "" || d.StableTimes <= 1 || d.Addr == "" || d.Addr == newAddr {
+		d.PendingAddr = ""
+		d.PendingTimes = 0
+		return true
+	}
+	if d.PendingAddr != newAddr {
+		d.PendingAddr = newAddr
+		d.PendingTimes = 0
+	}
+	d.PendingTimes++
+	if d.PendingTimes < d.StableTimes {
+		d.Provenance = IPPending
+		return false
+	}
+	d.PendingAddr = ""
+	d.PendingTimes = 0
+	return true
+}
+
+// getCacheTimes 获得缓存次数, 优先使用自定义的缓存次数
+func (d *IpCache) getCacheTimes() int {
+	if d.CustomTimes > 0 {
+		return d.CustomTimes
+	}
+	IPCacheTimes, err := strconv.Atoi(os.Getenv(IPCacheTimesENV))
+	if err != nil {
+		IPCacheTimes = 5
+	}
+	return IPCacheTimes
+}
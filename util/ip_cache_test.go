@@ -0,0 +1,79 @@
+package util
+
+import (
+	"os"
+	"testing"
+)
+
+// TestIpCacheCustomTimes 测试自定义缓存次数
+func TestIpCacheCustomTimes(t *testing.T) {
+	os.Setenv(IPCacheTimesENV, "5")
+	defer os.Unsetenv(IPCacheTimesENV)
+
+	tests := []struct {
+		name        string
+		customTimes int
+		expected    int
+	}{
+		{"Global cache times", 0, 6},
+		{"Custom cache times", 2, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cache := &IpCache{CustomTimes: tt.customTimes}
+			if !cache.Check("127.0.0.1") {
+				t.Fatalf("Expected first check to be true")
+			}
+			if cache.Times != tt.expected {
+				t.Errorf("Expected times %d, got %d", tt.expected, cache.Times)
+			}
+		})
+	}
+}
+
+// TestIpCacheStable 测试IP需连续相同N次后才更新
+func TestIpCacheStable(t *testing.T) {
+	cache := &IpCache{Addr: "2001:db8::1", StableTimes: 3}
+
+	if !cache.Stable("2001:db8::1") {
+		t.Fatalf("Expected unchanged address to be stable")
+	}
+	if cache.Stable("2001:db8::2") || cache.Stable("2001:db8::2") {
+		t.Fatalf("Expected new address to be pending")
+	}
+	// 中途变化则重新计数
+	if cache.Stable("2001:db8::3") || cache.Stable("2001:db8::3") {
+		t.Fatalf("Expected changed pending address to restart counting")
+	}
+	if !cache.Stable("2001:db8::3") {
+		t.Fatalf("Expected address to be stable after 3 times")
+	}
+	if cache.PendingAddr != "" || cache.PendingTimes != 0 {
+		t.Errorf("Expected pending state to be reset, got %s %d", cache.PendingAddr, cache.PendingTimes)
+	}
+
+	// 首次获取立即更新
+	if !(&IpCache{StableTimes: 3}).Stable("2001:db8::1") {
+		t.Errorf("Expected first address to be stable")
+	}
+}
+
+// TestIpCacheProvenance 测试记录结果来自缓存还是与服务商比对
+func TestIpCacheProvenance(t *testing.T) {
+	os.Setenv(IPCacheTimesENV, "2")
+	defer os.Unsetenv(IPCacheTimesENV)
+
+	cache := &IpCache{}
+	for i, expected := range []string{IPFresh, IPCached, IPCached, IPFresh} {
+		cache.Check("127.0.0.1")
+		if cache.Provenance != expected {
+			t.Errorf("第 %d 次期待 %s, 得到 %s", i+1, expected, cache.Provenance)
+		}
+	}
+
+	cache.StableTimes = 2
+	if cache.Stable("127.0.0.2") || cache.Provenance != IPPending {
+		t.Errorf("期待 %s, 得到 %s", IPPending, cache.Provenance)
+	}
+}
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// lastIPURL 最近一次获取IP成功的接口, 如 IPv4 => https://api.ipify.org
+var lastIPURL sync.Map
+
+// ipURLTimeout 每个获取IP接口的超时时间
+var ipURLTimeout = 10 * time.Second
+
+// SetIPURLTimeout 设置每个获取IP接口的超时时间
+func SetIPURLTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		ipURLTimeout = timeout
+	}
+}
+
+// GetIPFromURLs 按顺序依次请求以逗号分隔的接口, 直到有接口返回有效的IP
+// network 为 tcp4 或 tcp6
+func GetIPFromURLs(network string, urls string, reg *regexp.Regexp) string {
+	addrType := "IPv4"
+	if network == "tcp6" {
+		addrType = "IPv6"
+	}
+
+	client := CreateNoProxyHTTPClient(network)
+	client.Timeout = ipURLTimeout
+
+	for _, url := range strings.Split(urls, ",") {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		resp, err := client.Get(url)
+		if err != nil {
+			Log("通过接口获取%s失败! 接口地址: %s", addrType, url)
+			Log("异常信息: %s", err)
+			continue
+		}
+		lr := io.LimitReader(resp.Body, 1024000)
+		body, err := io.ReadAll(lr)
+		resp.Body.Close()
+		if err != nil {
+			Log("异常信息: %s", err)
+			continue
+		}
+		result := reg.FindString(traceIP(string(body)))
+		if result == "" {
+			Log("获取%s结果失败! 接口: %s ,返回值: %s", addrType, url, string(body))
+			continue
+		}
+		Log("通过接口 %s 获取%s成功: %s", url, addrType, result)
+		lastIPURL.Store(addrType, url)
+		return result
+	}
+	return ""
+}
+
+// traceIP 服务商返回连接IP的接口为 key=value 格式时只匹配 ip= 所在的行
+// 如 Cloudflare 的 https://cloudflare.com/cdn-cgi/trace
+func traceIP(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if ip, ok := strings.CutPrefix(strings.TrimSpace(line), "ip="); ok {
+			return ip
+		}
+	}
+	return body
+}
+
+// LastIPURL 获得最近一次获取IP成功的接口, addrType 为 IPv4 或 IPv6
+func LastIPURL(addrType string) string {
+	url, _ := lastIPURL.Load(addrType)
+	s, _ := url.(string)
+	return s
+}
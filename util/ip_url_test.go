@@ -0,0 +1,25 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+// TestGetIPFromTrace 测试从 Cloudflare trace 格式的返回值中获取IP
+func TestGetIPFromTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("fl=28f123\nh=cloudflare.com\nip=203.0.113.7\nts=1700000000.123\nvisit_scheme=https\ncolo=SJC\n"))
+	}))
+	defer server.Close()
+
+	reg := regexp.MustCompile(`((25[0-5]|(2[0-4]|1{0,1}[0-9]){0,1}[0-9])\.){3,3}(25[0-5]|(2[0-4]|1{0,1}[0-9]){0,1}[0-9])`)
+	if ip := GetIPFromURLs("tcp4", server.URL+"/cdn-cgi/trace", reg); ip != "203.0.113.7" {
+		t.Errorf("期待 203.0.113.7, 得到 %s", ip)
+	}
+
+	if ip := traceIP("Current IP: 198.51.100.1"); ip != "Current IP: 198.51.100.1" {
+		t.Errorf("非 trace 格式时应返回原内容, 得到 %s", ip)
+	}
+}
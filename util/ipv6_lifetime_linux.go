@@ -0,0 +1,59 @@
+package util
+
+import (
+	"encoding/binary"
+	"math"
+	"net"
+	"syscall"
+	"time"
+)
+
+// getIPv6Lifetimes 通过 netlink 获得网卡上IPv6地址的首选有效期, 永久地址为最大值
+func getIPv6Lifetimes(index int) map[string]time.Duration {
+	lifetimes := make(map[string]time.Duration)
+	tab, err := syscall.NetlinkRIB(syscall.RTM_GETADDR, syscall.AF_INET6)
+	if err != nil {
+		return lifetimes
+	}
+	msgs, err := syscall.ParseNetlinkMessage(tab)
+	if err != nil {
+		return lifetimes
+	}
+
+	for _, m := range msgs {
+		if m.Header.Type != syscall.RTM_NEWADDR || len(m.Data) < syscall.SizeofIfAddrmsg {
+			continue
+		}
+		// struct ifaddrmsg 中的 ifa_index
+		if int(binary.NativeEndian.Uint32(m.Data[4:8])) != index {
+			continue
+		}
+		attrs, err := syscall.ParseNetlinkRouteAttr(&m)
+		if err != nil {
+			continue
+		}
+
+		var ip net.IP
+		var preferred time.Duration
+		for _, attr := range attrs {
+			switch attr.Attr.Type {
+			case syscall.IFA_ADDRESS:
+				ip = net.IP(attr.Value)
+			case syscall.IFA_CACHEINFO:
+				// struct ifa_cacheinfo 中的 ifa_prefered, 单位秒
+				if len(attr.Value) >= 4 {
+					seconds := binary.NativeEndian.Uint32(attr.Value[0:4])
+					if seconds == math.MaxUint32 {
+						preferred = ipv6Forever
+					} else {
+						preferred = time.Duration(seconds) * time.Second
+					}
+				}
+			}
+		}
+		if ip != nil {
+			lifetimes[ip.String()] = preferred
+		}
+	}
+	return lifetimes
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package util
+
+import "time"
+
+// getIPv6Lifetimes 当前系统不支持获取IPv6地址的有效期
+func getIPv6Lifetimes(index int) map[string]time.Duration {
+	return nil
+}
@@ -0,0 +1,142 @@
+package util
+
+import (
+	"math"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IPv6地址的范围
+const (
+	IPv6ScopeGlobal    = "global"
+	IPv6ScopeULA       = "ula"
+	IPv6ScopeLinkLocal = "linklocal"
+)
+
+// DefaultIPv6ScopeOrder 默认的IPv6范围优先级
+const DefaultIPv6ScopeOrder = "global,ula,linklocal"
+
+// ipv6Forever 永久地址的有效期
+const ipv6Forever = time.Duration(math.MaxInt64)
+
+// _, ipv6Global, _ = net.ParseCIDR("2000::/3")
+var ipv6Global = &net.IPNet{IP: net.ParseIP("2000::"), Mask: net.CIDRMask(3, 128)}
+
+// IPv6Addr 网卡上的IPv6地址
+type IPv6Addr struct {
+	IP    net.IP
+	Scope string
+	// 链路本地地址的区域ID, 即网卡名称, 如 eth0
+	Zone string
+	// 首选有效期, 未知时为0
+	PreferredLifetime time.Duration
+}
+
+func (addr IPv6Addr) String() string {
+	if addr.PreferredLifetime == ipv6Forever {
+		return addr.Addr() + "(" + addr.Scope + ", forever)"
+	}
+	if addr.PreferredLifetime > 0 {
+		return addr.Addr() + "(" + addr.Scope + ", " + addr.PreferredLifetime.String() + ")"
+	}
+	return addr.Addr() + "(" + addr.Scope + ")"
+}
+
+// Addr 地址, 链路本地地址带有区域ID, 如 fe80::1%eth0
+func (addr IPv6Addr) Addr() string {
+	if addr.Zone != "" {
+		return addr.IP.String() + "%" + addr.Zone
+	}
+	return addr.IP.String()
+}
+
+// SplitIPv6Zone 拆分地址及区域ID, 如 fe80::1%eth0 拆分为 fe80::1 及 eth0, 没有区域ID时 zone 为空
+// DNS记录不能包含区域ID, 更新记录时需去除
+func SplitIPv6Zone(addr string) (ip string, zone string) {
+	ip, zone, _ = strings.Cut(addr, "%")
+	return
+}
+
+// GetIPv6Scope 获得IPv6地址的范围, 不支持的地址返回空
+func GetIPv6Scope(ip net.IP) string {
+	if ip == nil || ip.To4() != nil {
+		return ""
+	}
+	switch {
+	case ipv6Global.Contains(ip):
+		return IPv6ScopeGlobal
+	case ip.IsPrivate():
+		// fc00::/7
+		return IPv6ScopeULA
+	case ip.IsLinkLocalUnicast():
+		return IPv6ScopeLinkLocal
+	}
+	return ""
+}
+
+// GetInterfaceIPv6Addrs 获得网卡上的全部IPv6地址, 支持的系统中包含首选有效期
+func GetInterfaceIPv6Addrs(name string) ([]IPv6Addr, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	lifetimes := getIPv6Lifetimes(iface.Index)
+	var result []IPv6Addr
+	for _, address := range addrs {
+		ipnet, ok := address.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		scope := GetIPv6Scope(ipnet.IP)
+		if scope == "" {
+			continue
+		}
+		zone := ""
+		if scope == IPv6ScopeLinkLocal {
+			zone = iface.Name
+		}
+		result = append(result, IPv6Addr{
+			IP:                ipnet.IP,
+			Scope:             scope,
+			Zone:              zone,
+			PreferredLifetime: lifetimes[ipnet.IP.String()],
+		})
+	}
+	return result, nil
+}
+
+// SortIPv6ByScope 按范围的优先级排序, 同一范围内首选有效期长的优先
+// order 以逗号分隔, 如 global,ula,linklocal, 不在其中的地址被忽略
+func SortIPv6ByScope(addrs []IPv6Addr, order string) []IPv6Addr {
+	if strings.TrimSpace(order) == "" {
+		order = DefaultIPv6ScopeOrder
+	}
+	priority := make(map[string]int)
+	for i, scope := range strings.Split(order, ",") {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if _, ok := priority[scope]; !ok {
+			priority[scope] = i
+		}
+	}
+
+	var result []IPv6Addr
+	for _, addr := range addrs {
+		if _, ok := priority[addr.Scope]; ok {
+			result = append(result, addr)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if priority[result[i].Scope] != priority[result[j].Scope] {
+			return priority[result[i].Scope] < priority[result[j].Scope]
+		}
+		return result[i].PreferredLifetime > result[j].PreferredLifetime
+	})
+	return result
+}
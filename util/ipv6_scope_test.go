@@ -0,0 +1,79 @@
+package util
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// TestGetIPv6Scope 测试IPv6地址的范围
+func TestGetIPv6Scope(t *testing.T) {
+	data := map[string]string{
+		"2001:db8::1": IPv6ScopeGlobal,
+		"fd00::1":     IPv6ScopeULA,
+		"fe80::1":     IPv6ScopeLinkLocal,
+		"::1":         "",
+		"192.168.1.1": "",
+	}
+	for ip, expected := range data {
+		if got := GetIPv6Scope(net.ParseIP(ip)); got != expected {
+			t.Errorf("%s 期待 %q, 得到 %q", ip, expected, got)
+		}
+	}
+}
+
+// TestSortIPv6ByScope 测试按范围优先级及有效期排序
+func TestSortIPv6ByScope(t *testing.T) {
+	addrs := []IPv6Addr{
+		{IP: net.ParseIP("fe80::1"), Scope: IPv6ScopeLinkLocal},
+		{IP: net.ParseIP("fd00::1"), Scope: IPv6ScopeULA},
+		{IP: net.ParseIP("2001:db8::1"), Scope: IPv6ScopeGlobal, PreferredLifetime: time.Hour},
+		{IP: net.ParseIP("2001:db8::2"), Scope: IPv6ScopeGlobal, PreferredLifetime: ipv6Forever},
+	}
+
+	tests := []struct {
+		order    string
+		expected []string
+	}{
+		{"", []string{"2001:db8::2", "2001:db8::1", "fd00::1", "fe80::1"}},
+		{"ula, global", []string{"fd00::1", "2001:db8::2", "2001:db8::1"}},
+		{"linklocal", []string{"fe80::1"}},
+	}
+
+	for _, tt := range tests {
+		got := SortIPv6ByScope(addrs, tt.order)
+		if len(got) != len(tt.expected) {
+			t.Fatalf("%q 期待 %v, 得到 %v", tt.order, tt.expected, got)
+		}
+		for i := range got {
+			if got[i].IP.String() != tt.expected[i] {
+				t.Errorf("%q 期待 %v, 得到 %v", tt.order, tt.expected, got)
+				break
+			}
+		}
+	}
+}
+
+// TestSplitIPv6Zone 测试拆分带有区域ID的地址
+func TestSplitIPv6Zone(t *testing.T) {
+	data := []struct {
+		addr string
+		ip   string
+		zone string
+	}{
+		{"fe80::1%eth0", "fe80::1", "eth0"},
+		{"fe80::1%25", "fe80::1", "25"},
+		{"2001:db8::1", "2001:db8::1", ""},
+		{"", "", ""},
+	}
+	for _, d := range data {
+		if ip, zone := SplitIPv6Zone(d.addr); ip != d.ip || zone != d.zone {
+			t.Errorf("%s 期待 %s %s, 得到 %s %s", d.addr, d.ip, d.zone, ip, zone)
+		}
+	}
+
+	addr := IPv6Addr{IP: net.ParseIP("fe80::1"), Scope: IPv6ScopeLinkLocal, Zone: "eth0"}
+	if addr.Addr() != "fe80::1%eth0" {
+		t.Errorf("期待 fe80::1%%eth0, 得到 %s", addr.Addr())
+	}
+}
@@ -16,6 +16,13 @@ func init() {
 	message.SetString(language.English, "可使用 .\\ddns-go.exe -s install 安装服务运行", "You can use '.\\ddns-go.exe -s install' to install service")
 	message.SetString(language.English, "可使用 sudo ./ddns-go -s install 安装服务运行", "You can use 'sudo ./ddns-go -s install' to install service")
 	message.SetString(language.English, "监听 %s", "Listening on %s")
+	message.SetString(language.English, "已从 %s 获取配置, 缓存在: %s", "Fetched config from %s, cached in: %s")
+	message.SetString(language.English, "获取远程配置失败, 将使用本地缓存: %s", "Failed to fetch remote config, will use the local cache: %s")
+	message.SetString(language.English, "请输入配置文件密码: ", "Please enter the config file passphrase: ")
+	message.SetString(language.English, "配置文件已加密, 请通过环境变量 %s 设置密码", "The config file is encrypted, please set the passphrase via the environment variable %s")
+	message.SetString(language.English, "配置文件解密失败, 请检查密码是否正确", "Failed to decrypt the config file, please check the passphrase")
+	message.SetString(language.English, "配置文件 %s 不存在, 已跳过", "Config file %s does not exist, skipped")
+	message.SetString(language.English, "合并后的第 %s 个配置未指定DNS服务商", "The %s config has no DNS provider after merging")
 	message.SetString(language.English, "配置文件已保存在: %s", "Config file has been saved to: %s")
 
 	message.SetString(language.English, "你的IP %s 没有变化, 域名 %s", "Your's IP %s has not changed! Domain: %s")
@@ -28,6 +35,15 @@ func init() {
 	message.SetString(language.English, "你的IPv4未变化, 未触发 %s 请求", "Your's IPv4 has not changed, %s request has not been triggered")
 	message.SetString(language.English, "你的IPv6未变化, 未触发 %s 请求", "Your's IPv6 has not changed, %s request has not been triggered")
 	message.SetString(language.English, "Namecheap 不支持更新 IPv6", "Namecheap does not support IPv6")
+	message.SetString(language.English, "未填写更新地址", "Update URL is empty")
+	message.SetString(language.English, "该功能需要付费账户", "This feature requires a paid account")
+	message.SetString(language.English, "主机名不是完整域名", "The hostname is not a fully-qualified domain name")
+	message.SetString(language.English, "主机名不存在", "The hostname does not exist")
+	message.SetString(language.English, "主机名数量过多", "Too many hostnames")
+	message.SetString(language.English, "主机名因滥用已被封禁", "The hostname is blocked for abuse")
+	message.SetString(language.English, "User-Agent 被拒绝", "The User-Agent was rejected")
+	message.SetString(language.English, "服务商 DNS 错误", "DNS error on the provider side")
+	message.SetString(language.English, "服务商维护中", "The provider is under maintenance")
 
 	message.SetString(language.English, "dynadot仅支持单域名配置，多个域名请添加更多配置", "dynadot only supports single domain configuration, please add more configurations")
 
@@ -35,8 +51,9 @@ func init() {
 	message.SetString(language.English, "异常信息: %s", "Exception: %s")
 	message.SetString(language.English, "查询域名信息发生异常! %s", "Failed to query domain info! %s")
 	message.SetString(language.English, "返回内容: %s ,返回状态码: %d", "Response body: %s ,Response status code: %d")
-	message.SetString(language.English, "通过接口获取IPv4失败! 接口地址: %s", "Failed to get IPv4 from %s")
-	message.SetString(language.English, "通过接口获取IPv6失败! 接口地址: %s", "Failed to get IPv6 from %s")
+	message.SetString(language.English, "返回内容: %s", "Response body: %s")
+	message.SetString(language.English, "通过接口获取%s失败! 接口地址: %s", "Failed to get %s from %s")
+	message.SetString(language.English, "通过接口 %s 获取%s成功: %s", "Successfully got %[2]s from %[1]s: %[3]s")
 	message.SetString(language.English, "将不会触发Webhook, 仅在第 3 次失败时触发一次Webhook, 当前失败次数：%d", "Webhook will not be triggered, only trigger once when the third failure, current failure times: %d")
 	message.SetString(language.English, "在DNS服务商中未找到根域名: %s", "Root domain not found in DNS provider: %s")
 
@@ -45,29 +62,185 @@ func init() {
 	message.SetString(language.English, "Webhook中的 RequestBody JSON 无效", "Webhook RequestBody JSON is invalid")
 	message.SetString(language.English, "Webhook调用成功! 返回数据：%s", "Successfully called Webhook! Response body: %s")
 	message.SetString(language.English, "Webhook调用失败! 异常信息：%s", "Failed to call Webhook! Exception: %s")
-	message.SetString(language.English, "Webhook Header不正确: %s", "Webhook header is invalid: %s")
+	message.SetString(language.English, "Webhook调用失败! 返回状态码: %d, 返回数据：%s", "Failed to call Webhook! Status code: %d, response body: %s")
+	message.SetString(language.English, "Webhook成功状态码不正确: %s", "Webhook success status code is incorrect: %s")
+	message.SetString(language.English, "Header不正确: %s", "Header is invalid: %s")
 	message.SetString(language.English, "请输入Webhook的URL", "Please enter the Webhook url")
+	message.SetString(language.English, "域名 %s 的Webhook URL不正确", "Webhook url of domain %s is incorrect")
+	message.SetString(language.English, "域名 %s 的Webhook调用成功! 返回数据：%s", "Successfully called Webhook of domain %s! Response body: %s")
+	message.SetString(language.English, "域名 %s 的Webhook调用失败! 异常信息：%s", "Failed to call Webhook of domain %s! Exception: %s")
+
+	// notify
+	message.SetString(language.English, "更新后命令执行成功! 输出: %s", "Successfully ran the post-update command! Output: %s")
+	message.SetString(language.English, "更新后命令执行失败! 异常信息: %s, 输出: %s", "Failed to run the post-update command! Exception: %s, output: %s")
+	message.SetString(language.English, "%s通知发送成功! 返回数据：%s", "Successfully sent %s notification! Response body: %s")
+	message.SetString(language.English, "%s通知发送失败! 异常信息：%s", "Failed to send %s notification! Exception: %s")
 
 	// callback
 	message.SetString(language.English, "Callback的URL不正确", "Callback url is incorrect")
+
+	// ptr
+	message.SetString(language.English, "更新PTR记录 %s 成功! IP: %s", "Updated PTR record of %s successfully! IP: %s")
+	message.SetString(language.English, "更新PTR记录 %s 失败! 异常信息: %s", "Failed to update PTR record of %s! Exception: %s")
+	message.SetString(language.English, "%s 不支持更新PTR记录", "%s does not support updating PTR records")
+	message.SetString(language.English, "%s 不支持托管区域", "%s does not support managed zone")
+	message.SetString(language.English, "%s 不支持删除记录", "%s does not support deleting records")
+	message.SetString(language.English, "登录有效期不正确", "The session timeout is incorrect")
+	message.SetString(language.English, "网卡 %s 未启用或没有公网%s地址, 跳过本次更新", "Interface %s is down or has no global %s address, skip this update")
+	message.SetString(language.English, "不支持导入已加密的配置文件", "Importing an encrypted config file is not supported")
+	message.SetString(language.English, "配置中包含已隐藏的密钥, 请导出包含密钥的配置后再导入", "The config contains redacted secrets, please export the config with secrets and import it again")
+	message.SetString(language.English, "配置文件格式不正确: %s", "Incorrect config file format: %s")
+	message.SetString(language.English, "第 %s 个配置未选择DNS服务商", "The %s config has no DNS provider selected")
+	message.SetString(language.English, "%q 导出了包含密钥的配置文件", "%q exported the config file with secrets")
+	message.SetString(language.English, "%q 导入了配置文件", "%q imported the config file")
+	message.SetString(language.English, "只读用户的用户名不能与管理员相同", "The read-only username cannot be the same as the administrator")
+	message.SetString(language.English, "必须输入只读用户的密码", "The read-only password is required")
+	message.SetString(language.English, "%q 为只读用户, 禁止访问 %s", "%q is a read-only user, access to %s is forbidden")
+	message.SetString(language.English, "%q 以只读用户登录", "%q logged in as a read-only user")
+	message.SetString(language.English, "监听地址 %s 不正确, IPv6地址需使用中括号并指定端口, 如 [::1]:9876", "The listen address %s is incorrect, IPv6 addresses must be enclosed in brackets with a port, such as [::1]:9876")
+	message.SetString(language.English, "监听地址 %s 不正确, 应为 :9876、127.0.0.1:9876 或 [::1]:9876", "The listen address %s is incorrect, it should be like :9876, 127.0.0.1:9876 or [::1]:9876")
+	message.SetString(language.English, "监听地址 %s 的端口 %s 不正确", "The port %[2]s of the listen address %[1]s is incorrect")
+	message.SetString(language.English, "监听地址 %s 不正确: %s", "The listen address %s is incorrect: %s")
+	message.SetString(language.English, "%s 已限流, 将在 %s 之后再请求", "%s is rate limited, requests resume after %s")
+	message.SetString(language.English, "%s 返回 %d, 将在 %s 后重试", "%s returned %d, retry in %s")
+	message.SetString(language.English, "%s 返回 %d, 将在 %s 后再请求", "%s returned %d, requests resume in %s")
+	message.SetString(language.English, "环境变量 %s 的值 %q 不正确", "The value %[2]q of the environment variable %[1]s is incorrect")
+	message.SetString(language.English, "环境变量中第 %d 个DNS配置未设置 %s", "DNS config %d in the environment variables does not set %s")
+	message.SetString(language.English, "环境变量中第 %d 个DNS配置未启用IPv4及IPv6", "DNS config %d in the environment variables enables neither IPv4 nor IPv6")
+	message.SetString(language.English, "%s 由环境变量设置, 修改不会保存", "%s is set by an environment variable, the change is not saved")
+	message.SetString(language.English, "托管区域查询失败, 跳过删除过期记录! 异常信息: %s", "Failed to query the managed zones, skip deleting stale records! Exception: %s")
+	message.SetString(language.English, "上一次更新尚未完成, 将在完成后更新", "The previous update has not finished, will update after it finishes")
+	message.SetString(language.English, "使用多个配置文件时无法保存, 请直接修改配置文件", "Cannot save when multiple configuration files are used, please edit the files directly")
+	message.SetString(language.English, "使用远程配置时无法保存, 请修改远程配置文件", "Cannot save when the configuration is fetched from a URL, please edit the remote file")
+	message.SetString(language.English, "只使用环境变量中的配置, 无法保存", "Only the configuration from environment variables is used, it cannot be saved")
+	message.SetString(language.English, "已从环境变量中读取配置: %s", "Loaded the configuration from environment variables: %s")
+	message.SetString(language.English, "第 %d 个DNS配置: %s(%s), IPv4域名: %s, IPv6域名: %s", "DNS config %d: %s(%s), IPv4 domains: %s, IPv6 domains: %s")
+	message.SetString(language.English, "代理地址不正确: %s", "The proxy URL is incorrect: %s")
+	message.SetString(language.English, "%s, 将不使用代理", "%s, the proxy will not be used")
+	message.SetString(language.English, "维护时段格式不正确, 如 02:00-04:00", "The maintenance window is incorrect, such as 02:00-04:00")
+	message.SetString(language.English, "时区不正确: %s", "Incorrect timezone: %s")
+	message.SetString(language.English, "维护时段 %s 内, 跳过更新. IPv4: %s, IPv6: %s", "In maintenance window %s, skip updating. IPv4: %s, IPv6: %s")
+	message.SetString(language.English, "域名 %s 已生效, 解析到 %s", "Domain %s has propagated, resolved to %s")
+	message.SetString(language.English, "查询域名 %s 失败, 将与DNS服务商比对. 异常信息: %s", "Failed to query domain %s, comparing with the DNS provider. Exception: %s")
+	message.SetString(language.English, "第 %s 个配置未填写 %s 的ID", "The %s config is missing the ID of %s")
+	message.SetString(language.English, "第 %s 个配置未填写 %s 的Secret", "The %s config is missing the Secret of %s")
+	message.SetString(language.English, "第 %s 个配置的TTL %s 不正确", "The TTL %[2]s of the %[1]s config is incorrect")
+	message.SetString(language.English, "第 %s 个配置的域名 %s 不正确", "The domain %[2]s of the %[1]s config is incorrect")
+	message.SetString(language.English, "配置正确", "The configuration is valid")
+	message.SetString(language.English, "域名 %s 不符合DNS规则, 已忽略: %s", "Domain %s does not follow DNS rules, ignored: %s")
+	message.SetString(language.English, "域名超过 %d 个字符", "The domain is longer than %d characters")
+	message.SetString(language.English, "包含空的标签", "It contains an empty label")
+	message.SetString(language.English, "标签 %s 超过 %d 个字符", "Label %s is longer than %d characters")
+	message.SetString(language.English, "标签 %s 不能以连字符开头或结尾", "Label %s cannot start or end with a hyphen")
+	message.SetString(language.English, "标签 %s 包含下划线, 该配置不允许下划线", "Label %s contains an underscore, which is not allowed by this config")
+	message.SetString(language.English, "标签 %s 包含不允许的字符 %q", "Label %s contains the invalid character %q")
+	message.SetString(language.English, "自动申请 %s 的证书, 缓存目录: %s", "Obtaining certificates for %s automatically, cache directory: %s")
+	message.SetString(language.English, "自动申请 %s 的证书失败, 将使用指定的证书: %s", "Failed to obtain the certificate for %s, using the specified certificate: %s")
+	message.SetString(language.English, "读取CA证书失败: %s", "Failed to read the CA certificate: %s")
+	message.SetString(language.English, "CA证书中没有可用的证书", "No usable certificate in the CA certificate")
+	message.SetString(language.English, "加载HTTPS证书失败: %s", "Failed to load the HTTPS certificate: %s")
+	message.SetString(language.English, "使用 -http3 时需同时指定 -tlsCert -tlsKey 或 -tlsAutocert", "-http3 requires -tlsCert -tlsKey or -tlsAutocert")
+	message.SetString(language.English, "监听 %s (HTTP/3)", "Listening on %s (HTTP/3)")
+	message.SetString(language.English, "域名 %s 已更新为 %s, 跳过更新", "Domain %s was already updated to %s, skip updating")
+	message.SetString(language.English, "DNS服务器 %s 返回域名 %s 已解析到 %s, 跳过更新", "DNS server %[1]s shows domain %[2]s already resolves to %[3]s, skip updating")
+	message.SetString(language.English, "域名 %s 在 %s 内未生效, DNS服务器 %s 返回: %s", "Domain %s has not propagated within %s, DNS server %s answered: %s")
+	message.SetString(language.English, "记录类型 %s 不正确, 只支持 A、AAAA、both", "Record type %s is incorrect, only A, AAAA and both are supported")
+	message.SetString(language.English, "Njalla 不支持TTL %s, 将使用默认值", "Njalla does not support TTL %s, the default will be used")
+	message.SetString(language.English, "网卡 %s 的IPv6候选地址: %s, 将使用: %s", "IPv6 candidates of %s: %s, using: %s")
+	message.SetString(language.English, "[看门狗] 已超过 %s 未完成更新, 更新可能已停滞!", "[watchdog] No update completed for %s, updates may be stalled!")
+	message.SetString(language.English, "[看门狗] 重新启动定时更新", "[watchdog] Restarting the update loop")
+	message.SetString(language.English, "[看门狗] 退出程序", "[watchdog] Exiting")
+	message.SetString(language.English, "区域 %s 不包含域名 %s, 将使用根域名", "Zone %s does not contain domain %s, the root domain will be used")
+	message.SetString(language.English, "[调试] 请求: %s %s %s", "[debug] Request: %s %s %s")
+	message.SetString(language.English, "[调试] 响应: %s %s %s", "[debug] Response: %s %s %s")
+	message.SetString(language.English, "[调试] 请求 %s %s 失败: %s", "[debug] Request %s %s failed: %s")
+	message.SetString(language.English, "域名 %s 的 source 参数不正确, 将使用配置的获取IP方式", "The source parameter of domain %s is incorrect, the configured get IP method will be used")
+	message.SetString(language.English, "删除域名解析 %s 失败! 异常信息: %s", "Failed to delete %s! Exception: %s")
+	message.SetString(language.English, "未获取到IP, 已删除域名解析 %s(%s)", "No IP detected, deleted %s(%s)")
+	message.SetString(language.English, "已暂停所有更新, 跳过本次更新", "All updates are paused, skip this update")
+	message.SetString(language.English, "IPv6地址 %s 带有区域ID, 更新记录时将使用 %s", "IPv6 address %s has a zone ID, %s will be used for records")
+	message.SetString(language.English, "本次运行完成: %d 个域名, %d 个已更新, %d 个未改变, %d 个失败, ipv4=%s, ipv6=%s", "cycle done: %d domains, %d changed, %d unchanged, %d failed, ipv4=%s, ipv6=%s")
+	message.SetString(language.English, "部分域名更新失败, IPv4: %s, IPv6: %s", "Some domains failed to update, IPv4: %s, IPv6: %s")
+	message.SetString(language.English, "%q 暂停了所有更新", "%q paused all updates")
+	message.SetString(language.English, "%q 恢复了所有更新", "%q resumed all updates")
+	message.SetString(language.English, "托管区域 %s 查询失败, 跳过删除过期记录", "Failed to query managed zone %s, skip deleting stale records")
+	message.SetString(language.English, "删除过期记录 %s(%s) 成功! IP: %s", "Deleted stale record %s(%s)! IP: %s")
+	message.SetString(language.English, "删除过期记录 %s(%s) 失败! 异常信息: %s", "Failed to delete stale record %s(%s)! Exception: %s")
+	message.SetString(language.English, "在DNS服务商中未找到反向解析区域: %s", "Reverse zone not found in DNS provider: %s")
+	message.SetString(language.English, "未找到地址 %s 所在的网卡", "No network card found for address %s")
+	message.SetString(language.English, "从默认路由获取%s失败! 异常信息: %s", "Failed to get %s from the default route! Exception: %s")
+	message.SetString(language.English, "默认路由所在的网卡 %s 没有%s公网地址", "The network card %s of the default route has no global %s address")
+	message.SetString(language.English, "推送的%s %s 不正确", "The pushed %s %s is incorrect")
+	message.SetString(language.English, "尚未收到推送的%s", "No %s has been pushed yet")
+	message.SetString(language.English, "后缀 %s 不正确, 如: ::1", "The suffix %s is incorrect, such as: ::1")
+	message.SetString(language.English, "读取前缀文件 %s 失败: %s", "Failed to read the prefix file %s: %s")
+	message.SetString(language.English, "前缀文件 %s 中没有找到IPv6前缀", "No IPv6 prefix found in the prefix file %s")
+	message.SetString(language.English, "从文件 %s 获取到前缀 %s, 组合后的地址: %s", "Got prefix %[2]s from file %[1]s, composed address: %[3]s")
+	message.SetString(language.English, "配置不正确, 使用 -strict 时退出", "The configuration is invalid, exiting because of -strict")
+	message.SetString(language.English, "获取%s的方式与之前的配置相同, 使用已获取到的 %s", "%s is detected the same way as a previous config, using the detected %s")
+	message.SetString(language.English, "第 %s 个配置的域名 %s (%s) 重复", "The domain %[2]s (%[3]s) of the %[1]s config is duplicated")
+	message.SetString(language.English, "第 %s 个配置的域名 %s (%s) 与第 %s 个配置重复", "The domain %[2]s (%[3]s) of the %[1]s config duplicates the %[4]s config")
+	message.SetString(language.English, "重复域名的处理方式 %s 不正确, 只支持 warn、refuse", "The duplicate domains policy %s is incorrect, only warn and refuse are supported")
+	message.SetString(language.English, "Pushgateway 地址 %s 不正确", "The Pushgateway URL %s is incorrect")
+	message.SetString(language.English, "Pushgateway 的 job 不能为空", "The Pushgateway job cannot be empty")
+	message.SetString(language.English, "每隔 %s 推送指标到 Pushgateway, job: %s, instance: %s", "Pushing metrics to the Pushgateway every %s, job: %s, instance: %s")
+	message.SetString(language.English, "推送指标到 Pushgateway 失败: %s", "Failed to push metrics to the Pushgateway: %s")
+	message.SetString(language.English, "推送指标到 Pushgateway 已恢复", "Pushing metrics to the Pushgateway has recovered")
+	message.SetString(language.English, "开启Basic认证时必须输入用户名/密码", "Username/password is required when Basic auth is enabled")
+	message.SetString(language.English, "%q Basic认证的帐号密码不正确", "%q Basic auth username or password is incorrect")
+	message.SetString(language.English, "第 %s 个配置未填写前缀文件", "The prefix file of the %s config is empty")
+	message.SetString(language.English, "%q 触发更新, IPv4: %s, IPv6: %s", "%q triggered an update, IPv4: %s, IPv6: %s")
+	message.SetString(language.English, "%q 触发更新标签 %s 的域名, IPv4: %s, IPv6: %s", "%q triggered an update of domains tagged %s, IPv4: %s, IPv6: %s")
+	message.SetString(language.English, "没有带有标签 %s 的域名", "No domains are tagged %s")
+	message.SetString(language.English, "%q 触发更新第 %s 个配置", "%q triggered an update of the %s config")
+	message.SetString(language.English, "首次运行为试运行, 只获取IP并输出将要进行的更新, 下次运行时开始更新", "The first cycle is a dry run, only detecting IPs and logging intended changes. Updates start from the next cycle")
+	message.SetString(language.English, "试运行: 域名 %s 将更新为 %s, 查询当前解析失败: %s", "Dry run: domain %s will be updated to %s, failed to query the current record: %s")
+	message.SetString(language.English, "试运行: 域名 %s 已解析到 %s, 预计无需更新", "Dry run: domain %s already resolves to %s, no update expected")
+	message.SetString(language.English, "试运行: 域名 %s 当前解析为 %s, 将更新为 %s", "Dry run: domain %s currently resolves to %s, will be updated to %s")
+	message.SetString(language.English, "IP地址 %s 不正确", "IP address %s is incorrect")
+	message.SetString(language.English, "Callback的模板不正确, 异常信息: %s", "Callback template is incorrect! Exception: %s")
 	message.SetString(language.English, "Callback调用成功, 域名: %s, IP: %s, 返回数据: %s", "Successfully called Callback! Domain: %s, IP: %s, Response body: %s")
 	message.SetString(language.English, "Callback调用失败, 异常信息: %s", "Failed to call Callback! Exception: %s")
+	message.SetString(language.English, "Callback调用失败, 返回数据与 %s 不匹配: %s", "Failed to call Callback! Response body does not match %s: %s")
 
 	// save
 	message.SetString(language.English, "必须输入用户名/密码", "Username/Password is required")
 	message.SetString(language.English, "密码不安全！尝试使用更复杂的密码", "Password is not secure! Try using a more complex password")
 	message.SetString(language.English, "数据解析失败, 请刷新页面重试", "Data parsing failed, please refresh the page and try again")
+	message.SetString(language.English, "不支持 ddclient 的配置项 %s, 已忽略", "Unsupported ddclient directive %s, ignored")
+	message.SetString(language.English, "不支持 ddclient 的协议 %s, 已忽略主机 %s", "Unsupported ddclient protocol %s, host %s ignored")
+	message.SetString(language.English, "不支持 ddclient 的获取IP方式 %s, 将通过接口获取", "Unsupported ddclient IP detection method %s, the IP will be fetched from URLs")
+	message.SetString(language.English, "ddclient 主机 %s 使用了 Cloudflare 的邮箱及 Global API Key, 请改为API令牌", "ddclient host %s uses a Cloudflare email and Global API Key, please use an API token instead")
+	message.SetString(language.English, "读取 ddclient 配置失败: %s", "Failed to read the ddclient config: %s")
+	message.SetString(language.English, "ddclient 配置中没有可导入的域名", "No domains to import in the ddclient config")
+	message.SetString(language.English, "已导入 %s, IPv4域名: %s, IPv6域名: %s", "Imported %s, IPv4 domains: %s, IPv6 domains: %s")
+	message.SetString(language.English, "第 %s 个配置不存在", "The %s config does not exist")
+	message.SetString(language.English, "未填写凭证", "No credentials provided")
+	message.SetString(language.English, "只修改了DNS服务商的凭证, 将在下次更新时使用", "Only DNS provider credentials changed, they will be used on the next update")
+	message.SetString(language.English, "%q 修改了第 %s 个配置的凭证, 将在下次更新时使用", "%q changed the credentials of the %s config, they will be used on the next update")
 	message.SetString(language.English, "第 %s 个配置未填写域名", "The %s config does not fill in the domain")
+	message.SetString(language.English, "第 %s 个配置的稳定次数不正确", "The stable times of the %s config is incorrect")
+	message.SetString(language.English, "IPv4变化为 %s, 连续 %d/%d 次相同后更新", "IPv4 changed to %s, will update after it stays the same %d/%d times")
+	message.SetString(language.English, "IPv6变化为 %s, 连续 %d/%d 次相同后更新", "IPv6 changed to %s, will update after it stays the same %d/%d times")
+	message.SetString(language.English, "域名 %s 的记录不存在, 已开启只更新已有记录, 不会新增", "The record of domain %s does not exist and update only is enabled, not created")
+	message.SetString(language.English, "域名 %s 的缓存次数 %s 不正确, 需为大于0的整数, 已忽略", "The cache times %[2]s of domain %[1]s is incorrect, it must be an integer greater than 0, ignored")
+	message.SetString(language.English, "域名 %s 达到缓存次数, 与DNS服务商进行比对", "Domain %s reached its cache times, comparing with the DNS provider")
+	message.SetString(language.English, "域名 %s 未改变, 将等待 %d 次后与DNS服务商进行比对", "Domain %s unchanged, will compare with the DNS provider after %d times")
+	message.SetString(language.English, "域名 %s 的权重 %s 不正确, 需为0到100的整数, 已忽略", "The weight %[2]s of domain %[1]s is incorrect, it must be an integer from 0 to 100, ignored")
+	message.SetString(language.English, "更新域名 %s 的权重为 %s 成功", "Updated the weight of domain %s to %s successfully")
+	message.SetString(language.English, "更新域名 %s 的权重失败! 异常信息: %s", "Failed to update the weight of domain %s! Exception: %s")
+	message.SetString(language.English, "第 %s 个配置的更新间隔不正确", "The interval of the %s config is incorrect")
+	message.SetString(language.English, "第 %s 个配置每 %s 更新一次, 域名: %s", "The %s config is updated every %s, domains: %s")
+	message.SetString(language.English, "第 %s 个配置的缓存次数不正确", "The cache times of the %s config is incorrect")
 
 	// config
 	message.SetString(language.English, "从网卡获得IPv4失败", "Failed to get IPv4 from network card")
 	message.SetString(language.English, "从网卡中获得IPv4失败! 网卡名: %s", "Failed to get IPv4 from network card! Network card name: %s")
-	message.SetString(language.English, "获取IPv4结果失败! 接口: %s ,返回值: %s", "Failed to get IPv4 result! Interface: %s ,Result: %s")
+	message.SetString(language.English, "获取%s结果失败! 接口: %s ,返回值: %s", "Failed to get %s result! Interface: %s ,Result: %s")
 	message.SetString(language.English, "获取%s结果失败! 未能成功执行命令：%s, 错误：%q, 退出状态码：%s", "Failed to get %s result! Command: %s, Error: %q, Exit status code: %s")
 	message.SetString(language.English, "获取%s结果失败! 命令: %s, 标准输出: %q", "Failed to get %s result! Command: %s, Stdout: %q")
 	message.SetString(language.English, "从网卡获得IPv6失败", "Failed to get IPv6 from network card")
 	message.SetString(language.English, "从网卡中获得IPv6失败! 网卡名: %s", "Failed to get IPv6 from network card! Network card name: %s")
-	message.SetString(language.English, "获取IPv6结果失败! 接口: %s ,返回值: %s", "Failed to get IPv6 result! Interface: %s ,Result: %s")
 	message.SetString(language.English, "未找到第 %d 个IPv6地址! 将使用第一个IPv6地址", "%dth IPv6 address not found! Will use the first IPv6 address")
 	message.SetString(language.English, "IPv6匹配表达式 %s 不正确! 最小从1开始", "IPv6 match expression %s is incorrect! Minimum start from 1")
 	message.SetString(language.English, "IPv6将使用正则表达式 %s 进行匹配", "IPv6 will use regular expression %s for matching")
@@ -85,11 +258,25 @@ func init() {
 	message.SetString(language.English, "本机DNS异常! 将默认使用 %s, 可参考文档通过 -dns 自定义 DNS 服务器", "Local DNS exception! Will use %s by default, you can use -dns to customize DNS server")
 	message.SetString(language.English, "等待网络连接: %s", "Waiting for network connection: %s")
 	message.SetString(language.English, "%s 后重试...", "Retry after %s")
+	message.SetString(language.English, "等待网络连接超过 %s, 将继续运行", "Waited %s for the network connection, continuing anyway")
+	message.SetString(language.English, "首次更新前等待 %s", "Waiting %s before the first update")
 	message.SetString(language.English, "网络已连接", "The network is connected")
 
 	// main
 	message.SetString(language.English, "监听端口发生异常, 请检查端口是否被占用! %s", "Port listening failed, please check if the port is occupied! %s")
-	message.SetString(language.English, "Docker中运行, 请在浏览器中打开 http://docker主机IP:9876 进行配置", "Running in Docker, please open http://docker-host-ip:9876 in the browser for configuration")
+	message.SetString(language.English, "请在浏览器中打开 %s 进行配置", "Please open %s in the browser to configure")
+	message.SetString(language.English, "在容器(%s)中运行, 不打印二维码", "Running in a container (%s), QR code not printed")
+	message.SetString(language.English, "输出不是终端, 不打印二维码", "Output is not a terminal, QR code not printed")
+	message.SetString(language.English, "无法获取局域网地址, 不打印二维码: %s", "Unable to get the LAN address, QR code not printed: %s")
+	message.SetString(language.English, "生成二维码失败: %s", "Failed to generate QR code: %s")
+	message.SetString(language.English, "使用手机扫描二维码打开 %s", "Scan the QR code with your phone to open %s")
+	message.SetString(language.English, "内容过长, 无法生成二维码", "Content is too long for a QR code")
+	message.SetString(language.English, "监听地址 %s 只能在本机访问", "Listen address %s is only reachable from this machine")
+	message.SetString(language.English, "不支持的图标格式: %s", "Unsupported favicon format: %s")
+	message.SetString(language.English, "图标文件不存在: %s", "Favicon file does not exist: %s")
+	message.SetString(language.English, "图标路径不是文件: %s", "Favicon path is not a file: %s")
+	message.SetString(language.English, "自定义图标不可用, 将使用内置图标. %s", "Custom favicon is unavailable, the embedded favicon will be used. %s")
+	message.SetString(language.English, "在容器(%s)中运行, 请在浏览器中打开 http://主机IP:9876 进行配置", "Running in a container (%s), please open http://host-ip:9876 in the browser for configuration")
 	message.SetString(language.English, "ddns-go 服务卸载成功", "ddns-go service uninstalled successfully")
 	message.SetString(language.English, "ddns-go 服务卸载失败, 异常信息: %s", "ddns-go service uninstallation failed, Exception: %s")
 	message.SetString(language.English, "安装 ddns-go 服务成功! 请打开浏览器并进行配置", "Installed ddns-go service successfully! Please open the browser and configure it")
@@ -101,8 +288,12 @@ func init() {
 
 	// webhook通知
 	message.SetString(language.English, "未改变", "no changed")
+	message.SetString(language.English, "IPv4: #{ipv4Addr} #{ipv4Result}, 域名: #{ipv4Domains}\nIPv6: #{ipv6Addr} #{ipv6Result}, 域名: #{ipv6Domains}", "IPv4: #{ipv4Addr} #{ipv4Result}, domains: #{ipv4Domains}\nIPv6: #{ipv6Addr} #{ipv6Result}, domains: #{ipv6Domains}")
 	message.SetString(language.English, "失败", "failed")
 	message.SetString(language.English, "成功", "success")
+	message.SetString(language.English, "已启动", "started")
+	message.SetString(language.English, "已停止", "stopped")
+	message.SetString(language.English, "未获取到IP", "no IP detected")
 
 	// Login
 	message.SetString(language.English, "%q 配置文件为空, 超过3小时禁止从公网访问", "%q configuration file is empty, public network access is prohibited for more than 3 hours")
@@ -118,18 +309,51 @@ func init() {
 }
 
 func Log(key string, args ...interface{}) {
-	log.Println(LogStr(key, args...))
+	log.Println(ScrubSecrets(LogStr(key, args...)))
+}
+
+// debugLog 是否输出调试日志
+var debugLog bool
+
+// SetDebug 设置是否输出调试日志
+func SetDebug(debug bool) {
+	debugLog = debug
+}
+
+// IsDebug 是否输出调试日志
+func IsDebug() bool {
+	return debugLog
+}
+
+// LogDebug 开启调试后才输出日志
+func LogDebug(key string, args ...interface{}) {
+	if debugLog {
+		Log(key, args...)
+	}
 }
 
 func LogStr(key string, args ...interface{}) string {
 	return logPrinter.Sprintf(key, args...)
 }
 
-func InitLogLang(lang string) string {
-	newLang := language.English
+// LangStr 按指定的语言格式化, 用于Webhook及通知, lang 为空时使用日志的语言
+func LangStr(lang string, key string, args ...interface{}) string {
+	if lang == "" {
+		return LogStr(key, args...)
+	}
+	return message.NewPrinter(parseLang(lang)).Sprintf(key, args...)
+}
+
+// parseLang 以 zh 开头时为中文, 其它为英文
+func parseLang(lang string) language.Tag {
 	if strings.HasPrefix(lang, "zh") {
-		newLang = language.Chinese
+		return language.Chinese
 	}
+	return language.English
+}
+
+func InitLogLang(lang string) string {
+	newLang := parseLang(lang)
 	if newLang != logLang {
 		logLang = newLang
 		logPrinter = message.NewPrinter(logLang)
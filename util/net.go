@@ -1,8 +1,10 @@
 package util
 
 import (
+	"fmt"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -42,3 +44,108 @@ func GetRequestIPStr(r *http.Request) (addr string) {
 	}
 	return addr
 }
+
+// GetDefaultRouteAddr 获得默认路由使用的本地地址及网卡名称
+// network 为 udp4 或 udp6, 仅建立UDP连接, 不会发送数据
+func GetDefaultRouteAddr(network string) (ifaceName string, addr net.IP, err error) {
+	target := "8.8.8.8:53"
+	if network == "udp6" {
+		target = "[2001:4860:4860::8888]:53"
+	}
+	conn, err := net.Dial(network, target)
+	if err != nil {
+		return
+	}
+	addr = conn.LocalAddr().(*net.UDPAddr).IP
+	conn.Close()
+
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return
+	}
+	for _, iface := range interfaces {
+		addrs, _ := iface.Addrs()
+		for _, a := range addrs {
+			if ipnet, ok := a.(*net.IPNet); ok && ipnet.IP.Equal(addr) {
+				return iface.Name, addr, nil
+			}
+		}
+	}
+	return "", addr, fmt.Errorf(LogStr("未找到地址 %s 所在的网卡", addr))
+}
+
+// GetLANURL 获得局域网中访问网页的地址
+// 监听地址未指定IP时使用默认路由的本地IPv4地址, 如 :9876 => http://192.168.1.2:9876
+func GetLANURL(listen string) (string, error) {
+	addr, err := net.ResolveTCPAddr("tcp", listen)
+	if err != nil {
+		return "", err
+	}
+	ip := addr.IP
+	if ip == nil || ip.IsUnspecified() {
+		// 未找到网卡时仍可使用地址
+		if _, ip, err = GetDefaultRouteAddr("udp4"); ip == nil {
+			return "", err
+		}
+	}
+	if ip.IsLoopback() {
+		return "", fmt.Errorf(LogStr("监听地址 %s 只能在本机访问", listen))
+	}
+	return "http://" + net.JoinHostPort(ip.String(), strconv.Itoa(addr.Port)), nil
+}
+
+// ReverseAddr 获得IP的反向解析名称
+// 如 192.0.2.1 => 1.2.0.192.in-addr.arpa, 2001:db8::1 => 1.0.0...8.b.d.0.1.0.0.2.ip6.arpa
+func ReverseAddr(addr string) (string, error) {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return "", fmt.Errorf(LogStr("IP地址 %s 不正确", addr))
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", ip4[3], ip4[2], ip4[1], ip4[0]), nil
+	}
+
+	const hexDigit = "0123456789abcdef"
+	var sb strings.Builder
+	for i := len(ip) - 1; i >= 0; i-- {
+		sb.WriteByte(hexDigit[ip[i]&0xF])
+		sb.WriteByte('.')
+		sb.WriteByte(hexDigit[ip[i]>>4])
+		sb.WriteByte('.')
+	}
+	sb.WriteString("ip6.arpa")
+	return sb.String(), nil
+}
+
+// NormalizeListenAddr 校验并规范监听地址, 支持 9876、:9876、host:port 及 [::1]:9876
+func NormalizeListenAddr(listen string) (string, error) {
+	listen = strings.TrimSpace(listen)
+	// 仅填写端口
+	if _, err := strconv.Atoi(listen); err == nil {
+		listen = ":" + listen
+	}
+
+	host, port, err := net.SplitHostPort(listen)
+	if err != nil {
+		// 未使用中括号或未指定端口的IPv6地址
+		if ip := net.ParseIP(strings.Trim(listen, "[]")); ip != nil && ip.To4() == nil {
+			return "", fmt.Errorf(LogStr("监听地址 %s 不正确, IPv6地址需使用中括号并指定端口, 如 [::1]:9876", listen))
+		}
+		return "", fmt.Errorf(LogStr("监听地址 %s 不正确, 应为 :9876、127.0.0.1:9876 或 [::1]:9876", listen))
+	}
+
+	p, err := strconv.Atoi(port)
+	if err != nil || p < 0 || p > 65535 {
+		return "", fmt.Errorf(LogStr("监听地址 %s 的端口 %s 不正确", listen, port))
+	}
+
+	// 主机名需能解析
+	if host != "" && net.ParseIP(host) == nil {
+		if _, err := net.ResolveTCPAddr("tcp", listen); err != nil {
+			return "", fmt.Errorf(LogStr("监听地址 %s 不正确: %s", listen, err))
+		}
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(p)), nil
+}
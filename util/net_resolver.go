@@ -3,8 +3,10 @@ package util
 import (
 	"context"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
+	"sync/atomic"
 
 	"golang.org/x/text/language"
 )
@@ -24,9 +26,24 @@ func InitBackupDNS(customDNS, lang string) {
 
 }
 
+// currentDNS 当前使用的DNS服务器, 为空时使用系统DNS
+var currentDNS atomic.Value
+
+// GetDNS 获得当前使用的DNS服务器, 为空时使用系统DNS
+func GetDNS() string {
+	dns, _ := currentDNS.Load().(string)
+	return dns
+}
+
 // SetDNS sets the dialer.Resolver to use the given DNS server.
+// 支持 udp:// tcp:// 及加密的 tls://(DoT) https://(DoH), 仅填写IP时使用 UDP
 func SetDNS(dns string) {
+	currentDNS.Store(dns)
+	dialer.Resolver = NewResolver(dns)
+}
 
+// NewResolver 使用指定DNS服务器的 Resolver, 格式与 SetDNS 相同
+func NewResolver(dns string) *net.Resolver {
 	if !strings.Contains(dns, "://") {
 		dns = "udp://" + dns
 	}
@@ -36,6 +53,26 @@ func SetDNS(dns string) {
 	switch strings.ToLower(svrParse.Scheme) {
 	case "tcp":
 		network = "tcp"
+	case "tls":
+		// DNS over TLS, 默认端口 853
+		addr := svrParse.Host
+		if svrParse.Port() == "" {
+			addr = net.JoinHostPort(svrParse.Hostname(), "853")
+		}
+		return newDoTResolver(addr, svrParse.Hostname())
+	case "https":
+		// DNS over HTTPS, 未填写路径时使用 /dns-query
+		if svrParse.Path == "" {
+			svrParse.Path = "/dns-query"
+		}
+		// 使用系统的 Resolver 解析 DoH 服务器的域名
+		// 与其它请求使用相同的根证书, 以便通过自定义CA的代理访问
+		client := &http.Client{Transport: &http.Transport{
+			Proxy:             http.ProxyFromEnvironment,
+			ForceAttemptHTTP2: true,
+			TLSClientConfig:   clientTLSConfig(""),
+		}}
+		return newDoHResolver(svrParse.String(), client)
 	default:
 		network = "udp"
 	}
@@ -46,7 +83,7 @@ func SetDNS(dns string) {
 		dns = svrParse.Host
 	}
 
-	dialer.Resolver = &net.Resolver{
+	return &net.Resolver{
 		PreferGo: true,
 		Dial: func(ctx context.Context, _, address string) (net.Conn, error) {
 			return net.Dial(network, dns)
@@ -42,3 +42,77 @@ func TestGetRequestIPStr(t *testing.T) {
 		t.Errorf("GetRequestIPStr failed")
 	}
 }
+
+// TestReverseAddr 测试获得反向解析名称
+func TestReverseAddr(t *testing.T) {
+	data := map[string]string{
+		"192.0.2.1":   "1.2.0.192.in-addr.arpa",
+		"2001:db8::1": "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa",
+	}
+
+	for addr, expected := range data {
+		name, err := ReverseAddr(addr)
+		if err != nil || name != expected {
+			t.Errorf("%s 期望 %s, 实际 %s, 异常: %v", addr, expected, name, err)
+		}
+	}
+
+	if _, err := ReverseAddr("example.com"); err == nil {
+		t.Errorf("期望 example.com 返回异常")
+	}
+}
+
+// TestNormalizeListenAddr 测试校验并规范监听地址
+func TestNormalizeListenAddr(t *testing.T) {
+	valid := map[string]string{
+		"9876":              ":9876",
+		":9876":             ":9876",
+		" :9876 ":           ":9876",
+		"0.0.0.0:9876":      "0.0.0.0:9876",
+		"127.0.0.1:9876":    "127.0.0.1:9876",
+		"[::1]:9876":        "[::1]:9876",
+		"[::]:9876":         "[::]:9876",
+		"[fe80::1%eth0]:80": "[fe80::1%eth0]:80",
+	}
+	for listen, want := range valid {
+		got, err := NormalizeListenAddr(listen)
+		if err != nil || got != want {
+			t.Errorf("%q 期待 %q, 得到 %q, %v", listen, want, got, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"127.0.0.1",
+		"::1",
+		"::1:9876",
+		"[::1]",
+		":99999",
+		":-1",
+		":abc",
+	}
+	for _, listen := range invalid {
+		if got, err := NormalizeListenAddr(listen); err == nil {
+			t.Errorf("%q 期待异常, 得到 %q", listen, got)
+		}
+	}
+}
+
+// TestGetLANURL 测试获得局域网中访问网页的地址, 本机地址返回异常
+func TestGetLANURL(t *testing.T) {
+	valid := map[string]string{
+		"192.168.1.2:9876":   "http://192.168.1.2:9876",
+		"[2001:db8::1]:9876": "http://[2001:db8::1]:9876",
+	}
+	for listen, want := range valid {
+		if got, err := GetLANURL(listen); err != nil || got != want {
+			t.Errorf("%q 期待 %q, 得到 %q, %v", listen, want, got, err)
+		}
+	}
+
+	for _, listen := range []string{"127.0.0.1:9876", "[::1]:9876"} {
+		if got, err := GetLANURL(listen); err == nil {
+			t.Errorf("%q 期待异常, 得到 %q", listen, got)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package util
+
+import (
+	"errors"
+	"strings"
+)
+
+// QR码, 使用字节模式及L级纠错, 支持版本1到6(最多134字节), 足够编码网址
+// https://www.thonky.com/qr-code-tutorial/
+
+// qrVersion 版本的码字信息
+type qrVersion struct {
+	// 数据码字数
+	dataCodewords int
+	// 每块的纠错码字数
+	ecCodewords int
+	// 块数
+	blocks int
+	// 校正图形的位置, 版本1没有
+	alignment int
+}
+
+// qrVersions L级纠错, 下标为版本号减1
+var qrVersions = []qrVersion{
+	{19, 7, 1, 0},
+	{34, 10, 1, 18},
+	{55, 15, 1, 22},
+	{80, 20, 1, 26},
+	{108, 26, 1, 30},
+	{136, 18, 2, 34},
+}
+
+// QRCode 生成QR码, 返回的矩阵中 true 为深色模块
+func QRCode(content string) ([][]bool, error) {
+	data := []byte(content)
+	version := 0
+	for i, v := range qrVersions {
+		// 4位模式指示符及8位字符计数
+		if len(data)+2 <= v.dataCodewords {
+			version = i + 1
+			break
+		}
+	}
+	if version == 0 {
+		return nil, errors.New(LogStr("内容过长, 无法生成二维码"))
+	}
+
+	q := newQRMatrix(version)
+	q.placeData(qrCodewords(data, qrVersions[version-1]))
+
+	// 选择扣分最少的掩码
+	var best [][]bool
+	bestPenalty := -1
+	for mask := 0; mask < 8; mask++ {
+		modules := q.masked(mask)
+		if penalty := qrPenalty(modules); bestPenalty < 0 || penalty < bestPenalty {
+			best, bestPenalty = modules, penalty
+		}
+	}
+	return best, nil
+}
+
+// QRCodeString 生成可在终端显示的QR码, 每个字符显示上下两个模块
+func QRCodeString(content string) (string, error) {
+	modules, err := QRCode(content)
+	if err != nil {
+		return "", err
+	}
+
+	// 四周留白2个模块
+	const quiet = 2
+	size := len(modules)
+	light := func(y, x int) bool {
+		if y < 0 || y >= size || x < 0 || x >= size {
+			return true
+		}
+		return !modules[y][x]
+	}
+
+	var sb strings.Builder
+	for y := -quiet; y < size+quiet; y += 2 {
+		// 指定白色前景及黑色背景, 浅色模块使用前景色显示
+		sb.WriteString("\x1b[97;40m")
+		for x := -quiet; x < size+quiet; x++ {
+			top, bottom := light(y, x), light(y+1, x)
+			switch {
+			case top && bottom:
+				sb.WriteString("█")
+			case top:
+				sb.WriteString("▀")
+			case bottom:
+				sb.WriteString("▄")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString("\x1b[0m\n")
+	}
+	return sb.String(), nil
+}
+
+// qrCodewords 编码数据并计算纠错码字, 按块交错排列
+func qrCodewords(data []byte, v qrVersion) []byte {
+	var bits qrBits
+	bits.append(0b0100, 4) // 字节模式
+	bits.append(len(data), 8)
+	for _, b := range data {
+		bits.append(int(b), 8)
+	}
+	capacity := v.dataCodewords * 8
+	// 终止符最多4位, 再补齐到整字节
+	bits.append(0, min(4, capacity-len(bits)))
+	bits.append(0, (8-len(bits)%8)%8)
+
+	codewords := bits.bytes()
+	for pad := 0xEC; len(codewords) < v.dataCodewords; pad ^= 0xEC ^ 0x11 {
+		codewords = append(codewords, byte(pad))
+	}
+
+	// 分块计算纠错码字, 版本1到6每块的数据码字数相同
+	blockLen := v.dataCodewords / v.blocks
+	dataBlocks := make([][]byte, v.blocks)
+	ecBlocks := make([][]byte, v.blocks)
+	for i := range dataBlocks {
+		dataBlocks[i] = codewords[i*blockLen : (i+1)*blockLen]
+		ecBlocks[i] = qrReedSolomon(dataBlocks[i], v.ecCodewords)
+	}
+
+	result := make([]byte, 0, v.dataCodewords+v.ecCodewords*v.blocks)
+	for i := 0; i < blockLen; i++ {
+		for _, block := range dataBlocks {
+			result = append(result, block[i])
+		}
+	}
+	for i := 0; i < v.ecCodewords; i++ {
+		for _, block := range ecBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
+
+// qrBits 位序列
+type qrBits []bool
+
+// append 从高位开始追加 n 位
+func (b *qrBits) append(value int, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, value>>i&1 == 1)
+	}
+}
+
+// bytes 转换为字节, 长度需为8的倍数
+func (b qrBits) bytes() []byte {
+	result := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			result[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return result
+}
+
+// qrGFMul GF(256) 乘法, 本原多项式为 x^8+x^4+x^3+x^2+1
+func qrGFMul(x, y byte) byte {
+	var z byte
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x1D
+		z ^= (y >> i & 1) * x
+	}
+	return z
+}
+
+// qrReedSolomon 计算 Reed-Solomon 纠错码字
+func qrReedSolomon(data []byte, n int) []byte {
+	// 生成多项式 (x-α^0)(x-α^1)...(x-α^(n-1)), 省略最高次项的系数1
+	generator := make([]byte, n)
+	generator[n-1] = 1
+	root := byte(1)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			generator[j] = qrGFMul(generator[j], root)
+			if j+1 < n {
+				generator[j] ^= generator[j+1]
+			}
+		}
+		root = qrGFMul(root, 0x02)
+	}
+
+	result := make([]byte, n)
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[n-1] = 0
+		for i := range result {
+			result[i] ^= qrGFMul(generator[i], factor)
+		}
+	}
+	return result
+}
+
+// qrMatrix 未加掩码的模块矩阵
+type qrMatrix struct {
+	size     int
+	modules  [][]bool
+	function [][]bool // 功能图形, 不放置数据也不加掩码
+}
+
+// newQRMatrix 绘制定位、定时及校正图形, 并预留格式信息的位置
+func newQRMatrix(version int) *qrMatrix {
+	size := version*4 + 17
+	q := &qrMatrix{size: size, modules: make([][]bool, size), function: make([][]bool, size)}
+	for i := range q.modules {
+		q.modules[i] = make([]bool, size)
+		q.function[i] = make([]bool, size)
+	}
+
+	// 定时图形
+	for i := 0; i < size; i++ {
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+
+	// 定位图形及分隔符
+	for _, c := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x >= 0 && x < size && y >= 0 && y < size {
+					dist := max(qrAbs(dx), qrAbs(dy))
+					q.set(x, y, dist != 2 && dist != 4)
+				}
+			}
+		}
+	}
+
+	// 校正图形, 版本2到6只有右下角一个
+	if a := qrVersions[version-1].alignment; a > 0 {
+		for dy := -2; dy <= 2; dy++ {
+			for dx := -2; dx <= 2; dx++ {
+				q.set(a+dx, a+dy, max(qrAbs(dx), qrAbs(dy)) != 1)
+			}
+		}
+	}
+
+	// 预留格式信息, 加掩码时写入
+	q.drawFormat(0)
+	return q
+}
+
+// set 设置功能图形的模块
+func (q *qrMatrix) set(x, y int, dark bool) {
+	q.modules[y][x] = dark
+	q.function[y][x] = true
+}
+
+// drawFormat 绘制格式信息及固定的深色模块
+func (q *qrMatrix) drawFormat(mask int) {
+	// L级纠错的标识为01
+	data := 0b01<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	size := q.size
+	// 左上角
+	for i := 0; i <= 5; i++ {
+		q.set(8, i, bit(i))
+	}
+	q.set(8, 7, bit(6))
+	q.set(8, 8, bit(7))
+	q.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.set(14-i, 8, bit(i))
+	}
+	// 右上角及左下角
+	for i := 0; i < 8; i++ {
+		q.set(size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.set(8, size-15+i, bit(i))
+	}
+	q.set(8, size-8, true)
+}
+
+// placeData 按之字形从右下角开始放置数据, 剩余位为浅色
+func (q *qrMatrix) placeData(codewords []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		// 跳过垂直的定时图形
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < q.size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					// 向上
+					y = q.size - 1 - vert
+				}
+				if !q.function[y][x] && i < len(codewords)*8 {
+					q.modules[y][x] = codewords[i/8]>>(7-i%8)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// masked 返回加掩码并写入格式信息后的矩阵
+func (q *qrMatrix) masked(mask int) [][]bool {
+	q.drawFormat(mask)
+	result := make([][]bool, q.size)
+	for y := range result {
+		result[y] = make([]bool, q.size)
+		for x := range result[y] {
+			result[y][x] = q.modules[y][x] != (!q.function[y][x] && qrMask(mask, x, y))
+		}
+	}
+	return result
+}
+
+// qrMask 掩码图形
+func qrMask(mask int, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// qrPenalty 计算掩码的扣分, 扣分越少越容易识别
+func qrPenalty(modules [][]bool) int {
+	size := len(modules)
+	at := func(y, x int, vertical bool) bool {
+		if vertical {
+			return modules[x][y]
+		}
+		return modules[y][x]
+	}
+	finderLike := [][]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+
+	penalty := 0
+	dark := 0
+	for _, vertical := range []bool{false, true} {
+		for y := 0; y < size; y++ {
+			// 同色连续5个以上的模块
+			run := 1
+			for x := 1; x < size; x++ {
+				if at(y, x, vertical) == at(y, x-1, vertical) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					penalty += run - 2
+				}
+				run = 1
+			}
+			if run >= 5 {
+				penalty += run - 2
+			}
+
+			// 类似定位图形的 1:1:3:1:1
+			for x := 0; x+11 <= size; x++ {
+				for _, pattern := range finderLike {
+					match := true
+					for k, p := range pattern {
+						if at(y, x+k, vertical) != p {
+							match = false
+							break
+						}
+					}
+					if match {
+						penalty += 40
+					}
+				}
+			}
+		}
+	}
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			if modules[y][x] {
+				dark++
+			}
+			// 同色的2x2块
+			if x+1 < size && y+1 < size {
+				c := modules[y][x]
+				if modules[y][x+1] == c && modules[y+1][x] == c && modules[y+1][x+1] == c {
+					penalty += 3
+				}
+			}
+		}
+	}
+
+	// 深色模块比例偏离50%
+	total := size * size
+	penalty += qrAbs(dark*20-total*10) / total * 10
+	return penalty
+}
+
+// qrAbs 绝对值
+func qrAbs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
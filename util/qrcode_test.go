@@ -0,0 +1,134 @@
+package util
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestQRReedSolomon 测试纠错码字, 数据为版本1-M的 HELLO WORLD
+func TestQRReedSolomon(t *testing.T) {
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	expected := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := qrReedSolomon(data, 10); !bytes.Equal(got, expected) {
+		t.Errorf("期待 %v, 得到 %v", expected, got)
+	}
+}
+
+// TestQRCode 测试生成的QR码可按格式信息及掩码读回原内容
+func TestQRCode(t *testing.T) {
+	tests := []struct {
+		content string
+		version int
+	}{
+		{"http://192.168.1.2:9876", 2},
+		{"http://[2001:db8::1234:5678:9abc:def0]:9876/" + strings.Repeat("a", 60), 5},
+		{"http://example.com/" + strings.Repeat("a", 110), 6},
+	}
+	for _, tt := range tests {
+		modules, err := QRCode(tt.content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := len(modules)
+		if size != tt.version*4+17 {
+			t.Fatalf("%s 期待版本 %d, 得到大小 %d", tt.content, tt.version, size)
+		}
+
+		// 两处格式信息一致, 且为L级纠错
+		var format1, format2 int
+		for i := 0; i < 15; i++ {
+			var x1, y1, x2, y2 int
+			switch {
+			case i < 6:
+				x1, y1 = 8, i
+			case i < 8:
+				x1, y1 = 8, i+1
+			case i == 8:
+				x1, y1 = 7, 8
+			default:
+				x1, y1 = 14-i, 8
+			}
+			if i < 8 {
+				x2, y2 = size-1-i, 8
+			} else {
+				x2, y2 = 8, size-15+i
+			}
+			if modules[y1][x1] {
+				format1 |= 1 << i
+			}
+			if modules[y2][x2] {
+				format2 |= 1 << i
+			}
+		}
+		format := format1 ^ 0x5412
+		if format1 != format2 || format>>13 != 0b01 {
+			t.Fatalf("%s 格式信息不正确: %015b %015b", tt.content, format1, format2)
+		}
+		mask := format >> 10 & 7
+
+		// 按之字形读回数据并去除掩码
+		q := newQRMatrix(tt.version)
+		var bits qrBits
+		for right := size - 1; right >= 1; right -= 2 {
+			if right == 6 {
+				right = 5
+			}
+			for vert := 0; vert < size; vert++ {
+				for j := 0; j < 2; j++ {
+					x, y := right-j, vert
+					if (right+1)&2 == 0 {
+						y = size - 1 - vert
+					}
+					if !q.function[y][x] {
+						bits = append(bits, modules[y][x] != qrMask(mask, x, y))
+					}
+				}
+			}
+		}
+		bits = bits[:len(bits)/8*8]
+		codewords := bits.bytes()
+
+		// 还原交错的数据码字
+		v := qrVersions[tt.version-1]
+		blockLen := v.dataCodewords / v.blocks
+		data := make([]byte, v.dataCodewords)
+		for i := 0; i < v.dataCodewords; i++ {
+			data[i%v.blocks*blockLen+i/v.blocks] = codewords[i]
+		}
+		if data[0]>>4 != 0b0100 || int(data[0]&0x0F<<4|data[1]>>4) != len(tt.content) {
+			t.Fatalf("%s 模式或长度不正确: %v", tt.content, data[:2])
+		}
+		var got []byte
+		for i := 0; i < len(tt.content); i++ {
+			got = append(got, data[i+1]<<4|data[i+2]>>4)
+		}
+		if string(got) != tt.content {
+			t.Errorf("期待 %s, 得到 %s", tt.content, got)
+		}
+	}
+
+	if _, err := QRCode(strings.Repeat("a", 135)); err == nil {
+		t.Error("内容过长时期待返回异常")
+	}
+}
+
+// TestQRFormat 测试格式信息, L级纠错掩码0为 111011111000100
+func TestQRFormat(t *testing.T) {
+	q := newQRMatrix(1)
+	q.drawFormat(0)
+	var got int
+	for i := 0; i < 8; i++ {
+		if q.modules[8][q.size-1-i] {
+			got |= 1 << i
+		}
+	}
+	for i := 8; i < 15; i++ {
+		if q.modules[q.size-15+i][8] {
+			got |= 1 << i
+		}
+	}
+	if got != 0b111011111000100 {
+		t.Errorf("期待 111011111000100, 得到 %015b", got)
+	}
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// secretMask 替换密钥的字符串
+const secretMask = "******"
+
+// secretParamReg 匹配URL或表单中的敏感参数, 如 password=xxx
+var secretParamReg = regexp.MustCompile(`(?i)((?:^|[?&])(?:password|passwd|pass|token|secret|key|apikey|api_key|secretapikey|access_token|signature)=)[^&\s"']+`)
+
+// secretJSONReg 匹配JSON中的敏感字段, 如 "apikey": "xxx"
+var secretJSONReg = regexp.MustCompile(`(?i)("(?:password|passwd|token|secret|key|apikey|api_key|secretapikey|access_token)"\s*:\s*")[^"]*`)
+
+// bearerReg 匹配 Bearer Token
+var bearerReg = regexp.MustCompile(`(?i)(Bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+
+// logSecrets 需要从日志中隐藏的密钥
+var logSecrets struct {
+	sync.RWMutex
+	values []string
+}
+
+// SetLogSecrets 设置需要从日志中隐藏的密钥, 过短的忽略以免误伤
+func SetLogSecrets(values ...string) {
+	secrets := make([]string, 0, len(values))
+	for _, v := range values {
+		if len(v) >= 6 {
+			secrets = append(secrets, v)
+		}
+	}
+
+	logSecrets.Lock()
+	defer logSecrets.Unlock()
+	logSecrets.values = secrets
+}
+
+// ScrubSecrets 隐藏字符串中的密钥
+func ScrubSecrets(s string) string {
+	logSecrets.RLock()
+	for _, v := range logSecrets.values {
+		s = strings.ReplaceAll(s, v, secretMask)
+	}
+	logSecrets.RUnlock()
+
+	s = secretParamReg.ReplaceAllString(s, "${1}"+secretMask)
+	s = secretJSONReg.ReplaceAllString(s, "${1}"+secretMask)
+	return bearerReg.ReplaceAllString(s, "${1}"+secretMask)
+}
@@ -0,0 +1,24 @@
+package util
+
+import "testing"
+
+// TestScrubSecrets 测试隐藏日志中的密钥
+func TestScrubSecrets(t *testing.T) {
+	SetLogSecrets("my-secret-value", "abc")
+	defer SetLogSecrets()
+
+	data := map[string]string{
+		"Exception: my-secret-value":                              "Exception: ******",
+		"Get \"https://example.com/update?host=a&password=p4ss\"": "Get \"https://example.com/update?host=a&password=******\"",
+		"Authorization: Bearer eyJhbGciOi.x-y_z":                  "Authorization: Bearer ******",
+		"abc is too short to be hidden":                           "abc is too short to be hidden",
+		`{"apikey": "pk1_xxx", "secretapikey":"sk1_xxx"}`:         `{"apikey": "******", "secretapikey":"******"}`,
+		"key=k3y&domain=example.com":                              "key=******&domain=example.com",
+	}
+
+	for input, expected := range data {
+		if got := ScrubSecrets(input); got != expected {
+			t.Errorf("期待 %s, 得到 %s", expected, got)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// ShellCommand 使用系统的 shell 执行命令, Windows 使用 powershell, 其它系统优先使用 bash
+func ShellCommand(ctx context.Context, cmd string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "powershell", "-Command", cmd)
+	}
+	// If Bash does not exist, use sh
+	if _, err := exec.LookPath("bash"); err != nil {
+		return exec.CommandContext(ctx, "sh", "-c", cmd)
+	}
+	return exec.CommandContext(ctx, "bash", "-c", cmd)
+}
+
+// RunShell 执行命令并返回标准输出及标准错误, env 追加到当前的环境变量, 超时后结束命令
+func RunShell(cmd string, env []string, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	execCmd := ShellCommand(ctx, cmd)
+	execCmd.Env = append(os.Environ(), env...)
+	// 超时后子进程可能仍持有输出, 不再等待
+	execCmd.WaitDelay = time.Second
+	out, err := execCmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return out, ctx.Err()
+	}
+	return out, err
+}
@@ -0,0 +1,27 @@
+package util
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRunShell 测试执行命令时传递环境变量及超时
+func TestRunShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("命令使用 sh 语法")
+	}
+
+	out, err := RunShell("echo $DDNS_GO_TEST_VALUE", []string{"DDNS_GO_TEST_VALUE=1.2.3.4"}, time.Second)
+	if err != nil || strings.TrimSpace(string(out)) != "1.2.3.4" {
+		t.Errorf("期待 1.2.3.4, 得到 %q %v", out, err)
+	}
+
+	start := time.Now()
+	_, err = RunShell("sleep 5", nil, 100*time.Millisecond)
+	if err != context.DeadlineExceeded || time.Since(start) > 3*time.Second {
+		t.Errorf("期待超时, 得到 %v, 用时 %s", err, time.Since(start))
+	}
+}
@@ -6,6 +6,9 @@ import (
 
 const ConfigFilePathENV = "DDNS_CONFIG_FILE_PATH"
 
+// VersionENV ddns-go 版本
+const VersionENV = "DDNS_GO_VERSION"
+
 // GetConfigFilePath 获得配置文件路径
 func GetConfigFilePath() string {
 	configFilePath := os.Getenv(ConfigFilePathENV)
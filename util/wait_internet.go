@@ -5,16 +5,24 @@ import (
 	"time"
 )
 
-// Wait blocks until the Internet is connected.
+// waitInternetDelay 等待网络连接时的重试间隔
+var waitInternetDelay = time.Second * 5
+
+// Wait blocks until the Internet is connected or the timeout expires.
+// A timeout of 0 waits indefinitely. It returns false if the timeout expired.
 //
 // See also:
 //
 //   - https://stackoverflow.com/a/50058255
 //   - https://github.com/ddev/ddev/blob/v1.22.7/pkg/globalconfig/global_config.go#L776
-func WaitInternet(addresses []string) {
-	delay := time.Second * 5
+func WaitInternet(addresses []string, timeout time.Duration) bool {
+	delay := waitInternetDelay
 	retryTimes := 0
 	failed := false
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
 
 	for {
 		for _, addr := range addresses {
@@ -25,7 +33,13 @@ func WaitInternet(addresses []string) {
 				if failed {
 					Log("网络已连接")
 				}
-				return
+				return true
+			}
+
+			// Timeout expired, proceed anyway.
+			if !deadline.IsZero() && !time.Now().Before(deadline) {
+				Log("等待网络连接超过 %s, 将继续运行", timeout)
+				return false
 			}
 
 			failed = true
@@ -39,7 +53,11 @@ func WaitInternet(addresses []string) {
 				retryTimes = retryTimes + 1
 			}
 
-			time.Sleep(delay)
+			wait := delay
+			if !deadline.IsZero() {
+				wait = min(wait, time.Until(deadline))
+			}
+			time.Sleep(wait)
 		}
 	}
 }
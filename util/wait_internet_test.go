@@ -0,0 +1,21 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+// TestWaitInternetTimeout 测试超时后不再等待网络连接
+func TestWaitInternetTimeout(t *testing.T) {
+	oldDelay := waitInternetDelay
+	waitInternetDelay = 10 * time.Millisecond
+	defer func() { waitInternetDelay = oldDelay }()
+
+	start := time.Now()
+	if WaitInternet([]string{"https://ddns-go.invalid"}, 50*time.Millisecond) {
+		t.Error("期待等待网络连接超时")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("等待网络连接超时后应立即返回, 用时 %s", elapsed)
+	}
+}
@@ -0,0 +1,97 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/dns"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// APIUpdate 立即更新, 可在请求中指定IP及只更新带有标签 tag 的域名
+// 支持 JSON {"ipv4": "", "ipv6": "", "tag": ""} 或表单 ipv4、ipv6、myip、tag
+func APIUpdate(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var data struct {
+		IPv4 string `json:"ipv4"`
+		IPv6 string `json:"ipv6"`
+		Tag  string `json:"tag,omitempty"`
+	}
+	if strings.Contains(request.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(request.Body).Decode(&data); err != nil {
+			returnError(writer, util.LogStr("数据解析失败, 请刷新页面重试"))
+			return
+		}
+	} else {
+		data.IPv4 = request.FormValue("ipv4")
+		data.IPv6 = request.FormValue("ipv6")
+		data.Tag = request.FormValue("tag")
+		// 兼容 DynDNS2 的 myip
+		if myip := request.FormValue("myip"); myip != "" {
+			if strings.Contains(myip, ":") {
+				data.IPv6 = myip
+			} else {
+				data.IPv4 = myip
+			}
+		}
+	}
+	data.IPv4 = strings.TrimSpace(data.IPv4)
+	data.IPv6 = strings.TrimSpace(data.IPv6)
+	data.Tag = strings.TrimSpace(data.Tag)
+
+	if data.IPv4 != "" {
+		if err := config.CheckPushedAddr(data.IPv4, "IPv4"); err != nil {
+			returnError(writer, err.Error())
+			return
+		}
+	}
+	if data.IPv6 != "" {
+		if err := config.CheckPushedAddr(data.IPv6, "IPv6"); err != nil {
+			returnError(writer, err.Error())
+			return
+		}
+	}
+	config.SetPushedAddr(data.IPv4, data.IPv6)
+	if data.Tag != "" {
+		util.Log("%q 触发更新标签 %s 的域名, IPv4: %s, IPv6: %s", util.GetRequestIPStr(request), data.Tag, data.IPv4, data.IPv6)
+		go dns.RunTag(data.Tag)
+		returnOK(writer, "ok", data)
+		return
+	}
+	util.Log("%q 触发更新, IPv4: %s, IPv6: %s", util.GetRequestIPStr(request), data.IPv4, data.IPv6)
+
+	go dns.RunOnce()
+	returnOK(writer, "ok", data)
+}
+
+// APIUpdateIndex 只更新第 index 个配置(从1开始), 不使用缓存, 返回每个域名的更新结果
+func APIUpdateIndex(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	conf, _ := config.GetConfigCached()
+	index, err := strconv.Atoi(request.PathValue("index"))
+	if err != nil {
+		returnError(writer, util.LogStr("第 %s 个配置不存在", request.PathValue("index")))
+		return
+	}
+	util.Log("%q 触发更新第 %s 个配置", util.GetRequestIPStr(request), util.Ordinal(index, conf.Lang))
+	result, domains, ok := dns.RunIndex(index - 1)
+	if !ok {
+		returnError(writer, util.LogStr("第 %s 个配置不存在", util.Ordinal(index, conf.Lang)))
+		return
+	}
+	returnOK(writer, "ok", struct {
+		Index   int                `json:"index"`
+		Result  string             `json:"result"`
+		Domains []dns.DomainResult `json:"domains"`
+	}{index, result, domains})
+}
@@ -2,6 +2,7 @@ package web
 
 import (
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/jeessy2/ddns-go/v6/config"
@@ -11,15 +12,150 @@ import (
 // ViewFunc func
 type ViewFunc func(http.ResponseWriter, *http.Request)
 
-// Auth 验证Token是否已经通过
+// role 登录用户的角色
+type role int
+
+const (
+	roleNone role = iota
+	// roleReadOnly 只读用户, 只能查看配置、日志及状态
+	roleReadOnly
+	// roleAdmin 管理员
+	roleAdmin
+)
+
+// basicDetect Basic认证失败检测, 与登录页面分开计数
+type basicDetect struct {
+	mu          sync.Mutex
+	failedTimes uint32    // 失败次数
+	lockedUntil time.Time // 锁定截止时间
+}
+
+var bd = &basicDetect{}
+
+// basicLockUnit 每次失败锁定的时长单位
+var basicLockUnit = time.Minute
+
+// locked 失败次数过多且未到解锁时间
+func (d *basicDetect) locked() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.failedTimes >= 5 && time.Now().Before(d.lockedUntil)
+}
+
+// fail 记录一次失败, 达到5次后按失败次数锁定, 最多一天
+func (d *basicDetect) fail() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failedTimes++
+	if d.failedTimes >= 5 {
+		x := min(d.failedTimes, 1440)
+		d.lockedUntil = time.Now().Add(time.Duration(x) * basicLockUnit)
+	}
+}
+
+// success 认证成功后清空失败次数
+func (d *basicDetect) success() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.failedTimes = 0
+	d.lockedUntil = time.Time{}
+}
+
+// validCookie 浏览器中的cookie是否与系统中的cookie一致且未过期
+func validCookie(inSystem *http.Cookie, inWeb *http.Cookie) bool {
+	return inSystem.Value != "" &&
+		inSystem.Value == inWeb.Value &&
+		inSystem.Expires.After(time.Now())
+}
+
+// getCookieRole 根据cookie获得登录用户的角色
+func getCookieRole(r *http.Request) role {
+	cookieInWeb, err := r.Cookie(cookieName)
+	if err != nil {
+		return roleNone
+	}
+	if validCookie(cookieInSystem, cookieInWeb) {
+		return roleAdmin
+	}
+	if validCookie(readOnlyCookieInSystem, cookieInWeb) {
+		return roleReadOnly
+	}
+	return roleNone
+}
+
+// getUserRole 根据用户名密码获得角色
+func getUserRole(user *config.User, username string, password string) role {
+	if user.Username != "" && username == user.Username && util.PasswordOK(user.Password, password) {
+		return roleAdmin
+	}
+	if user.ReadOnlyUsername != "" && username == user.ReadOnlyUsername && util.PasswordOK(user.ReadOnlyPassword, password) {
+		return roleReadOnly
+	}
+	return roleNone
+}
+
+// forbidReadOnly 只读用户禁止访问
+func forbidReadOnly(w http.ResponseWriter, r *http.Request) {
+	util.Log("%q 为只读用户, 禁止访问 %s", util.GetRequestIPStr(r), r.URL.Path)
+	w.WriteHeader(http.StatusForbidden)
+}
+
+// Auth 验证Token是否已经通过, 仅管理员可访问
 func Auth(f ViewFunc) ViewFunc {
+	return auth(f, roleAdmin)
+}
+
+// AuthReadOnly 验证Token是否已经通过, 只读用户也可访问
+func AuthReadOnly(f ViewFunc) ViewFunc {
+	return auth(f, roleReadOnly)
+}
+
+func auth(f ViewFunc, minRole role) ViewFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		cookieInWeb, err := r.Cookie(cookieName)
-		if err != nil {
+		conf, _ := config.GetConfigCached()
+
+		// 禁止公网访问
+		if conf.NotAllowWanAccess {
+			if !util.IsPrivateNetwork(r.RemoteAddr) {
+				w.WriteHeader(http.StatusForbidden)
+				util.Log("%q 被禁止从公网访问", util.GetRequestIPStr(r))
+				return
+			}
+		}
+
+		if !basicAuthGate(w, r, &conf.User, false) {
+			return
+		}
+
+		if _, err := r.Cookie(cookieName); err != nil {
 			http.Redirect(w, r, "./login", http.StatusTemporaryRedirect)
 			return
 		}
 
+		// 验证token
+		switch userRole := getCookieRole(r); {
+		case userRole >= minRole:
+			f(w, r) // 执行被装饰的函数
+		case userRole == roleReadOnly:
+			forbidReadOnly(w, r)
+		default:
+			http.Redirect(w, r, "./login", http.StatusTemporaryRedirect)
+		}
+	}
+}
+
+// AuthAPI 验证登录后的Cookie或Basic认证, 便于路由器等调用接口, 仅管理员可访问
+func AuthAPI(f ViewFunc) ViewFunc {
+	return authAPI(f, roleAdmin)
+}
+
+// AuthAPIReadOnly 验证登录后的Cookie或Basic认证, 只读用户也可访问
+func AuthAPIReadOnly(f ViewFunc) ViewFunc {
+	return authAPI(f, roleReadOnly)
+}
+
+func authAPI(f ViewFunc, minRole role) ViewFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		conf, _ := config.GetConfigCached()
 
 		// 禁止公网访问
@@ -31,18 +167,42 @@ func Auth(f ViewFunc) ViewFunc {
 			}
 		}
 
-		// 验证token
-		if cookieInSystem.Value != "" &&
-			cookieInSystem.Value == cookieInWeb.Value &&
-			cookieInSystem.Expires.After(time.Now()) {
-			f(w, r) // 执行被装饰的函数
+		if !basicAuthGate(w, r, &conf.User, true) {
+			return
+		}
+
+		userRole := getAPIRole(r, &conf.User)
+		if userRole >= minRole {
+			f(w, r)
+			return
+		}
+		if userRole == roleReadOnly {
+			forbidReadOnly(w, r)
 			return
 		}
 
-		http.Redirect(w, r, "./login", http.StatusTemporaryRedirect)
+		w.Header().Set("WWW-Authenticate", `Basic realm="ddns-go"`)
+		w.WriteHeader(http.StatusUnauthorized)
 	}
 }
 
+// getAPIRole 根据登录后的Cookie或Basic认证获得角色
+func getAPIRole(r *http.Request, user *config.User) role {
+	// 验证token
+	userRole := getCookieRole(r)
+
+	// 验证Basic认证, 失败次数过多时拒绝
+	if username, password, ok := r.BasicAuth(); userRole == roleNone && ok && !bd.locked() {
+		if userRole = getUserRole(user, username, password); userRole == roleNone {
+			bd.fail()
+			util.Log("%q 帐号密码不正确", util.GetRequestIPStr(r))
+		} else {
+			bd.success()
+		}
+	}
+	return userRole
+}
+
 // AuthAssert 保护静态等文件不被公网访问
 func AuthAssert(f ViewFunc) ViewFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -66,7 +226,32 @@ func AuthAssert(f ViewFunc) ViewFunc {
 			}
 		}
 
+		if !basicAuthGate(w, r, &conf.User, false) {
+			return
+		}
+
 		f(w, r) // 执行被装饰的函数
 
 	}
 }
+
+// basicAuthGate 开启Basic认证时, 验证通过才可访问, 未通过时返回 false 并要求浏览器输入帐号密码
+// allowUser 为 true 时也接受管理员及只读用户的帐号密码, 以免影响路由器等通过Basic认证调用接口
+func basicAuthGate(w http.ResponseWriter, r *http.Request, user *config.User, allowUser bool) bool {
+	if !user.BasicAuth {
+		return true
+	}
+	// 失败次数过多时拒绝, 到时间后自动解锁
+	if username, password, ok := r.BasicAuth(); ok && !bd.locked() {
+		if (username == user.BasicAuthUsername && util.PasswordOK(user.BasicAuthPassword, password)) ||
+			(allowUser && getUserRole(user, username, password) != roleNone) {
+			bd.success()
+			return true
+		}
+		bd.fail()
+		util.Log("%q Basic认证的帐号密码不正确", util.GetRequestIPStr(r))
+	}
+	w.Header().Set("WWW-Authenticate", `Basic realm="ddns-go"`)
+	w.WriteHeader(http.StatusUnauthorized)
+	return false
+}
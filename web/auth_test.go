@@ -0,0 +1,89 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testHash 测试用的低成本哈希
+func testHash(password string) string {
+	hashed, _ := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	return string(hashed)
+}
+
+// TestBasicAuthGateUnlock 测试Basic认证失败5次后锁定, 到时间后正确的帐号密码可以通过
+func TestBasicAuthGateUnlock(t *testing.T) {
+	hashed := testHash("secret")
+	user := &config.User{BasicAuth: true, BasicAuthUsername: "admin", BasicAuthPassword: hashed}
+
+	oldUnit := basicLockUnit
+	basicLockUnit = 20 * time.Millisecond
+	defer func() {
+		basicLockUnit = oldUnit
+		bd.success()
+	}()
+	bd.success()
+
+	gate := func(password string) bool {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.SetBasicAuth("admin", password)
+		return basicAuthGate(httptest.NewRecorder(), r, user, false)
+	}
+
+	for i := 0; i < 5; i++ {
+		if gate("wrong") {
+			t.Fatalf("第 %d 次错误的密码不应通过", i+1)
+		}
+	}
+	if gate("secret") {
+		t.Error("锁定期间不应通过")
+	}
+
+	time.Sleep(5 * 20 * time.Millisecond)
+	if !gate("secret") {
+		t.Error("解锁后正确的密码应通过")
+	}
+	if bd.failedTimes != 0 {
+		t.Errorf("通过后失败次数应清零, 实际为 %d", bd.failedTimes)
+	}
+}
+
+// TestAPIRoleUnlock 测试接口的Basic认证失败过多后锁定并自动解锁
+func TestAPIRoleUnlock(t *testing.T) {
+	hashed := testHash("secret")
+	user := &config.User{Username: "admin", Password: hashed}
+
+	oldUnit := basicLockUnit
+	basicLockUnit = 20 * time.Millisecond
+	defer func() {
+		basicLockUnit = oldUnit
+		bd.success()
+	}()
+	bd.success()
+
+	apiRole := func(password string) role {
+		r := httptest.NewRequest(http.MethodPost, "/api/update", nil)
+		r.SetBasicAuth("admin", password)
+		return getAPIRole(r, user)
+	}
+
+	for i := 0; i < 5; i++ {
+		apiRole("wrong")
+	}
+	if !bd.locked() {
+		t.Fatal("失败5次后应锁定")
+	}
+	if apiRole("secret") != roleNone {
+		t.Error("锁定期间不应通过")
+	}
+
+	time.Sleep(5 * 20 * time.Millisecond)
+	if apiRole("secret") != roleAdmin {
+		t.Error("解锁后正确的密码应通过")
+	}
+}
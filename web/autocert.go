@@ -0,0 +1,138 @@
+package web
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/util"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// autocertPeekTimeout 等待连接发送首字节的超时时间
+const autocertPeekTimeout = 10 * time.Second
+
+// Autocert 从 Let's Encrypt 自动申请及续期证书, 使用监听端口完成 HTTP-01 验证
+type Autocert struct {
+	manager  *autocert.Manager
+	config   *tls.Config
+	fallback *tls.Certificate
+}
+
+// NewAutocert 为 hosts 申请证书并缓存在 dir, 申请失败或未匹配域名时使用 fallback, fallback 可为 nil
+func NewAutocert(hosts []string, dir string, fallback *tls.Certificate) *Autocert {
+	a := &Autocert{
+		manager: &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			Cache:      autocert.DirCache(dir),
+			HostPolicy: autocert.HostWhitelist(hosts...),
+		},
+		fallback: fallback,
+	}
+	a.config = a.manager.TLSConfig()
+	a.config.GetCertificate = a.getCertificate
+	return a
+}
+
+// getCertificate 优先使用自动申请的证书
+func (a *Autocert) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, err := a.manager.GetCertificate(hello)
+	if err == nil || a.fallback == nil {
+		return cert, err
+	}
+	if hello.ServerName != "" {
+		util.Log("自动申请 %s 的证书失败, 将使用指定的证书: %s", hello.ServerName, err)
+	}
+	return a.fallback, nil
+}
+
+// TLSConfig 自动申请证书的TLS配置, 供 HTTP/3 使用
+func (a *Autocert) TLSConfig() *tls.Config {
+	return a.config.Clone()
+}
+
+// Handler HTTPS 请求交由 next 处理, HTTP 请求只处理 HTTP-01 验证, 其余重定向到 HTTPS
+func (a *Autocert) Handler(next http.Handler) http.Handler {
+	redirect := a.manager.HTTPHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://"+r.Host+r.URL.RequestURI(), http.StatusFound)
+	}))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		redirect.ServeHTTP(w, r)
+	})
+}
+
+// Listener 同一端口同时接受 HTTPS 及 HTTP 连接, 按首字节是否为TLS握手区分
+func (a *Autocert) Listener(l net.Listener) net.Listener {
+	al := &autocertListener{Listener: l, config: a.config, conns: make(chan net.Conn), done: make(chan struct{})}
+	go al.run()
+	return al
+}
+
+type autocertListener struct {
+	net.Listener
+	config *tls.Config
+	conns  chan net.Conn
+	done   chan struct{}
+	once   sync.Once
+	err    error
+}
+
+// run 接受连接, 读取首字节后交由 Accept 返回, 以免慢速连接阻塞其它连接
+func (l *autocertListener) run() {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			l.once.Do(func() {
+				l.err = err
+				close(l.done)
+			})
+			return
+		}
+		go func() {
+			conn.SetReadDeadline(time.Now().Add(autocertPeekTimeout))
+			r := bufio.NewReader(conn)
+			first, err := r.Peek(1)
+			conn.SetReadDeadline(time.Time{})
+			if err != nil {
+				conn.Close()
+				return
+			}
+			var c net.Conn = &peekedConn{Conn: conn, r: r}
+			// 0x16 为TLS握手记录
+			if first[0] == 0x16 {
+				c = tls.Server(c, l.config)
+			}
+			select {
+			case l.conns <- c:
+			case <-l.done:
+				c.Close()
+			}
+		}()
+	}
+}
+
+func (l *autocertListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, l.err
+	}
+}
+
+// peekedConn 先读取已预读的数据
+type peekedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *peekedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
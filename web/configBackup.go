@@ -0,0 +1,84 @@
+package web
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/dns"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// maxImportSize 导入配置文件的最大字节数
+const maxImportSize = 1 << 20
+
+// ConfigExport 下载配置文件, redact=false 时包含密钥, 只读用户只能下载隐藏了密钥的配置
+func ConfigExport(writer http.ResponseWriter, request *http.Request) {
+	conf, err := config.GetConfigCached()
+	if err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+
+	redact := request.URL.Query().Get("redact") != "false"
+	if !redact && getAPIRole(request, &conf.User) != roleAdmin {
+		forbidReadOnly(writer, request)
+		return
+	}
+
+	byt, err := conf.ExportYAML(redact)
+	if err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+	if !redact {
+		util.Log("%q 导出了包含密钥的配置文件", util.GetRequestIPStr(request))
+	}
+
+	writer.Header().Set("Content-Type", "application/yaml; charset=utf-8")
+	writer.Header().Set("Content-Disposition", `attachment; filename="ddns-go-config-`+time.Now().Format("20060102150405")+`.yaml"`)
+	writer.Write(byt)
+}
+
+// ConfigImport 导入配置文件, 校验通过后覆盖当前配置并立即更新一次
+// 支持请求体为配置文件, 或表单中 file 字段上传的文件
+func ConfigImport(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	request.Body = http.MaxBytesReader(writer, request.Body, maxImportSize)
+	var body io.Reader = request.Body
+	if strings.HasPrefix(request.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := request.FormFile("file")
+		if err != nil {
+			returnError(writer, util.LogStr("数据解析失败, 请刷新页面重试"))
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+	byt, err := io.ReadAll(body)
+	if err != nil {
+		returnError(writer, util.LogStr("数据解析失败, 请刷新页面重试"))
+		return
+	}
+
+	conf, err := config.ImportYAML(byt)
+	if err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+	if err = conf.SaveConfig(); err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+
+	util.Log("%q 导入了配置文件", util.GetRequestIPStr(request))
+	util.ForceCompareGlobal = true
+	go dns.RunOnce()
+	returnOK(writer, "ok", map[string]int{"dnsConf": len(conf.DnsConf)})
+}
@@ -0,0 +1,58 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// APICredentials 只修改DNS服务商的凭证, 不重置IP缓存, 下次更新时使用新凭证
+// 支持 JSON {"index": 1, "id": "", "secret": ""} 或表单 index、id、secret, index 从1开始
+func APICredentials(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var data struct {
+		Index  int    `json:"index"`
+		ID     string `json:"id"`
+		Secret string `json:"secret"`
+	}
+	if strings.Contains(request.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(request.Body).Decode(&data); err != nil {
+			returnError(writer, util.LogStr("数据解析失败, 请刷新页面重试"))
+			return
+		}
+	} else {
+		index, err := strconv.Atoi(request.FormValue("index"))
+		if err != nil {
+			returnError(writer, util.LogStr("数据解析失败, 请刷新页面重试"))
+			return
+		}
+		data.Index = index
+		data.ID = request.FormValue("id")
+		data.Secret = request.FormValue("secret")
+	}
+
+	conf, err := config.GetConfigCached()
+	if err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+	if err = conf.SetCredentials(data.Index-1, strings.TrimSpace(data.ID), strings.TrimSpace(data.Secret)); err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+	if err = conf.SaveConfig(); err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+
+	util.Log("%q 修改了第 %s 个配置的凭证, 将在下次更新时使用", util.GetRequestIPStr(request), util.Ordinal(data.Index, conf.Lang))
+	returnOK(writer, "ok", map[string]int{"index": data.Index})
+}
@@ -0,0 +1,163 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/dns"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// diagnosticsData 诊断信息, 不包含密钥及命令等敏感信息
+type diagnosticsData struct {
+	Version    string               `json:"version"`
+	OS         string               `json:"os"`
+	Arch       string               `json:"arch"`
+	GoVersion  string               `json:"goVersion"`
+	Docker     bool                 `json:"docker"`
+	Container  string               `json:"container,omitempty"`
+	ConfigFile string               `json:"configFile"`
+	DNS        string               `json:"dns"`
+	Paused     bool                 `json:"paused"`
+	LastRun    *time.Time           `json:"lastRun,omitempty"`
+	LastResult string               `json:"lastResult,omitempty"`
+	IPv4URL    string               `json:"ipv4URL,omitempty"`
+	IPv6URL    string               `json:"ipv6URL,omitempty"`
+	DnsConf    []diagnosticsDnsConf `json:"dnsConf"`
+}
+
+// diagnosticsDnsConf 单个DNS配置的诊断信息
+type diagnosticsDnsConf struct {
+	Name     string          `json:"name,omitempty"`
+	Provider string          `json:"provider"`
+	Ipv4     diagnosticsAddr `json:"ipv4"`
+	Ipv6     diagnosticsAddr `json:"ipv6"`
+}
+
+// diagnosticsAddr IPv4/IPv6 的获取方式及最近一次获取到的地址
+type diagnosticsAddr struct {
+	Enable  bool   `json:"enable"`
+	GetType string `json:"getType,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Addr    string `json:"addr,omitempty"`
+	// 最近一次结果的来源, fresh: 已与服务商比对, cached: 使用缓存, pending: 等待IP稳定
+	Provenance string `json:"provenance,omitempty"`
+}
+
+// Diagnostics 返回运行环境及获取IP的诊断信息, 用于排查问题
+// 默认返回纯文本, 通过 ?format=json 返回json
+func Diagnostics(writer http.ResponseWriter, request *http.Request) {
+	conf, _ := config.GetConfigCached()
+	runStatus := dns.GetStatus()
+
+	data := diagnosticsData{
+		Version:    os.Getenv(util.VersionENV),
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+		GoVersion:  runtime.Version(),
+		Docker:     util.IsRunInDocker(),
+		Container:  util.DetectContainer(),
+		ConfigFile: util.GetConfigFilePath(),
+		DNS:        util.GetDNS(),
+		Paused:     conf.Paused,
+		LastResult: runStatus.LastResult,
+		IPv4URL:    util.ScrubSecrets(util.LastIPURL("IPv4")),
+		IPv6URL:    util.ScrubSecrets(util.LastIPURL("IPv6")),
+		DnsConf:    []diagnosticsDnsConf{},
+	}
+	if config.IsEnvOnly() {
+		data.ConfigFile = "env"
+	}
+	if data.DNS == "" {
+		data.DNS = "system"
+	}
+	if !runStatus.LastRun.IsZero() {
+		data.LastRun = &runStatus.LastRun
+	}
+
+	for i, dc := range conf.DnsConf {
+		ipv4Addr, ipv6Addr := dns.DetectedIpAddr(i)
+		ipv4Provenance, ipv6Provenance := dns.DetectedIpProvenance(i)
+		data.DnsConf = append(data.DnsConf, diagnosticsDnsConf{
+			Name:     dc.Name,
+			Provider: dc.DNS.Name,
+			Ipv4: diagnosticsAddr{
+				Enable:     dc.Ipv4.Enable,
+				GetType:    dc.Ipv4.GetType,
+				Source:     diagnosticsSource(dc.Ipv4.GetType, dc.Ipv4.URL, dc.Ipv4.NetInterface),
+				Addr:       ipv4Addr,
+				Provenance: ipv4Provenance,
+			},
+			Ipv6: diagnosticsAddr{
+				Enable:     dc.Ipv6.Enable,
+				GetType:    dc.Ipv6.GetType,
+				Source:     diagnosticsSource(dc.Ipv6.GetType, dc.Ipv6.URL, dc.Ipv6.NetInterface),
+				Addr:       ipv6Addr,
+				Provenance: ipv6Provenance,
+			},
+		})
+	}
+
+	if request.URL.Query().Get("format") == "json" {
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(data)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	writer.Write([]byte(data.String()))
+}
+
+// diagnosticsSource 获取IP的来源, 命令可能包含密钥, 不返回
+func diagnosticsSource(getType, url, netInterface string) string {
+	switch getType {
+	case "url":
+		return util.ScrubSecrets(url)
+	case "netInterface":
+		return netInterface
+	}
+	return ""
+}
+
+// String 纯文本格式, 方便复制到 issue 中
+func (data diagnosticsData) String() string {
+	var sb strings.Builder
+	line := func(key string, value any) {
+		fmt.Fprintf(&sb, "%-12s %v\n", key+":", value)
+	}
+	line("version", data.Version)
+	line("os/arch", data.OS+"/"+data.Arch)
+	line("go", data.GoVersion)
+	line("docker", data.Docker)
+	if data.Container != "" {
+		line("container", data.Container)
+	}
+	line("config", data.ConfigFile)
+	line("dns", data.DNS)
+	line("paused", data.Paused)
+	if data.LastRun != nil {
+		line("lastRun", data.LastRun.Format(time.RFC3339)+" "+data.LastResult)
+	}
+	line("ipv4URL", data.IPv4URL)
+	line("ipv6URL", data.IPv6URL)
+	for i, dc := range data.DnsConf {
+		fmt.Fprintf(&sb, "\n[%d] %s %s\n", i, dc.Provider, dc.Name)
+		for _, a := range []struct {
+			key  string
+			addr diagnosticsAddr
+		}{{"ipv4", dc.Ipv4}, {"ipv6", dc.Ipv6}} {
+			if !a.addr.Enable {
+				line(a.key, "disabled")
+				continue
+			}
+			line(a.key, strings.Join(strings.Fields(fmt.Sprintf("%s %s %s %s", a.addr.GetType, a.addr.Source, a.addr.Addr, a.addr.Provenance)), " "))
+		}
+	}
+	return sb.String()
+}
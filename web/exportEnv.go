@@ -0,0 +1,21 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+)
+
+// ExportEnv 将配置导出为环境变量, redact=false 时包含密钥
+func ExportEnv(writer http.ResponseWriter, request *http.Request) {
+	conf, err := config.GetConfigCached()
+	if err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+	if request.URL.Query().Get("redact") != "false" {
+		conf = conf.Redacted()
+	}
+	returnOK(writer, "ok", strings.Join(conf.ToEnv(), "\n"))
+}
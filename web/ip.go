@@ -0,0 +1,37 @@
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/dns"
+)
+
+// IP 返回最近一次获取到的IPv4/IPv6, 不会触发重新获取
+func IP(writer http.ResponseWriter, request *http.Request) {
+	conf, _ := config.GetConfigCached()
+	if !conf.IPEndpoint {
+		http.NotFound(writer, request)
+		return
+	}
+
+	ipv4, ipv6 := dns.LastIpAddr()
+
+	// 通过 ?format=json 或 Accept: application/json 返回json
+	if request.URL.Query().Get("format") == "json" ||
+		strings.Contains(request.Header.Get("Accept"), "application/json") {
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(map[string]string{"ipv4": ipv4, "ipv6": ipv6})
+		return
+	}
+
+	// 默认返回纯文本, 一行一个IP
+	writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	for _, ip := range []string{ipv4, ipv6} {
+		if ip != "" {
+			writer.Write([]byte(ip + "\n"))
+		}
+	}
+}
@@ -22,6 +22,9 @@ var cookieName = "token"
 // CookieInSystem only one cookie
 var cookieInSystem = &http.Cookie{}
 
+// readOnlyCookieInSystem 只读用户的cookie
+var readOnlyCookieInSystem = &http.Cookie{}
+
 // 服务启动时间
 var startTime = time.Now()
 
@@ -48,9 +51,11 @@ func Login(writer http.ResponseWriter, request *http.Request) {
 	conf, _ := config.GetConfigCached()
 
 	err = tmpl.Execute(writer, struct {
-		EmptyUser bool // 未填写用户名和密码
+		EmptyUser bool   // 未填写用户名和密码
+		Title     string // 页面标题
 	}{
 		EmptyUser: conf.Username == "" && conf.Password == "",
+		Title:     conf.GetTitle(),
 	})
 	if err != nil {
 		fmt.Println("Error happened..")
@@ -73,6 +78,7 @@ func LoginFunc(w http.ResponseWriter, r *http.Request) {
 	var data struct {
 		Username string `json:"Username"`
 		Password string `json:"Password"`
+		Remember bool   `json:"Remember"`
 	}
 
 	err := json.NewDecoder(r.Body).Decode(&data)
@@ -114,31 +120,36 @@ func LoginFunc(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// 登录
-	if data.Username == conf.Username && util.PasswordOK(conf.Password, data.Password) {
+	if userRole := getUserRole(&conf.User, data.Username, data.Password); userRole != roleNone {
 		ld.ticker.Stop()
 		ld.failedTimes = 0
 
-		// 设置cookie过期时间为1天
-		timeoutDays := 1
-		if conf.NotAllowWanAccess {
-			// 内网访问cookie过期时间为30天
-			timeoutDays = 30
-		}
-
 		// 覆盖cookie
-		cookieInSystem = &http.Cookie{
+		newCookie := &http.Cookie{
 			Name:     cookieName,
 			Value:    util.GenerateToken(data.Username), // 生成token
 			Path:     "/",
-			Expires:  time.Now().AddDate(0, 0, timeoutDays), // 设置过期时间
+			Expires:  time.Now().Add(conf.User.GetSessionTimeout(data.Remember)), // 设置过期时间
 			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		}
+		if userRole == roleReadOnly {
+			readOnlyCookieInSystem = newCookie
+			util.Log("%q 以只读用户登录", util.GetRequestIPStr(r))
+		} else {
+			cookieInSystem = newCookie
+		}
+		// 写入cookie, 未勾选记住我时为会话cookie, 关闭浏览器后需重新登录
+		cookie := *newCookie
+		if !data.Remember {
+			cookie.Expires = time.Time{}
 		}
-		// 写入cookie
-		http.SetCookie(w, cookieInSystem)
+		http.SetCookie(w, &cookie)
 
 		util.Log("%q 登录成功", util.GetRequestIPStr(r))
 
-		returnOK(w, util.LogStr("登录成功"), cookieInSystem.Value)
+		returnOK(w, util.LogStr("登录成功"), newCookie.Value)
 		return
 	}
 
@@ -6,8 +6,8 @@ import (
 )
 
 func Logout(w http.ResponseWriter, r *http.Request) {
-	// 覆盖cookieInSystem
-	cookieInSystem = &http.Cookie{
+	// 过期的 Cookie
+	expiredCookie := &http.Cookie{
 		Name:     cookieName,
 		Value:    "",
 		Path:     "/",
@@ -15,8 +15,14 @@ func Logout(w http.ResponseWriter, r *http.Request) {
 		MaxAge:   -1,              // 立即删除该 Cookie
 		HttpOnly: true,
 	}
+	// 只读用户退出时不影响管理员
+	if getCookieRole(r) == roleReadOnly {
+		readOnlyCookieInSystem = expiredCookie
+	} else {
+		cookieInSystem = expiredCookie
+	}
 	// 设置过期的 Cookie
-	http.SetCookie(w, cookieInSystem)
+	http.SetCookie(w, expiredCookie)
 
 	// 重定向用户到登录页面
 	http.Redirect(w, r, "./login", http.StatusFound)
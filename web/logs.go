@@ -2,29 +2,81 @@ package web
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"sync"
+	"time"
 )
 
 // MemoryLogs 内存中的日志
 type MemoryLogs struct {
-	MaxNum int      // 保存最大条数
-	Logs   []string // 日志
+	MaxNum      int      // 保存最大条数
+	Logs        []string // 日志
+	lock        sync.Mutex
+	subscribers map[chan string]struct{} // 实时日志的订阅者
 }
 
 func (mlogs *MemoryLogs) Write(p []byte) (n int, err error) {
-	mlogs.Logs = append(mlogs.Logs, string(p))
+	mlogs.lock.Lock()
+	defer mlogs.lock.Unlock()
+
+	line := string(p)
+	mlogs.Logs = append(mlogs.Logs, line)
 	// 处理日志数量
 	if len(mlogs.Logs) > mlogs.MaxNum {
 		mlogs.Logs = mlogs.Logs[len(mlogs.Logs)-mlogs.MaxNum:]
 	}
+	// 推送给订阅者, 订阅者处理不及时则丢弃
+	for ch := range mlogs.subscribers {
+		select {
+		case ch <- line:
+		default:
+		}
+	}
 	return len(p), nil
 }
 
+// subscribe 订阅新日志
+func (mlogs *MemoryLogs) subscribe() chan string {
+	mlogs.lock.Lock()
+	defer mlogs.lock.Unlock()
+
+	ch := make(chan string, 64)
+	if mlogs.subscribers == nil {
+		mlogs.subscribers = make(map[chan string]struct{})
+	}
+	mlogs.subscribers[ch] = struct{}{}
+	return ch
+}
+
+// unsubscribe 取消订阅
+func (mlogs *MemoryLogs) unsubscribe(ch chan string) {
+	mlogs.lock.Lock()
+	defer mlogs.lock.Unlock()
+	delete(mlogs.subscribers, ch)
+}
+
+// maxLogNum 内存中日志的最大条数上限, 防止占用过多内存
+const maxLogNum = 10000
+
 var mlogs = &MemoryLogs{MaxNum: 50}
 
+// SetMaxLogs 设置内存中保存的日志条数, 不超过 maxLogNum
+func SetMaxLogs(num int) {
+	if num <= 0 {
+		return
+	}
+	if num > maxLogNum {
+		num = maxLogNum
+	}
+	mlogs.lock.Lock()
+	defer mlogs.lock.Unlock()
+	mlogs.MaxNum = num
+}
+
 // 初始化日志
 func init() {
 	log.SetOutput(io.MultiWriter(mlogs, os.Stdout))
@@ -33,12 +85,50 @@ func init() {
 
 // Logs web
 func Logs(writer http.ResponseWriter, request *http.Request) {
+	mlogs.lock.Lock()
 	// mlogs.Logs数组转为json
 	logs, _ := json.Marshal(mlogs.Logs)
+	mlogs.lock.Unlock()
 	writer.Write(logs)
 }
 
+// LogsStream 通过 SSE 实时推送新日志
+func LogsStream(writer http.ResponseWriter, request *http.Request) {
+	flusher, ok := writer.(http.Flusher)
+	if !ok {
+		http.Error(writer, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.Header().Set("Cache-Control", "no-cache")
+	writer.Header().Set("Connection", "keep-alive")
+	flusher.Flush()
+
+	ch := mlogs.subscribe()
+	defer mlogs.unsubscribe(ch)
+
+	// 定时发送注释, 防止被代理断开
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case line := <-ch:
+			data, _ := json.Marshal(line)
+			fmt.Fprintf(writer, "data: %s\n\n", data)
+			flusher.Flush()
+		case <-ticker.C:
+			fmt.Fprint(writer, ": ping\n\n")
+			flusher.Flush()
+		case <-request.Context().Done():
+			return
+		}
+	}
+}
+
 // ClearLog
 func ClearLog(writer http.ResponseWriter, request *http.Request) {
+	mlogs.lock.Lock()
+	defer mlogs.lock.Unlock()
 	mlogs.Logs = mlogs.Logs[:0]
 }
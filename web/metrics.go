@@ -0,0 +1,13 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/jeessy2/ddns-go/v6/dns"
+)
+
+// Metrics 以 Prometheus 文本格式返回DNS服务商接口的耗时、错误数及域名的更新结果
+func Metrics(writer http.ResponseWriter, request *http.Request) {
+	writer.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	dns.WriteMetrics(writer)
+}
@@ -0,0 +1,45 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/dns"
+	"github.com/jeessy2/ddns-go/v6/util"
+)
+
+// Pause 暂停或恢复所有更新, 参数 paused=true/false
+// 恢复后立即更新一次
+func Pause(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	paused, err := strconv.ParseBool(request.FormValue("paused"))
+	if err != nil {
+		returnError(writer, util.LogStr("数据解析失败, 请刷新页面重试"))
+		return
+	}
+
+	conf, err := config.GetConfigCached()
+	if err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+	conf.Paused = paused
+	if err = conf.SaveConfig(); err != nil {
+		returnError(writer, err.Error())
+		return
+	}
+
+	if paused {
+		util.Log("%q 暂停了所有更新", util.GetRequestIPStr(request))
+	} else {
+		util.Log("%q 恢复了所有更新", util.GetRequestIPStr(request))
+		util.ForceCompareGlobal = true
+		go dns.RunOnce()
+	}
+	returnOK(writer, "ok", map[string]bool{"paused": paused})
+}
@@ -3,6 +3,7 @@ package web
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/jeessy2/ddns-go/v6/config"
@@ -10,38 +11,79 @@ import (
 	"github.com/jeessy2/ddns-go/v6/util"
 )
 
-// Save 保存
+// Save 保存, 校验失败时 errors 为各配置项的错误, 页面据此标记对应的输入框; warnings 为保存成功但需注意的提示, 如重复的域名
 func Save(writer http.ResponseWriter, request *http.Request) {
-	result := checkAndSave(request)
+	result, errs := checkAndSave(request)
 	dnsConfJsonStr := "[]"
+	var warnings []string
 	if result == "ok" {
 		conf, _ := config.GetConfigCached()
 		dnsConfJsonStr = getDnsConfStr(conf.DnsConf)
+		warnings = conf.DuplicateDomainWarnings()
+		for _, w := range warnings {
+			util.Log("%s", w)
+		}
 	}
-	byt, _ := json.Marshal(map[string]string{"result": result, "dnsConf": dnsConfJsonStr})
+	byt, _ := json.Marshal(struct {
+		Result   string                  `json:"result"`
+		DnsConf  string                  `json:"dnsConf"`
+		Errors   config.ValidationErrors `json:"errors,omitempty"`
+		Warnings []string                `json:"warnings,omitempty"`
+	}{result, dnsConfJsonStr, errs, warnings})
 
 	writer.Write(byt)
 }
 
-func checkAndSave(request *http.Request) string {
+func checkAndSave(request *http.Request) (string, config.ValidationErrors) {
 	conf, _ := config.GetConfigCached()
+	// 保存前的配置, 用于判断是否只修改了凭证
+	old := conf
 
 	// 从请求中读取 JSON 数据
 	var data struct {
-		Username           string       `json:"Username"`
-		Password           string       `json:"Password"`
-		NotAllowWanAccess  bool         `json:"NotAllowWanAccess"`
-		WebhookURL         string       `json:"WebhookURL"`
-		WebhookRequestBody string       `json:"WebhookRequestBody"`
-		WebhookHeaders     string       `json:"WebhookHeaders"`
-		DnsConf            []dnsConf4JS `json:"DnsConf"`
+		Username              string       `json:"Username"`
+		Password              string       `json:"Password"`
+		SessionTimeout        string       `json:"SessionTimeout"`
+		ReadOnlyUsername      string       `json:"ReadOnlyUsername"`
+		ReadOnlyPassword      string       `json:"ReadOnlyPassword"`
+		BasicAuth             bool         `json:"BasicAuth"`
+		BasicAuthUsername     string       `json:"BasicAuthUsername"`
+		BasicAuthPassword     string       `json:"BasicAuthPassword"`
+		NotAllowWanAccess     bool         `json:"NotAllowWanAccess"`
+		IPEndpoint            bool         `json:"IPEndpoint"`
+		FirstCycleDryRun      bool         `json:"FirstCycleDryRun"`
+		DuplicateDomains      string       `json:"DuplicateDomains"`
+		MaintenanceWindow     string       `json:"MaintenanceWindow"`
+		MaintenanceTimezone   string       `json:"MaintenanceTimezone"`
+		Title                 string       `json:"Title"`
+		Favicon               string       `json:"Favicon"`
+		WebhookURL            string       `json:"WebhookURL"`
+		WebhookRequestBody    string       `json:"WebhookRequestBody"`
+		WebhookHeaders        string       `json:"WebhookHeaders"`
+		WebhookSecret         string       `json:"WebhookSecret"`
+		WebhookNotifyStartup  bool         `json:"WebhookNotifyStartup"`
+		WebhookNotifyShutdown bool         `json:"WebhookNotifyShutdown"`
+		WebhookSuccessCodes   string       `json:"WebhookSuccessCodes"`
+		NotifyTemplate        string       `json:"NotifyTemplate"`
+		PushoverToken         string       `json:"PushoverToken"`
+		PushoverUser          string       `json:"PushoverUser"`
+		GotifyURL             string       `json:"GotifyURL"`
+		GotifyToken           string       `json:"GotifyToken"`
+		MatrixHomeserver      string       `json:"MatrixHomeserver"`
+		MatrixAccessToken     string       `json:"MatrixAccessToken"`
+		MatrixRoomID          string       `json:"MatrixRoomID"`
+		ServerChanSendKey     string       `json:"ServerChanSendKey"`
+		PushDeerKey           string       `json:"PushDeerKey"`
+		PostUpdateCmd         string       `json:"PostUpdateCmd"`
+		DnsConf               []dnsConf4JS `json:"DnsConf"`
 	}
 
 	// 解析请求中的 JSON 数据
 	err := json.NewDecoder(request.Body).Decode(&data)
 	if err != nil {
-		return util.LogStr("数据解析失败, 请刷新页面重试")
+		return util.LogStr("数据解析失败, 请刷新页面重试"), nil
 	}
+	var errs config.ValidationErrors
 	usernameNew := strings.TrimSpace(data.Username)
 	passwordNew := data.Password
 
@@ -49,28 +91,103 @@ func checkAndSave(request *http.Request) string {
 	accept := request.Header.Get("Accept-Language")
 	conf.Lang = util.InitLogLang(accept)
 
+	// 登录有效期, 为空使用默认值
+	conf.User.SessionTimeout = 0
+	if sessionTimeout := strings.TrimSpace(data.SessionTimeout); sessionTimeout != "" {
+		timeout, err := strconv.Atoi(sessionTimeout)
+		if err != nil || timeout < 0 {
+			errs.Add(-1, "SessionTimeout", util.LogStr("登录有效期不正确"))
+		}
+		conf.User.SessionTimeout = timeout
+	}
+
 	conf.NotAllowWanAccess = data.NotAllowWanAccess
+	conf.IPEndpoint = data.IPEndpoint
+	conf.FirstCycleDryRun = data.FirstCycleDryRun
+	conf.DuplicateDomains = data.DuplicateDomains
+	conf.MaintenanceWindow = strings.TrimSpace(data.MaintenanceWindow)
+	conf.MaintenanceTimezone = strings.TrimSpace(data.MaintenanceTimezone)
+	conf.Title = strings.TrimSpace(data.Title)
+	conf.Favicon = strings.TrimSpace(data.Favicon)
 	conf.WebhookURL = strings.TrimSpace(data.WebhookURL)
 	conf.WebhookRequestBody = strings.TrimSpace(data.WebhookRequestBody)
 	conf.WebhookHeaders = strings.TrimSpace(data.WebhookHeaders)
+	// 未修改时保持原值
+	if data.WebhookSecret != secretPlaceholder {
+		conf.WebhookSecret = strings.TrimSpace(data.WebhookSecret)
+	}
+	conf.WebhookNotifyStartup = data.WebhookNotifyStartup
+	conf.WebhookNotifyShutdown = data.WebhookNotifyShutdown
+	conf.WebhookSuccessCodes = strings.TrimSpace(data.WebhookSuccessCodes)
+	conf.NotifyTemplate = strings.TrimSpace(data.NotifyTemplate)
+	conf.PostUpdateCmd = strings.TrimSpace(data.PostUpdateCmd)
+	conf.PushoverUser = strings.TrimSpace(data.PushoverUser)
+	conf.GotifyURL = strings.TrimSpace(data.GotifyURL)
+	conf.MatrixHomeserver = strings.TrimSpace(data.MatrixHomeserver)
+	conf.MatrixRoomID = strings.TrimSpace(data.MatrixRoomID)
+	if data.PushoverToken != secretPlaceholder {
+		conf.PushoverToken = strings.TrimSpace(data.PushoverToken)
+	}
+	if data.GotifyToken != secretPlaceholder {
+		conf.GotifyToken = strings.TrimSpace(data.GotifyToken)
+	}
+	if data.MatrixAccessToken != secretPlaceholder {
+		conf.MatrixAccessToken = strings.TrimSpace(data.MatrixAccessToken)
+	}
+	if data.ServerChanSendKey != secretPlaceholder {
+		conf.ServerChanSendKey = strings.TrimSpace(data.ServerChanSendKey)
+	}
+	if data.PushDeerKey != secretPlaceholder {
+		conf.PushDeerKey = strings.TrimSpace(data.PushDeerKey)
+	}
 
 	// 如果新密码不为空则检查是否够强, 内/外网要求强度不同
 	conf.Username = usernameNew
 	if passwordNew != "" {
 		hashedPwd, err := conf.CheckPassword(passwordNew)
 		if err != nil {
-			return err.Error()
+			errs.Add(-1, "Password", err.Error())
+		} else {
+			conf.Password = hashedPwd
 		}
-		conf.Password = hashedPwd
 	}
 
 	// 帐号密码不能为空
-	if conf.Username == "" || conf.Password == "" {
-		return util.LogStr("必须输入用户名/密码")
+	if conf.Username == "" {
+		errs.Add(-1, "Username", util.LogStr("必须输入用户名/密码"))
+	} else if conf.Password == "" && passwordNew == "" {
+		errs.Add(-1, "Password", util.LogStr("必须输入用户名/密码"))
+	}
+
+	// 只读用户, 用户名为空时删除
+	conf.ReadOnlyUsername = strings.TrimSpace(data.ReadOnlyUsername)
+	if conf.ReadOnlyUsername == "" {
+		conf.ReadOnlyPassword = ""
+	} else if data.ReadOnlyPassword != "" {
+		hashedPwd, err := conf.CheckPassword(data.ReadOnlyPassword)
+		if err != nil {
+			errs.Add(-1, "ReadOnlyPassword", err.Error())
+		} else {
+			conf.ReadOnlyPassword = hashedPwd
+		}
+	}
+
+	// Basic认证, 密码为空时不修改
+	conf.BasicAuth = data.BasicAuth
+	conf.BasicAuthUsername = strings.TrimSpace(data.BasicAuthUsername)
+	if data.BasicAuthPassword != "" {
+		hashedPwd, err := conf.CheckPassword(data.BasicAuthPassword)
+		if err != nil {
+			errs.Add(-1, "BasicAuthPassword", err.Error())
+		} else {
+			conf.BasicAuthPassword = hashedPwd
+		}
 	}
 
 	dnsConfFromJS := data.DnsConf
 	var dnsConfArray []config.DnsConfig
+	// 保存的第 i 个配置在页面中的序号, 跳过了空配置
+	var jsIndex []int
 	empty := dnsConf4JS{}
 	for k, v := range dnsConfFromJS {
 		if v == empty {
@@ -81,6 +198,53 @@ func checkAndSave(request *http.Request) string {
 		dnsConf.DNS.Name = v.DnsName
 		dnsConf.DNS.ID = strings.TrimSpace(v.DnsID)
 		dnsConf.DNS.Secret = strings.TrimSpace(v.DnsSecret)
+		dnsConf.DNS.UserAgent = strings.TrimSpace(v.DnsUserAgent)
+		dnsConf.DNS.Proxy = strings.TrimSpace(v.DnsProxy)
+		dnsConf.DNS.Endpoint = strings.TrimSpace(v.DnsEndpoint)
+		dnsConf.DNS.Method = v.DnsMethod
+		dnsConf.DNS.Headers = strings.TrimSpace(v.DnsHeaders)
+		dnsConf.DNS.SuccessMatch = strings.TrimSpace(v.DnsSuccessMatch)
+
+		// 缓存次数, 为空使用全局配置
+		if cacheTimes := strings.TrimSpace(v.CacheTimes); cacheTimes != "" {
+			times, err := strconv.Atoi(cacheTimes)
+			if err != nil || times < 0 {
+				errs.Add(k, "CacheTimes", util.LogStr("第 %s 个配置的缓存次数不正确", util.Ordinal(k+1, conf.Lang)))
+			} else {
+				dnsConf.CacheTimes = times
+			}
+		}
+
+		// 稳定次数, 为空立即更新
+		if stableTimes := strings.TrimSpace(v.StableTimes); stableTimes != "" {
+			times, err := strconv.Atoi(stableTimes)
+			if err != nil || times < 0 {
+				errs.Add(k, "StableTimes", util.LogStr("第 %s 个配置的稳定次数不正确", util.Ordinal(k+1, conf.Lang)))
+			} else {
+				dnsConf.StableTimes = times
+			}
+		}
+
+		// 更新间隔, 为空使用全局的 -f
+		if interval := strings.TrimSpace(v.Interval); interval != "" {
+			seconds, err := strconv.Atoi(interval)
+			if err != nil || seconds < 0 {
+				errs.Add(k, "Interval", util.LogStr("第 %s 个配置的更新间隔不正确", util.Ordinal(k+1, conf.Lang)))
+			} else {
+				dnsConf.Interval = seconds
+			}
+		}
+
+		dnsConf.ManagedZone = v.ManagedZone
+		dnsConf.ManagedZoneID = strings.TrimSpace(v.ManagedZoneID)
+		dnsConf.NoIPAction = v.NoIPAction
+		dnsConf.RequireInterfaceUp = v.RequireIfaceUp
+		dnsConf.VerifyDNS = strings.TrimSpace(v.VerifyDNS)
+		dnsConf.CompareDNS = strings.TrimSpace(v.CompareDNS)
+		dnsConf.KeepCase = v.KeepCase
+		dnsConf.NoUnderscore = v.NoUnderscore
+		dnsConf.OnlyChanged = v.OnlyChanged
+		dnsConf.UpdateOnly = v.UpdateOnly
 
 		if v.Ipv4Domains == "" && v.Ipv6Domains == "" {
 			util.Log("第 %s 个配置未填写域名", util.Ordinal(k+1, conf.Lang))
@@ -99,6 +263,9 @@ func checkAndSave(request *http.Request) string {
 		dnsConf.Ipv6.NetInterface = v.Ipv6NetInterface
 		dnsConf.Ipv6.Cmd = strings.TrimSpace(v.Ipv6Cmd)
 		dnsConf.Ipv6.Ipv6Reg = strings.TrimSpace(v.Ipv6Reg)
+		dnsConf.Ipv6.ScopeOrder = strings.TrimSpace(v.Ipv6ScopeOrder)
+		dnsConf.Ipv6.PrefixFile = strings.TrimSpace(v.Ipv6PrefixFile)
+		dnsConf.Ipv6.PrefixSuffix = strings.TrimSpace(v.Ipv6PrefixSuffix)
 		dnsConf.Ipv6.Domains = util.SplitLines(v.Ipv6Domains)
 
 		if k < len(conf.DnsConf) {
@@ -113,19 +280,36 @@ func checkAndSave(request *http.Request) string {
 		}
 
 		dnsConfArray = append(dnsConfArray, dnsConf)
+		jsIndex = append(jsIndex, k)
 	}
 	conf.DnsConf = dnsConfArray
 
+	// 与导入及 -check 共用校验, 序号转换为页面中的序号
+	for _, e := range conf.Validate() {
+		if e.Index >= 0 {
+			e.Index = jsIndex[e.Index]
+		}
+		errs = append(errs, e)
+	}
+	if len(errs) > 0 {
+		return errs.Error(), errs
+	}
+
 	// 保存到用户目录
 	err = conf.SaveConfig()
 
-	// 只运行一次
-	util.ForceCompareGlobal = true
-	go dns.RunOnce()
+	if err == nil && config.CredentialsOnlyChanged(&old, &conf) {
+		// 只修改了凭证, 保留IP缓存, 下次更新时使用新凭证
+		util.Log("只修改了DNS服务商的凭证, 将在下次更新时使用")
+	} else {
+		// 只运行一次
+		util.ForceCompareGlobal = true
+		go dns.RunOnce()
+	}
 
 	// 回写错误信息
 	if err != nil {
-		return err.Error()
+		return err.Error(), nil
 	}
-	return "ok"
+	return "ok", nil
 }
@@ -0,0 +1,48 @@
+package web
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/dns"
+)
+
+// statusData 运行状态, 时间未知时省略
+type statusData struct {
+	Paused     bool       `json:"paused"`
+	LastRun    *time.Time `json:"lastRun,omitempty"`
+	LastResult string     `json:"lastResult,omitempty"`
+	Ipv4Result string     `json:"ipv4Result,omitempty"`
+	Ipv6Result string     `json:"ipv6Result,omitempty"`
+	// 结果来源, fresh: 已与服务商比对, cached: 使用缓存, pending: 等待IP稳定, mixed: 多个配置不同
+	Ipv4Provenance string     `json:"ipv4Provenance,omitempty"`
+	Ipv6Provenance string     `json:"ipv6Provenance,omitempty"`
+	NextRun        *time.Time `json:"nextRun,omitempty"`
+	// 标签及带有该标签的域名
+	Tags map[string][]string `json:"tags,omitempty"`
+}
+
+// Status 返回运行状态
+func Status(writer http.ResponseWriter, request *http.Request) {
+	conf, _ := config.GetConfigCached()
+	runStatus := dns.GetStatus()
+	data := statusData{
+		Paused:         conf.Paused,
+		LastResult:     runStatus.LastResult,
+		Ipv4Result:     runStatus.LastIpv4Result,
+		Ipv6Result:     runStatus.LastIpv6Result,
+		Ipv4Provenance: runStatus.LastIpv4Provenance,
+		Ipv6Provenance: runStatus.LastIpv6Provenance,
+	}
+	if tags := conf.DomainTags(); len(tags) > 0 {
+		data.Tags = tags
+	}
+	if !runStatus.LastRun.IsZero() {
+		data.LastRun = &runStatus.LastRun
+	}
+	if !runStatus.NextRun.IsZero() {
+		data.NextRun = &runStatus.NextRun
+	}
+	returnOK(writer, "ok", data)
+}
@@ -7,23 +7,42 @@ import (
 	"html/template"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/jeessy2/ddns-go/v6/config"
+	"github.com/jeessy2/ddns-go/v6/util"
 )
 
 //go:embed writing.html
 var writingEmbedFile embed.FS
 
-const VersionEnv = "DDNS_GO_VERSION"
-
 // js中的dns配置
 type dnsConf4JS struct {
 	Name             string
 	DnsName          string
 	DnsID            string
 	DnsSecret        string
+	DnsUserAgent     string
+	DnsProxy         string
+	DnsEndpoint      string
+	DnsMethod        string
+	DnsHeaders       string
+	DnsSuccessMatch  string
 	TTL              string
+	CacheTimes       string
+	StableTimes      string
+	Interval         string
+	ManagedZone      bool
+	ManagedZoneID    string
+	NoIPAction       string
+	RequireIfaceUp   bool
+	VerifyDNS        string
+	CompareDNS       string
+	KeepCase         bool
+	NoUnderscore     bool
+	OnlyChanged      bool
+	UpdateOnly       bool
 	Ipv4Enable       bool
 	Ipv4GetType      string
 	Ipv4Url          string
@@ -36,6 +55,9 @@ type dnsConf4JS struct {
 	Ipv6NetInterface string
 	Ipv6Cmd          string
 	Ipv6Reg          string
+	Ipv6ScopeOrder   string
+	Ipv6PrefixFile   string
+	Ipv6PrefixSuffix string
 	Ipv6Domains      string
 }
 
@@ -57,21 +79,63 @@ func Writing(writer http.ResponseWriter, request *http.Request) {
 	ipv4, ipv6, _ := config.GetNetInterface()
 
 	err = tmpl.Execute(writer, struct {
-		DnsConf           template.JS
-		NotAllowWanAccess bool
-		Username          string
+		DnsConf             template.JS
+		NotAllowWanAccess   bool
+		IPEndpoint          bool
+		FirstCycleDryRun    bool
+		DuplicateDomains    string
+		Paused              bool
+		MaintenanceWindow   string
+		MaintenanceTimezone string
+		PostUpdateCmd       string
+		Title               string
+		Favicon             string
+		Username            string
+		SessionTimeout      int
+		ReadOnlyUsername    string
+		BasicAuth           bool
+		BasicAuthUsername   string
+		ReadOnly            bool
 		config.Webhook
-		Version string
-		Ipv4    []config.NetInterface
-		Ipv6    []config.NetInterface
+		WebhookSecret string
+		config.Notify
+		PushoverToken     string
+		GotifyToken       string
+		MatrixAccessToken string
+		ServerChanSendKey string
+		PushDeerKey       string
+		Version           string
+		Ipv4              []config.NetInterface
+		Ipv6              []config.NetInterface
 	}{
-		DnsConf:           template.JS(getDnsConfStr(conf.DnsConf)),
-		NotAllowWanAccess: conf.NotAllowWanAccess,
-		Username:          conf.User.Username,
-		Webhook:           conf.Webhook,
-		Version:           os.Getenv(VersionEnv),
-		Ipv4:              ipv4,
-		Ipv6:              ipv6,
+		DnsConf:             template.JS(getDnsConfStr(conf.DnsConf)),
+		NotAllowWanAccess:   conf.NotAllowWanAccess,
+		IPEndpoint:          conf.IPEndpoint,
+		FirstCycleDryRun:    conf.FirstCycleDryRun,
+		DuplicateDomains:    conf.DuplicateDomains,
+		Paused:              conf.Paused,
+		MaintenanceWindow:   conf.MaintenanceWindow,
+		MaintenanceTimezone: conf.MaintenanceTimezone,
+		PostUpdateCmd:       conf.PostUpdateCmd,
+		Title:               conf.Title,
+		Favicon:             conf.Favicon,
+		Username:            conf.User.Username,
+		SessionTimeout:      conf.User.SessionTimeout,
+		ReadOnlyUsername:    conf.User.ReadOnlyUsername,
+		BasicAuth:           conf.User.BasicAuth,
+		BasicAuthUsername:   conf.User.BasicAuthUsername,
+		ReadOnly:            getCookieRole(request) == roleReadOnly,
+		Webhook:             conf.Webhook,
+		WebhookSecret:       getHideSecret(conf.WebhookSecret),
+		Notify:              conf.Notify,
+		PushoverToken:       getHideSecret(conf.PushoverToken),
+		GotifyToken:         getHideSecret(conf.GotifyToken),
+		MatrixAccessToken:   getHideSecret(conf.MatrixAccessToken),
+		ServerChanSendKey:   getHideSecret(conf.ServerChanSendKey),
+		PushDeerKey:         getHideSecret(conf.PushDeerKey),
+		Version:             os.Getenv(util.VersionENV),
+		Ipv4:                ipv4,
+		Ipv6:                ipv6,
 	})
 	if err != nil {
 		fmt.Println("Error happened..")
@@ -84,12 +148,43 @@ func getDnsConfStr(dnsConf []config.DnsConfig) string {
 	for _, conf := range dnsConf {
 		// 已存在配置文件，隐藏真实的ID、Secret
 		idHide, secretHide := getHideIDSecret(&conf)
+		cacheTimes := ""
+		if conf.CacheTimes > 0 {
+			cacheTimes = strconv.Itoa(conf.CacheTimes)
+		}
+		stableTimes := ""
+		if conf.StableTimes > 0 {
+			stableTimes = strconv.Itoa(conf.StableTimes)
+		}
+		interval := ""
+		if conf.Interval > 0 {
+			interval = strconv.Itoa(conf.Interval)
+		}
 		dnsConfArray = append(dnsConfArray, dnsConf4JS{
 			Name:             conf.Name,
 			DnsName:          conf.DNS.Name,
 			DnsID:            idHide,
 			DnsSecret:        secretHide,
+			DnsUserAgent:     conf.DNS.UserAgent,
+			DnsProxy:         conf.DNS.Proxy,
+			DnsEndpoint:      conf.DNS.Endpoint,
+			DnsMethod:        conf.DNS.Method,
+			DnsHeaders:       conf.DNS.Headers,
+			DnsSuccessMatch:  conf.DNS.SuccessMatch,
 			TTL:              conf.TTL,
+			CacheTimes:       cacheTimes,
+			StableTimes:      stableTimes,
+			Interval:         interval,
+			ManagedZone:      conf.ManagedZone,
+			ManagedZoneID:    conf.ManagedZoneID,
+			NoIPAction:       conf.NoIPAction,
+			RequireIfaceUp:   conf.RequireInterfaceUp,
+			VerifyDNS:        conf.VerifyDNS,
+			CompareDNS:       conf.CompareDNS,
+			KeepCase:         conf.KeepCase,
+			NoUnderscore:     conf.NoUnderscore,
+			OnlyChanged:      conf.OnlyChanged,
+			UpdateOnly:       conf.UpdateOnly,
 			Ipv4Enable:       conf.Ipv4.Enable,
 			Ipv4GetType:      conf.Ipv4.GetType,
 			Ipv4Url:          conf.Ipv4.URL,
@@ -102,6 +197,9 @@ func getDnsConfStr(dnsConf []config.DnsConfig) string {
 			Ipv6NetInterface: conf.Ipv6.NetInterface,
 			Ipv6Cmd:          conf.Ipv6.Cmd,
 			Ipv6Reg:          conf.Ipv6.Ipv6Reg,
+			Ipv6ScopeOrder:   conf.Ipv6.ScopeOrder,
+			Ipv6PrefixFile:   conf.Ipv6.PrefixFile,
+			Ipv6PrefixSuffix: conf.Ipv6.PrefixSuffix,
 			Ipv6Domains:      strings.Join(conf.Ipv6.Domains, "\r\n"),
 		})
 	}
@@ -112,6 +210,17 @@ func getDnsConfStr(dnsConf []config.DnsConfig) string {
 // 显示的数量
 const displayCount int = 3
 
+// secretPlaceholder 已保存的Secret显示的占位符
+const secretPlaceholder = "********"
+
+// getHideSecret 隐藏Webhook签名密钥及通知服务的令牌
+func getHideSecret(secret string) string {
+	if secret != "" {
+		return secretPlaceholder
+	}
+	return ""
+}
+
 // hideIDSecret 隐藏真实的ID、Secret
 func getHideIDSecret(conf *config.DnsConfig) (idHide string, secretHide string) {
 	if len(conf.DNS.ID) > displayCount && conf.DNS.Name != "callback" {
@@ -119,8 +228,9 @@ func getHideIDSecret(conf *config.DnsConfig) (idHide string, secretHide string)
 	} else {
 		idHide = conf.DNS.ID
 	}
-	if len(conf.DNS.Secret) > displayCount && conf.DNS.Name != "callback" {
-		secretHide = conf.DNS.Secret[:displayCount] + strings.Repeat("*", len(conf.DNS.Secret)-displayCount)
+	// Secret 不显示任何字符, 未修改时保持原值
+	if conf.DNS.Secret != "" && conf.DNS.Name != "callback" {
+		secretHide = secretPlaceholder
 	} else {
 		secretHide = conf.DNS.Secret
 	}